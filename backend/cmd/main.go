@@ -17,11 +17,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	"lutexplorer/internal/api"
 	"lutexplorer/internal/bgloader"
+	"lutexplorer/internal/cli"
+	"lutexplorer/internal/lgs"
 	"lutexplorer/internal/lut"
 	"lutexplorer/internal/watcher"
 	"lutexplorer/internal/ws"
@@ -97,12 +100,18 @@ func generateAndSaveCert() (tls.Certificate, error) {
 }
 
 func main() {
+	// Headless subcommands (check, stats, simulate, ...) bypass the server entirely
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	libraryPath := flag.String("library", "", "Path to library folder (required)")
 	port := flag.Int("port", 7754, "Server port (HTTP)")
 	httpsPort := flag.Int("https-port", 7755, "HTTPS port (0 to disable)")
 	convexURL := flag.String("convex-url", "", "URL of the Convex Optimizer Python service (e.g., http://localhost:7756)")
 	watch := flag.Bool("watch", false, "Enable auto-reload when CSV lookup tables change")
 	noAutoloadBooks := flag.Bool("no-autoload-books", false, "Disable automatic loading of event books at startup")
+	noPersistSessions := flag.Bool("no-persist-sessions", false, "Keep LGS sessions in memory only (do not restore or save "+lgs.SessionsFile+")")
 	flag.Parse()
 
 	// Check environment variable for convex URL if not provided via flag
@@ -115,6 +124,7 @@ func main() {
 	if *libraryPath == "" {
 		fmt.Fprintln(os.Stderr, "Error: -library flag is required")
 		fmt.Fprintln(os.Stderr, "Usage: lutexplorer -library <path/to/library> [-port 7754] [-https-port 7755]")
+		fmt.Fprintln(os.Stderr, "       lutexplorer <command> -library <path/to/library> [flags]")
 		os.Exit(1)
 	}
 
@@ -188,6 +198,18 @@ func main() {
 	server.SetBackgroundLoader(bgLoader)
 	server.SetCSVWatcher(csvWatcher)
 
+	// Restore LGS sessions from the library folder so QA state survives restarts
+	if !*noPersistSessions {
+		sessionsPath := filepath.Join(*libraryPath, lgs.SessionsFile)
+		if err := server.EnableSessionPersistence(sessionsPath); err != nil {
+			log.Printf("Warning: LGS session persistence disabled: %v", err)
+		} else {
+			log.Printf("LGS sessions persisted to %s", sessionsPath)
+		}
+	} else {
+		log.Println("LGS session persistence disabled (sessions are lost on restart)")
+	}
+
 	// Log convex optimizer status
 	if *convexURL != "" {
 		log.Printf("Convex Optimizer proxy enabled: %s", *convexURL)
@@ -205,6 +227,9 @@ func main() {
 			csvWatcher.Stop()
 		}
 		bgLoader.Stop()
+		if err := server.Close(); err != nil {
+			log.Printf("Warning: failed to save LGS sessions: %v", err)
+		}
 		os.Exit(0)
 	}()
 
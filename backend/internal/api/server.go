@@ -2,18 +2,24 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
+	"strings"
 
 	"lutexplorer/internal/bgloader"
 	"lutexplorer/internal/common"
 	"lutexplorer/internal/convexopt"
 	"lutexplorer/internal/crowdsim"
+	"lutexplorer/internal/export"
+	"lutexplorer/internal/jobs"
 	"lutexplorer/internal/lgs"
 	"lutexplorer/internal/lut"
 	"lutexplorer/internal/optimizer"
+	"lutexplorer/internal/report"
 	"lutexplorer/internal/watcher"
 	"lutexplorer/internal/ws"
 
@@ -30,6 +36,8 @@ type Server struct {
 	crowdsimHandlers   *crowdsim.Handlers
 	optimizerHandlers  *optimizer.Handlers
 	convexoptHandlers  *convexopt.Handlers
+	jobs               *jobs.Manager
+	jobHandlers        *jobs.Handlers
 	wsHub              *ws.Hub
 	bgLoader           *bgloader.BackgroundLoader
 	csvWatcher         *watcher.FileWatcher
@@ -38,13 +46,16 @@ type Server struct {
 // NewServer creates a new API server.
 func NewServer(loader *lut.Loader, addr string, hub *ws.Hub, convexURL string) *Server {
 	sessions := lgs.NewSessionManager()
+	jobManager := jobs.NewManager(hub)
 	s := &Server{
 		loader:            loader,
 		addr:              addr,
 		lgsSessions:       sessions,
 		lgsHandlers:       lgs.NewHandlers(loader, sessions, hub),
-		crowdsimHandlers:  crowdsim.NewHandlers(loader, hub),
-		optimizerHandlers: optimizer.NewHandlers(loader, hub),
+		crowdsimHandlers:  crowdsim.NewHandlers(loader, hub, jobManager),
+		optimizerHandlers: optimizer.NewHandlers(loader, hub, jobManager),
+		jobs:              jobManager,
+		jobHandlers:       jobs.NewHandlers(jobManager),
 		wsHub:             hub,
 	}
 
@@ -66,6 +77,17 @@ func (s *Server) SetCSVWatcher(w *watcher.FileWatcher) {
 	s.csvWatcher = w
 }
 
+// EnableSessionPersistence restores LGS sessions from the snapshot file at path
+// and keeps it updated as sessions change.
+func (s *Server) EnableSessionPersistence(path string) error {
+	return s.lgsSessions.UseStore(lgs.NewFileSessionStore(path))
+}
+
+// Close flushes persistent state. Call before the process exits.
+func (s *Server) Close() error {
+	return s.lgsSessions.Close()
+}
+
 // Hub returns the WebSocket hub.
 func (s *Server) Hub() *ws.Hub {
 	return s.wsHub
@@ -90,6 +112,9 @@ func (s *Server) Start() error {
 	mux.HandleFunc("GET /api/mode/{mode}/distribution/bucket", s.handleModeBucketDistribution)
 	mux.HandleFunc("GET /api/mode/{mode}/outcomes", s.handleModeOutcomes)
 	mux.HandleFunc("GET /api/compare", s.handleCompare)
+	mux.HandleFunc("POST /api/diff", s.handleDiff)
+	mux.HandleFunc("GET /api/mode/{mode}/diff", s.handleModeDiff)
+	mux.HandleFunc("GET /api/mode/{mode}/backups", s.handleModeBackups)
 
 	// Events API
 	mux.HandleFunc("POST /api/mode/{mode}/events/load", s.handleLoadEvents)
@@ -98,9 +123,22 @@ func (s *Server) Start() error {
 	// Simulator API
 	mux.HandleFunc("POST /api/mode/{mode}/simulate", s.handleSimulate)
 	mux.HandleFunc("POST /api/mode/{mode}/simulate/quick", s.handleQuickSimulate)
+	mux.HandleFunc("POST /api/mode/{mode}/simulate/jobs", s.handleSimulateJob)
+	mux.HandleFunc("POST /api/mode/{mode}/simulate/export", s.handleSimulateExport)
+	mux.HandleFunc("GET /api/simulate/jobs/{id}/export", s.handleSimulateJobExport)
+
+	// Background jobs API
+	mux.HandleFunc("GET /api/jobs", s.jobHandlers.HandleList)
+	mux.HandleFunc("GET /api/jobs/{id}", s.jobHandlers.HandleGet)
+	mux.HandleFunc("GET /api/jobs/{id}/result", s.jobHandlers.HandleResult)
+	mux.HandleFunc("DELETE /api/jobs/{id}", s.jobHandlers.HandleCancel)
 
 	// CrowdSim API
 	mux.HandleFunc("POST /api/crowdsim/{mode}/simulate", s.crowdsimHandlers.HandleSimulate)
+	mux.HandleFunc("POST /api/crowdsim/{mode}/jobs", s.crowdsimHandlers.HandleSimulateJob)
+	mux.HandleFunc("POST /api/crowdsim/{mode}/analytic", s.crowdsimHandlers.HandleAnalytic)
+	mux.HandleFunc("POST /api/crowdsim/{mode}/ruin", s.crowdsimHandlers.HandleRuin)
+	mux.HandleFunc("POST /api/crowdsim/ruin/compare", s.crowdsimHandlers.HandleRuinCompare)
 	mux.HandleFunc("POST /api/crowdsim/compare", s.crowdsimHandlers.HandleCompare)
 	mux.HandleFunc("GET /api/crowdsim/presets", s.crowdsimHandlers.HandlePresets)
 	mux.HandleFunc("POST /api/crowdsim/{mode}/validate", s.crowdsimHandlers.HandleValidate)
@@ -139,6 +177,12 @@ func (s *Server) Start() error {
 	mux.HandleFunc("DELETE /lgs/force-outcome", s.lgsHandlers.ClearForcedOutcome)
 	mux.HandleFunc("POST /lgs/rtp-bias", s.lgsHandlers.SetRTPBias)
 	mux.HandleFunc("GET /lgs/rtp-bias", s.lgsHandlers.GetRTPBias)
+	mux.HandleFunc("POST /lgs/seed", s.lgsHandlers.SetSeed)
+	mux.HandleFunc("GET /lgs/config", s.lgsHandlers.GetConfig)
+	mux.HandleFunc("POST /lgs/config", s.lgsHandlers.SetConfig)
+	mux.HandleFunc("GET /lgs/jurisdictions", s.lgsHandlers.Jurisdictions)
+	mux.HandleFunc("POST /lgs/jurisdiction", s.lgsHandlers.SetJurisdiction)
+	mux.HandleFunc("GET /lgs/currencies", s.lgsHandlers.Currencies)
 
 	// WebSocket endpoint
 	mux.HandleFunc("GET /ws", s.wsHub.ServeWs)
@@ -146,6 +190,14 @@ func (s *Server) Start() error {
 	// Compliance API
 	mux.HandleFunc("GET /api/mode/{mode}/compliance", s.handleModeCompliance)
 	mux.HandleFunc("GET /api/compliance", s.handleAllCompliance)
+	mux.HandleFunc("GET /api/compliance/profiles", s.handleComplianceProfiles)
+	mux.HandleFunc("GET /api/win-tiers", s.handleWinTiers)
+	mux.HandleFunc("GET /api/report", s.handleReport)
+	mux.HandleFunc("POST /api/report/jobs", s.handleReportJob)
+	mux.HandleFunc("GET /api/report/jobs/{id}", s.handleReportJobDocument)
+
+	// Data export API
+	mux.HandleFunc("GET /api/mode/{mode}/export/{dataset}", s.handleExport)
 
 	// Background loader API
 	mux.HandleFunc("GET /api/loader/status", s.handleLoaderStatus)
@@ -196,6 +248,9 @@ func (s *Server) GetHandler() http.Handler {
 	mux.HandleFunc("GET /api/mode/{mode}/distribution/bucket", s.handleModeBucketDistribution)
 	mux.HandleFunc("GET /api/mode/{mode}/outcomes", s.handleModeOutcomes)
 	mux.HandleFunc("GET /api/compare", s.handleCompare)
+	mux.HandleFunc("POST /api/diff", s.handleDiff)
+	mux.HandleFunc("GET /api/mode/{mode}/diff", s.handleModeDiff)
+	mux.HandleFunc("GET /api/mode/{mode}/backups", s.handleModeBackups)
 
 	// Events API
 	mux.HandleFunc("POST /api/mode/{mode}/events/load", s.handleLoadEvents)
@@ -204,9 +259,22 @@ func (s *Server) GetHandler() http.Handler {
 	// Simulator API
 	mux.HandleFunc("POST /api/mode/{mode}/simulate", s.handleSimulate)
 	mux.HandleFunc("POST /api/mode/{mode}/simulate/quick", s.handleQuickSimulate)
+	mux.HandleFunc("POST /api/mode/{mode}/simulate/jobs", s.handleSimulateJob)
+	mux.HandleFunc("POST /api/mode/{mode}/simulate/export", s.handleSimulateExport)
+	mux.HandleFunc("GET /api/simulate/jobs/{id}/export", s.handleSimulateJobExport)
+
+	// Background jobs API
+	mux.HandleFunc("GET /api/jobs", s.jobHandlers.HandleList)
+	mux.HandleFunc("GET /api/jobs/{id}", s.jobHandlers.HandleGet)
+	mux.HandleFunc("GET /api/jobs/{id}/result", s.jobHandlers.HandleResult)
+	mux.HandleFunc("DELETE /api/jobs/{id}", s.jobHandlers.HandleCancel)
 
 	// CrowdSim API
 	mux.HandleFunc("POST /api/crowdsim/{mode}/simulate", s.crowdsimHandlers.HandleSimulate)
+	mux.HandleFunc("POST /api/crowdsim/{mode}/jobs", s.crowdsimHandlers.HandleSimulateJob)
+	mux.HandleFunc("POST /api/crowdsim/{mode}/analytic", s.crowdsimHandlers.HandleAnalytic)
+	mux.HandleFunc("POST /api/crowdsim/{mode}/ruin", s.crowdsimHandlers.HandleRuin)
+	mux.HandleFunc("POST /api/crowdsim/ruin/compare", s.crowdsimHandlers.HandleRuinCompare)
 	mux.HandleFunc("POST /api/crowdsim/compare", s.crowdsimHandlers.HandleCompare)
 	mux.HandleFunc("GET /api/crowdsim/presets", s.crowdsimHandlers.HandlePresets)
 	mux.HandleFunc("POST /api/crowdsim/{mode}/validate", s.crowdsimHandlers.HandleValidate)
@@ -244,6 +312,12 @@ func (s *Server) GetHandler() http.Handler {
 	mux.HandleFunc("DELETE /lgs/force-outcome", s.lgsHandlers.ClearForcedOutcome)
 	mux.HandleFunc("POST /lgs/rtp-bias", s.lgsHandlers.SetRTPBias)
 	mux.HandleFunc("GET /lgs/rtp-bias", s.lgsHandlers.GetRTPBias)
+	mux.HandleFunc("POST /lgs/seed", s.lgsHandlers.SetSeed)
+	mux.HandleFunc("GET /lgs/config", s.lgsHandlers.GetConfig)
+	mux.HandleFunc("POST /lgs/config", s.lgsHandlers.SetConfig)
+	mux.HandleFunc("GET /lgs/jurisdictions", s.lgsHandlers.Jurisdictions)
+	mux.HandleFunc("POST /lgs/jurisdiction", s.lgsHandlers.SetJurisdiction)
+	mux.HandleFunc("GET /lgs/currencies", s.lgsHandlers.Currencies)
 
 	// WebSocket endpoint
 	mux.HandleFunc("GET /ws", s.wsHub.ServeWs)
@@ -251,6 +325,14 @@ func (s *Server) GetHandler() http.Handler {
 	// Compliance API
 	mux.HandleFunc("GET /api/mode/{mode}/compliance", s.handleModeCompliance)
 	mux.HandleFunc("GET /api/compliance", s.handleAllCompliance)
+	mux.HandleFunc("GET /api/compliance/profiles", s.handleComplianceProfiles)
+	mux.HandleFunc("GET /api/win-tiers", s.handleWinTiers)
+	mux.HandleFunc("GET /api/report", s.handleReport)
+	mux.HandleFunc("POST /api/report/jobs", s.handleReportJob)
+	mux.HandleFunc("GET /api/report/jobs/{id}", s.handleReportJobDocument)
+
+	// Data export API
+	mux.HandleFunc("GET /api/mode/{mode}/export/{dataset}", s.handleExport)
 
 	// Background loader API
 	mux.HandleFunc("GET /api/loader/status", s.handleLoaderStatus)
@@ -545,6 +627,110 @@ func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// DiffRequest is the request body for POST /api/diff.
+type DiffRequest struct {
+	BaseLibrary      string   `json:"base_library"`             // Name of a library next to the loaded one to compare against (required)
+	TargetLibrary    string   `json:"target_library,omitempty"` // Name of a library next to the loaded one; defaults to the loaded library
+	Modes            []string `json:"modes,omitempty"`
+	MaxWeightChanges int      `json:"max_weight_changes,omitempty"`
+}
+
+// handleDiff compares two libraries mode by mode.
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	var req DiffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if req.BaseLibrary == "" {
+		common.WriteError(w, http.StatusBadRequest, "base_library is required")
+		return
+	}
+
+	// Libraries are named relative to the loaded one; clients never pick filesystem paths
+	basePath, err := s.loader.ResolveLibrary(req.BaseLibrary)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	base := lut.NewLoaderFromLibrary(basePath)
+	if err := base.Load(); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "failed to load base library: "+err.Error())
+		return
+	}
+
+	target := s.loader
+	if req.TargetLibrary != "" {
+		targetPath, err := s.loader.ResolveLibrary(req.TargetLibrary)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		target = lut.NewLoaderFromLibrary(targetPath)
+		if err := target.Load(); err != nil {
+			common.WriteError(w, http.StatusBadRequest, "failed to load target library: "+err.Error())
+			return
+		}
+	}
+
+	result := lut.DiffLibraries(base, target, lut.DiffOptions{
+		Modes:            req.Modes,
+		MaxWeightChanges: req.MaxWeightChanges,
+	})
+
+	common.WriteSuccess(w, result)
+}
+
+// handleModeDiff compares the loaded table against one of its backups.
+// Query params: backup (file name or "latest", default latest), limit.
+func (s *Server) handleModeDiff(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	if _, err := s.loader.GetMode(mode); err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 0
+	if v := query.Get("limit"); v != "" {
+		fmt.Sscanf(v, "%d", &limit)
+	}
+
+	result, err := s.loader.DiffBackup(mode, query.Get("backup"), lut.DiffOptions{MaxWeightChanges: limit})
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, result)
+}
+
+// handleModeBackups lists the weight backups for a mode, newest first.
+func (s *Server) handleModeBackups(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	backups, err := s.loader.Backups(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, map[string]interface{}{
+		"mode":    mode,
+		"backups": backups,
+	})
+}
+
 // handleLoadEvents loads events for a mode from the .jsonl.zst file.
 func (s *Server) handleLoadEvents(w http.ResponseWriter, r *http.Request) {
 	mode := r.PathValue("mode")
@@ -663,26 +849,60 @@ type SimulateRequest struct {
 	TargetRTP   float64   `json:"target_rtp"`
 	TestSpins   []int     `json:"test_spins"`
 	TestWeights []float64 `json:"test_weights"`
+	Seed        int64     `json:"seed"` // 0 picks a random seed
+
+	RTPPrecision float64 `json:"rtp_precision"` // RTP half-width to report the spins needed for
+
+	AllTrialSummaries bool `json:"all_trial_summaries"` // Keep every trial's summary, so a job's trials can be exported
 }
 
 // handleSimulate runs a full simulation with multiple trials.
 func (s *Server) handleSimulate(w http.ResponseWriter, r *http.Request) {
+	table, config, ok := s.decodeSimulation(w, r)
+	if !ok {
+		return
+	}
+
+	result := s.loader.Simulator().RunSimulation(table, config)
+	common.WriteSuccess(w, result)
+}
+
+// handleSimulateJob starts a full simulation as a background job. Progress is
+// broadcast as job_progress messages; the result is fetched from /api/jobs.
+func (s *Server) handleSimulateJob(w http.ResponseWriter, r *http.Request) {
+	table, config, ok := s.decodeSimulation(w, r)
+	if !ok {
+		return
+	}
+
+	simulator := s.loader.Simulator()
+	info := s.jobs.Start(jobs.KindSimulation, table.Mode, func(ctx context.Context, report func(jobs.Progress)) (interface{}, error) {
+		return simulator.RunSimulationContext(ctx, table, config, func(trialsDone, trials int) {
+			report(jobs.Progress{Done: int64(trialsDone), Total: int64(trials)})
+		})
+	})
+	jobs.WriteStarted(w, info)
+}
+
+// decodeSimulation looks up the mode and reads a SimulateRequest, applying
+// defaults and limits. It writes the error response and returns false on failure.
+func (s *Server) decodeSimulation(w http.ResponseWriter, r *http.Request) (*stakergs.LookupTable, lut.SimulationConfig, bool) {
 	mode := r.PathValue("mode")
 	if mode == "" {
 		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
-		return
+		return nil, lut.SimulationConfig{}, false
 	}
 
 	table, err := s.loader.GetMode(mode)
 	if err != nil {
 		common.WriteError(w, http.StatusNotFound, err.Error())
-		return
+		return nil, lut.SimulationConfig{}, false
 	}
 
 	var req SimulateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
-		return
+		return nil, lut.SimulationConfig{}, false
 	}
 
 	// Validate and set defaults
@@ -718,15 +938,81 @@ func (s *Server) handleSimulate(w http.ResponseWriter, r *http.Request) {
 		TargetRTP:   req.TargetRTP,
 		TestSpins:   req.TestSpins,
 		TestWeights: req.TestWeights,
+		Seed:        req.Seed,
+
+		RTPPrecision: req.RTPPrecision,
+
+		AllTrialSummaries: req.AllTrialSummaries,
 	}
+	return table, config, true
+}
 
-	result := s.loader.Simulator().RunSimulation(table, config)
-	common.WriteSuccess(w, result)
+// handleSimulateExport runs a full simulation and streams every trial's
+// summary in the "format" query param (default: csv). Simulations of more
+// than report.MaxSyncSpins spins are refused; run them as a job with
+// all_trial_summaries and export it from /api/simulate/jobs/{id}/export.
+func (s *Server) handleSimulateExport(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	table, config, ok := s.decodeSimulation(w, r)
+	if !ok {
+		return
+	}
+	if spins := config.Spins * config.Trials; spins > report.MaxSyncSpins {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf(
+			"spins × trials is %d, more than the %d a request may simulate: start a job with POST /api/mode/%s/simulate/jobs "+
+				"and \"all_trial_summaries\": true, then export it from GET /api/simulate/jobs/{id}/export",
+			spins, report.MaxSyncSpins, table.Mode))
+		return
+	}
+	config.AllTrialSummaries = true
+
+	result, err := s.loader.Simulator().RunSimulationContext(r.Context(), table, config, nil)
+	if err != nil {
+		if r.Context().Err() != nil {
+			return // Client went away
+		}
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeExport(w, table.Mode, export.Trials(result), format)
+}
+
+// handleSimulateJobExport streams every trial's summary of a completed
+// simulation job started with all_trial_summaries, in the "format" query
+// param (default: csv).
+func (s *Server) handleSimulateJobExport(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	info, result, err := s.jobs.Result(r.PathValue("id"))
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	sim, isSimulation := result.(*lut.SimulationResult)
+	switch {
+	case info.Kind != jobs.KindSimulation:
+		common.WriteError(w, http.StatusBadRequest, "job is not a simulation job")
+	case info.Status == jobs.StatusFailed:
+		common.WriteError(w, http.StatusConflict, "job failed: "+info.Error)
+	case info.Status != jobs.StatusCompleted || !isSimulation:
+		common.WriteError(w, http.StatusConflict, "job is "+string(info.Status))
+	case !sim.Config.AllTrialSummaries && len(sim.TrialSummaries) < sim.Config.Trials:
+		common.WriteError(w, http.StatusConflict, "job did not keep every trial: start it with \"all_trial_summaries\": true")
+	default:
+		writeExport(w, sim.Mode, export.Trials(sim), format)
+	}
 }
 
 // QuickSimulateRequest holds the request body for quick simulation.
 type QuickSimulateRequest struct {
-	Spins int `json:"spins"`
+	Spins int   `json:"spins"`
+	Seed  int64 `json:"seed"` // 0 picks a random seed
 }
 
 // handleQuickSimulate runs a quick single-trial simulation with spin-by-spin results.
@@ -763,7 +1049,7 @@ func (s *Server) handleQuickSimulate(w http.ResponseWriter, r *http.Request) {
 		bet = 1.0
 	}
 
-	result := s.loader.Simulator().RunQuickSimulation(table, req.Spins, bet)
+	result := s.loader.Simulator().RunQuickSimulation(table, req.Spins, bet, req.Seed)
 	common.WriteSuccess(w, result)
 }
 
@@ -890,7 +1176,11 @@ func (s *Server) handleModeCompliance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	checker := lut.NewComplianceChecker()
+	checker, err := s.complianceChecker(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	result := checker.CheckMode(table)
 
 	common.WriteSuccess(w, result)
@@ -913,12 +1203,252 @@ func (s *Server) handleAllCompliance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	checker := lut.NewComplianceChecker()
+	checker, err := s.complianceChecker(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	result := checker.CheckAllModes(tables)
 
 	common.WriteSuccess(w, result)
 }
 
+// complianceChecker creates a checker for the profile named in the "profile" query param.
+func (s *Server) complianceChecker(r *http.Request) (*lut.ComplianceChecker, error) {
+	profile, err := s.loader.ComplianceProfile(r.URL.Query().Get("profile"))
+	if err != nil {
+		return nil, err
+	}
+	return lut.NewComplianceCheckerWithProfile(profile), nil
+}
+
+// handleWinTiers returns the win tiers used to classify the library's wins.
+func (s *Server) handleWinTiers(w http.ResponseWriter, r *http.Request) {
+	common.WriteSuccess(w, s.loader.WinTiers())
+}
+
+// handleReport renders a certification report for the modes in the "mode"
+// query params (default: all modes) as HTML or, with format=pdf, as a PDF.
+// Reports simulating more than report.MaxSyncSpins spins per mode are
+// refused; start them with POST /api/report/jobs instead.
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	format, ok := reportFormat(w, r)
+	if !ok {
+		return
+	}
+	modes, opts, ok := s.decodeReport(w, r)
+	if !ok {
+		return
+	}
+	if spins := opts.Spins * opts.Trials; spins > report.MaxSyncSpins {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf(
+			"spins × trials is %d, more than the %d a request may simulate per mode: start a report job with POST /api/report/jobs",
+			spins, report.MaxSyncSpins))
+		return
+	}
+
+	rep, err := report.Build(r.Context(), s.loader, modes, opts, nil)
+	if err != nil {
+		if r.Context().Err() != nil {
+			return // Client went away
+		}
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeReport(w, rep, format)
+}
+
+// handleReportJob builds a certification report as a background job, taking
+// the query params of /api/report. Progress is broadcast as job_progress
+// messages; the finished report is rendered by /api/report/jobs/{id}.
+func (s *Server) handleReportJob(w http.ResponseWriter, r *http.Request) {
+	modes, opts, ok := s.decodeReport(w, r)
+	if !ok {
+		return
+	}
+
+	loader := s.loader
+	info := s.jobs.Start(jobs.KindReport, strings.Join(modes, ","), func(ctx context.Context, progress func(jobs.Progress)) (interface{}, error) {
+		return report.Build(ctx, loader, modes, opts, func(trialsDone, trials int) {
+			progress(jobs.Progress{Done: int64(trialsDone), Total: int64(trials)})
+		})
+	})
+	jobs.WriteStarted(w, info)
+}
+
+// handleReportJobDocument renders the report of a completed report job as
+// HTML or, with format=pdf, as a PDF.
+func (s *Server) handleReportJobDocument(w http.ResponseWriter, r *http.Request) {
+	format, ok := reportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	info, result, err := s.jobs.Result(r.PathValue("id"))
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	rep, isReport := result.(*report.Report)
+	switch {
+	case info.Kind != jobs.KindReport:
+		common.WriteError(w, http.StatusBadRequest, "job is not a report job")
+	case info.Status == jobs.StatusFailed:
+		common.WriteError(w, http.StatusConflict, "job failed: "+info.Error)
+	case info.Status != jobs.StatusCompleted || !isReport:
+		common.WriteError(w, http.StatusConflict, "job is "+string(info.Status))
+	default:
+		writeReport(w, rep, format)
+	}
+}
+
+// reportFormat reads the "format" query param, writing the error response
+// and returning false if it is not a report format.
+func reportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = report.FormatHTML
+	}
+	if format != report.FormatHTML && format != report.FormatPDF {
+		common.WriteError(w, http.StatusBadRequest, "format must be html or pdf")
+		return "", false
+	}
+	return format, true
+}
+
+// decodeReport reads the modes and report options from the query params,
+// applying defaults and limits. It writes the error response and returns
+// false on failure.
+func (s *Server) decodeReport(w http.ResponseWriter, r *http.Request) ([]string, report.Options, bool) {
+	query := r.URL.Query()
+
+	modes := query["mode"]
+	if len(modes) == 0 {
+		modes = s.loader.ListModes()
+	}
+	for _, mode := range modes {
+		if _, err := s.loader.GetMode(mode); err != nil {
+			common.WriteError(w, http.StatusNotFound, err.Error())
+			return nil, report.Options{}, false
+		}
+	}
+
+	opts := report.Options{
+		Title:   query.Get("title"),
+		Profile: query.Get("profile"),
+	}
+	if v := query.Get("spins"); v != "" {
+		fmt.Sscanf(v, "%d", &opts.Spins)
+	}
+	if v := query.Get("trials"); v != "" {
+		fmt.Sscanf(v, "%d", &opts.Trials)
+	}
+	if v := query.Get("seed"); v != "" {
+		fmt.Sscanf(v, "%d", &opts.Seed)
+	}
+	if v := query.Get("rows"); v != "" {
+		fmt.Sscanf(v, "%d", &opts.DistributionRows)
+	}
+	if err := opts.Validate(); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil, report.Options{}, false
+	}
+	return modes, opts, true
+}
+
+// writeReport renders a report in format.
+func writeReport(w http.ResponseWriter, rep *report.Report, format string) {
+	var err error
+	if format == report.FormatPDF {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="report.pdf"`)
+		err = rep.WritePDF(w)
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err = rep.WriteHTML(w)
+	}
+	if err != nil {
+		log.Printf("[Report] Failed to write report: %v", err)
+	}
+}
+
+// handleExport streams a mode's distribution, buckets or outcomes in the
+// "format" query param (default: csv), listing every sim_id.
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	dataset, err := export.TableDataset(r.PathValue("dataset"), table, s.loader.Analyzer())
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeExport(w, mode, dataset, format)
+}
+
+// exportFormat reads the "format" query param, writing the error response
+// and returning false if it is not an export format.
+func exportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if !export.ValidFormat(format) {
+		common.WriteError(w, http.StatusBadRequest, "format must be csv, xlsx or parquet")
+		return "", false
+	}
+	return format, true
+}
+
+// writeExport streams a dataset as a file download, or writes the error
+// response if it cannot be written in format.
+func writeExport(w http.ResponseWriter, mode string, d *export.Dataset, format string) {
+	if err := export.Check(d, format); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName(mode, d, format)))
+	if err := export.Write(w, d, format); err != nil {
+		log.Printf("[Export] Failed to write %s %s: %v", mode, d.Name, err)
+	}
+}
+
+// handleComplianceProfiles returns all compliance profiles available for the library.
+func (s *Server) handleComplianceProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := s.loader.ComplianceProfiles()
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]*lut.ComplianceProfile, 0, len(names))
+	for _, name := range names {
+		list = append(list, profiles[name])
+	}
+
+	common.WriteSuccess(w, list)
+}
+
 // WatcherStatus represents the status of the CSV watcher.
 type WatcherStatus struct {
 	Available bool              `json:"available"`
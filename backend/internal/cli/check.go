@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"lutexplorer/internal/lut"
+)
+
+// CheckOutput is the JSON document printed by "check -json".
+type CheckOutput struct {
+	Library       string                        `json:"library"`
+	Passed        bool                          `json:"passed"`
+	Strict        bool                          `json:"strict"`
+	ErrorFailures int                           `json:"error_failures"`
+	Warnings      int                           `json:"warnings"`
+	Result        *lut.AllModesComplianceResult `json:"result"`
+}
+
+// runCheck runs all compliance checks and gates on error-severity failures.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	var common commonFlags
+	var strict bool
+	var profileName string
+
+	fs := newFlagSet("check", stderr)
+	common.register(fs)
+	fs.BoolVar(&strict, "strict", false, "Also fail on warning-severity checks")
+	fs.StringVar(&profileName, "profile", lut.DefaultComplianceProfileName, "Compliance profile to apply (see "+lut.ComplianceProfilesFile+")")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	loader, modes, err := loadLibrary(common)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	profile, err := loader.ComplianceProfile(profileName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	checker := lut.NewComplianceCheckerWithProfile(profile)
+	result := checker.CheckAllModes(tablesFor(loader, modes))
+
+	errorFailures, warnings := countGatedFailures(result)
+	passed := errorFailures == 0 && (!strict || warnings == 0)
+
+	if common.json {
+		if err := writeJSON(stdout, CheckOutput{
+			Library:       common.library,
+			Passed:        passed,
+			Strict:        strict,
+			ErrorFailures: errorFailures,
+			Warnings:      warnings,
+			Result:        result,
+		}); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+	} else {
+		printCheck(stdout, modes, result)
+		fmt.Fprintln(stdout)
+		if passed {
+			fmt.Fprintf(stdout, "Result: PASSED (%d warning(s))\n", warnings)
+		} else {
+			fmt.Fprintf(stdout, "Result: FAILED (%d error-severity failure(s), %d warning(s))\n", errorFailures, warnings)
+		}
+	}
+
+	if !passed {
+		return ExitFailed
+	}
+	return ExitOK
+}
+
+// countGatedFailures counts failed checks with error and warning severity
+// across all modes and global checks. Info-severity failures never gate.
+func countGatedFailures(result *lut.AllModesComplianceResult) (errors, warnings int) {
+	count := func(checks []lut.ComplianceCheck) {
+		for _, check := range checks {
+			if check.Passed {
+				continue
+			}
+			switch check.Severity {
+			case "error":
+				errors++
+			case "warning":
+				warnings++
+			}
+		}
+	}
+
+	for _, modeResult := range result.ModeResults {
+		count(modeResult.Checks)
+	}
+	count(result.GlobalChecks)
+
+	return errors, warnings
+}
+
+// printCheck prints compliance results as a table per mode.
+func printCheck(w io.Writer, modes []string, result *lut.AllModesComplianceResult) {
+	fmt.Fprintf(w, "Profile: %s\n", result.Profile)
+	if len(result.Disabled) > 0 {
+		fmt.Fprintf(w, "Disabled checks: %v\n", result.Disabled)
+	}
+	fmt.Fprintln(w)
+
+	for i, mode := range modes {
+		modeResult, ok := result.ModeResults[mode]
+		if !ok {
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Mode %q: %d passed, %d failed, %d warning(s)\n",
+			mode, modeResult.PassedCount, modeResult.FailedCount, modeResult.WarningCount)
+		printChecks(w, modeResult.Checks)
+	}
+
+	if len(result.GlobalChecks) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Global checks:")
+		printChecks(w, result.GlobalChecks)
+	}
+}
+
+func printChecks(w io.Writer, checks []lut.ComplianceCheck) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, check := range checks {
+		status := "PASS"
+		if !check.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(tw, "  [%s]\t%s\t%s\t%s\t(expected %s)\n", status, check.Severity, check.Name, check.Value, check.Expected)
+		if !check.Passed && check.Reason != "" {
+			fmt.Fprintf(tw, "  \t\t\t%s\t\n", check.Reason)
+		}
+	}
+	tw.Flush()
+}
@@ -0,0 +1,159 @@
+// Package cli implements the headless lutexplorer subcommands.
+// These run the same analysis as the HTTP API directly against a library
+// folder, so CI pipelines can gate merges without booting the server.
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"lutexplorer/internal/lut"
+	"stakergs"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK     = 0 // Command succeeded and all gated checks passed
+	ExitFailed = 1 // Command ran but a gated check failed
+	ExitUsage  = 2 // Invalid arguments or library could not be loaded
+)
+
+// command describes a single CLI subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+// commands returns all registered subcommands in display order.
+func commands() []command {
+	return []command{
+		{"check", "Run compliance checks and exit non-zero on error-severity failures", runCheck},
+		{"stats", "Print LUT statistics for each mode", runStats},
+		{"simulate", "Run a Monte Carlo simulation for each mode", runSimulate},
+		{"diff", "Compare a library against another library or a weight backup", runDiff},
+		{"report", "Write an HTML or PDF certification report for each mode", runReport},
+		{"export", "Export distributions, buckets, outcomes or trials as CSV, XLSX or Parquet", runExport},
+	}
+}
+
+// IsCommand reports whether name is a known subcommand (including "help").
+func IsCommand(name string) bool {
+	if name == "help" || name == "-h" || name == "--help" {
+		return true
+	}
+	for _, c := range commands() {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the subcommand in args[0] with the remaining arguments.
+// Returns the process exit code.
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return ExitOK
+	}
+
+	for _, c := range commands() {
+		if c.name == args[0] {
+			return c.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "Error: unknown command %q\n\n", args[0])
+	printUsage(stderr)
+	return ExitUsage
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  lutexplorer -library <path> [-port 7754] [-https-port 7755]   Start the API server")
+	fmt.Fprintln(w, "  lutexplorer <command> -library <path> [flags]                  Run a headless command")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'lutexplorer <command> -h' for command flags.")
+}
+
+// commonFlags holds flags shared by all subcommands.
+type commonFlags struct {
+	library string
+	modes   string
+	json    bool
+}
+
+// register adds the shared flags to a flag set.
+func (c *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.library, "library", "", "Path to library folder (required)")
+	fs.StringVar(&c.modes, "mode", "", "Comma-separated list of modes (default: all modes)")
+	fs.BoolVar(&c.json, "json", false, "Print machine-readable JSON instead of text")
+}
+
+// newFlagSet creates a flag set that reports errors to stderr instead of exiting.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// loadLibrary loads the library and resolves the requested modes.
+// Modes are returned in index order so output is stable between runs.
+func loadLibrary(c commonFlags) (*lut.Loader, []string, error) {
+	if c.library == "" {
+		return nil, nil, fmt.Errorf("-library flag is required")
+	}
+
+	loader := lut.NewLoaderFromLibrary(c.library)
+	if err := loader.Load(); err != nil {
+		return nil, nil, fmt.Errorf("failed to load library: %w", err)
+	}
+
+	if c.modes == "" {
+		return loader, loader.ListModes(), nil
+	}
+
+	var modes []string
+	for _, m := range strings.Split(c.modes, ",") {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		table, err := loader.GetMode(m)
+		if err != nil {
+			return nil, nil, err
+		}
+		modes = append(modes, table.Mode)
+	}
+	if len(modes) == 0 {
+		return nil, nil, fmt.Errorf("no modes selected")
+	}
+	return loader, modes, nil
+}
+
+// tablesFor returns the lookup tables for the given modes keyed by mode name.
+func tablesFor(loader *lut.Loader, modes []string) map[string]*stakergs.LookupTable {
+	tables := make(map[string]*stakergs.LookupTable, len(modes))
+	for _, mode := range modes {
+		if table, err := loader.GetMode(mode); err == nil {
+			tables[mode] = table
+		}
+	}
+	return tables
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"lutexplorer/internal/lut"
+	"lutexplorer/internal/testutil"
+)
+
+// compliantCSV is a 96% RTP table with enough distinct outcomes to pass all error checks.
+// Its most frequent outcome has a 35% probability, so it fails the
+// simulation diversity warning.
+const compliantCSV = `0,348250,0
+1,300000,50
+2,150000,100
+3,151750,200
+4,40000,500
+5,8000,1000
+6,1500,2500
+7,400,5000
+8,90,10000
+9,10,100000
+`
+
+func TestRun_CheckExitCodes(t *testing.T) {
+	good := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: compliantCSV})
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"check", "-library", good, "-json"}, &stdout, &stderr)
+
+	var out CheckOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	t.Logf("RTP: %.4f, error failures: %d, warnings: %d", out.Result.ModeResults["base"].Summary.RTP, out.ErrorFailures, out.Warnings)
+	if code != ExitOK || out.ErrorFailures != 0 {
+		t.Errorf("expected exit %d with no error failures, got %d (%d failures)", ExitOK, code, out.ErrorFailures)
+	}
+
+	// Warnings only gate in strict mode
+	var diversity *lut.ComplianceCheck
+	for i, check := range out.Result.ModeResults["base"].Checks {
+		if check.ID == lut.CheckSimulationDiversity {
+			diversity = &out.Result.ModeResults["base"].Checks[i]
+		}
+	}
+	if diversity == nil || diversity.Passed || diversity.Severity != "warning" || out.Warnings == 0 {
+		t.Fatalf("fixture must fail the simulation diversity warning: got %+v, %d warnings", diversity, out.Warnings)
+	}
+	stdout.Reset()
+	if code := Run([]string{"check", "-library", good, "-strict"}, &stdout, &stderr); code != ExitFailed {
+		t.Errorf("expected exit %d in strict mode with warnings, got %d", ExitFailed, code)
+	}
+
+	// A table paying 200% RTP must fail the RTP range check
+	bad := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: "0,1,200\n1,1,200\n"})
+	stdout.Reset()
+	if code := Run([]string{"check", "-library", bad}, &stdout, &stderr); code != ExitFailed {
+		t.Errorf("expected exit %d for non-compliant table, got %d\n%s", ExitFailed, code, stdout.String())
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	if code := Run([]string{"stats"}, &stdout, &stderr); code != ExitUsage {
+		t.Errorf("expected exit %d without -library, got %d", ExitUsage, code)
+	}
+	if code := Run([]string{"unknown"}, &stdout, &stderr); code != ExitUsage {
+		t.Errorf("expected exit %d for unknown command, got %d", ExitUsage, code)
+	}
+
+	lib := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: compliantCSV})
+	if code := Run([]string{"stats", "-library", lib, "-mode", "missing"}, &stdout, &stderr); code != ExitUsage {
+		t.Errorf("expected exit %d for unknown mode, got %d", ExitUsage, code)
+	}
+}
+
+func TestRun_SimulateJSON(t *testing.T) {
+	lib := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: compliantCSV})
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"simulate", "-library", lib, "-spins", "1000", "-trials", "10", "-json"}, &stdout, &stderr)
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr.String())
+	}
+
+	var results []map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result, got %d", len(results))
+	}
+}
+
+func TestRun_SimulateSeedIsReproducible(t *testing.T) {
+	lib := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: compliantCSV})
+
+	simulate := func(args ...string) lut.SimulationResult {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		args = append([]string{"simulate", "-library", lib, "-spins", "500", "-trials", "20", "-json"}, args...)
+		if code := Run(args, &stdout, &stderr); code != ExitOK {
+			t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr.String())
+		}
+		var results []lut.SimulationResult
+		if err := json.Unmarshal(stdout.Bytes(), &results); err != nil || len(results) != 1 {
+			t.Fatalf("invalid JSON output: %v", err)
+		}
+		results[0].DurationMs = 0
+		return results[0]
+	}
+
+	first := simulate()
+	if first.Config.Seed == 0 {
+		t.Fatal("expected the picked seed in the result")
+	}
+	replay := simulate("-seed", strconv.FormatInt(first.Config.Seed, 10))
+	if !reflect.DeepEqual(first, replay) {
+		t.Errorf("replay with seed %d differs:\nfirst  %+v\nreplay %+v", first.Config.Seed, first, replay)
+	}
+	if other := simulate("-seed", strconv.FormatInt(first.Config.Seed+1, 10)); reflect.DeepEqual(first.TrialSummaries, other.TrialSummaries) {
+		t.Error("expected a different seed to give different trials")
+	}
+}
+
+func TestRun_CheckProfile(t *testing.T) {
+	lib := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: compliantCSV})
+
+	profiles := `{"profiles": [{
+		"name": "tight",
+		"rules": {
+			"rtp_range": {"thresholds": {"min": 0.97}},
+			"hit_rate_reasonable": {"enabled": false}
+		}
+	}]}`
+	if err := os.WriteFile(filepath.Join(lib, "compliance_profiles.json"), []byte(profiles), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"check", "-library", lib, "-profile", "tight", "-json"}, &stdout, &stderr); code != ExitFailed {
+		t.Errorf("expected exit %d with 97%% minimum RTP, got %d", ExitFailed, code)
+	}
+
+	var out CheckOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if out.Result.Profile != "tight" {
+		t.Errorf("expected profile %q, got %q", "tight", out.Result.Profile)
+	}
+	for _, check := range out.Result.ModeResults["base"].Checks {
+		if check.ID == "hit_rate_reasonable" {
+			t.Errorf("disabled check %q was run", check.ID)
+		}
+	}
+
+	if code := Run([]string{"check", "-library", lib, "-profile", "missing"}, &stdout, &stderr); code != ExitUsage {
+		t.Errorf("expected exit %d for unknown profile, got %d", ExitUsage, code)
+	}
+}
+
+func TestRun_Diff(t *testing.T) {
+	base := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: compliantCSV})
+	target := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: strings.Replace(compliantCSV, "9,10,100000", "9,20,100000", 1)})
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"diff", "-library", target, "-base", base, "-json"}, &stdout, &stderr); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr.String())
+	}
+
+	var result lut.LibraryDiff
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(result.Modes) != 1 {
+		t.Fatalf("expected 1 mode, got %d", len(result.Modes))
+	}
+
+	d := result.Modes[0]
+	if d.ChangedSimIDs != 1 || len(d.WeightChanges) != 1 || d.WeightChanges[0].SimID != 9 {
+		t.Errorf("expected only sim_id 9 to change, got %+v", d.WeightChanges)
+	}
+	if d.RTP.Delta <= 0 {
+		t.Errorf("expected RTP to increase, got delta %f", d.RTP.Delta)
+	}
+
+	// Saving new weights creates a backup the diff can compare against
+	loader := lut.NewLoaderFromLibrary(base)
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+	table, _ := loader.GetMode("base")
+	weights := make([]uint64, len(table.Outcomes))
+	for i, o := range table.Outcomes {
+		weights[i] = o.Weight
+	}
+	weights[0] += 1000
+	if _, err := loader.SaveWeightsWithBackup("base", weights); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout.Reset()
+	if code := Run([]string{"diff", "-library", base, "-backup", "latest", "-json"}, &stdout, &stderr); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr.String())
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if d := result.Modes[0]; d.ChangedSimIDs != 1 || d.WeightChanges[0].SimID != 0 {
+		t.Errorf("expected only sim_id 0 to change against backup, got %+v", d.WeightChanges)
+	}
+}
+
+func TestRun_Report(t *testing.T) {
+	lib := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: compliantCSV})
+	out := filepath.Join(t.TempDir(), "report.pdf")
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"report", "-library", lib, "-format", "pdf", "-o", out, "-spins", "100", "-trials", "5"}, &stdout, &stderr); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr.String())
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || stdout.Len() != 0 {
+		t.Errorf("expected a PDF file and no output, got %d bytes of output", stdout.Len())
+	}
+
+	stdout.Reset()
+	if code := Run([]string{"report", "-library", lib, "-spins", "100", "-trials", "5"}, &stdout, &stderr); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "<!DOCTYPE html>") || !strings.Contains(stdout.String(), "Mode: base") {
+		t.Errorf("expected an HTML report on stdout")
+	}
+
+	if code := Run([]string{"report", "-library", lib, "-format", "docx"}, &stdout, &stderr); code != ExitUsage {
+		t.Errorf("expected exit %d for an unknown format, got %d", ExitUsage, code)
+	}
+}
+
+func TestRun_Export(t *testing.T) {
+	lib := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: compliantCSV})
+	out := filepath.Join(t.TempDir(), "exports")
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"export", "-library", lib, "-out", out, "-data", "distribution,outcomes,trials", "-spins", "100", "-trials", "150", "-json"}
+	if code := Run(args, &stdout, &stderr); code != ExitOK {
+		t.Fatalf("expected exit %d, got %d: %s", ExitOK, code, stderr.String())
+	}
+	var files []exportedFile
+	if err := json.Unmarshal(stdout.Bytes(), &files); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(files) != 3 || files[2].Rows != 150 {
+		t.Fatalf("unexpected files %+v", files)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "base_outcomes.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "sim_id,weight,payout,probability\n") {
+		t.Errorf("unexpected outcomes export %q", data)
+	}
+
+	for _, bad := range [][]string{
+		{"export", "-library", lib, "-out", out, "-format", "json"},
+		{"export", "-library", lib, "-out", out, "-data", "events"},
+	} {
+		if code := Run(bad, &stdout, &stderr); code != ExitUsage {
+			t.Errorf("expected exit %d for %v, got %d", ExitUsage, bad[len(bad)-2:], code)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"lutexplorer/internal/lut"
+)
+
+// runDiff compares a library against another library or against weight backups.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	var common commonFlags
+	var baseLibrary, backup string
+	var limit int
+
+	fs := newFlagSet("diff", stderr)
+	common.register(fs)
+	fs.StringVar(&baseLibrary, "base", "", "Library to compare against")
+	fs.StringVar(&backup, "backup", "", "Compare against a weight backup of each mode (file name or \"latest\")")
+	fs.IntVar(&limit, "limit", 20, "Largest sim_id weight changes to report per mode")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if (baseLibrary == "") == (backup == "") {
+		fmt.Fprintln(stderr, "Error: exactly one of -base or -backup is required")
+		return ExitUsage
+	}
+
+	loader, modes, err := loadLibrary(common)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	opts := lut.DiffOptions{MaxWeightChanges: limit}
+	var result *lut.LibraryDiff
+
+	if baseLibrary != "" {
+		base := lut.NewLoaderFromLibrary(baseLibrary)
+		if err := base.Load(); err != nil {
+			fmt.Fprintf(stderr, "Error: failed to load base library: %v\n", err)
+			return ExitUsage
+		}
+		if common.modes != "" {
+			opts.Modes = modes
+		}
+		result = lut.DiffLibraries(base, loader, opts)
+	} else {
+		if backup != "latest" && len(modes) != 1 {
+			fmt.Fprintln(stderr, "Error: a named -backup requires a single -mode")
+			return ExitUsage
+		}
+		result = &lut.LibraryDiff{
+			Base:   backup,
+			Target: common.library,
+			Modes:  make([]*lut.ModeDiff, 0, len(modes)),
+		}
+		for _, mode := range modes {
+			diff, err := loader.DiffBackup(mode, backup, opts)
+			if err != nil {
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				return ExitUsage
+			}
+			result.Modes = append(result.Modes, diff)
+		}
+	}
+
+	if common.json {
+		if err := writeJSON(stdout, result); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+		return ExitOK
+	}
+
+	printDiff(stdout, result)
+	return ExitOK
+}
+
+// printDiff prints a human-readable library diff.
+func printDiff(w io.Writer, result *lut.LibraryDiff) {
+	fmt.Fprintf(w, "Base:   %s\n", result.Base)
+	fmt.Fprintf(w, "Target: %s\n", result.Target)
+	if len(result.AddedModes) > 0 {
+		fmt.Fprintf(w, "Added modes: %v\n", result.AddedModes)
+	}
+	if len(result.RemovedModes) > 0 {
+		fmt.Fprintf(w, "Removed modes: %v\n", result.RemovedModes)
+	}
+
+	for _, d := range result.Modes {
+		fmt.Fprintln(w)
+		if !d.Changed {
+			fmt.Fprintf(w, "Mode %q: unchanged\n", d.Mode)
+			continue
+		}
+		fmt.Fprintf(w, "Mode %q: %d sim_id(s) changed (%d added, %d removed)\n",
+			d.Mode, d.ChangedSimIDs, d.AddedSimIDs, d.RemovedSimIDs)
+		if d.CostChanged {
+			fmt.Fprintln(w, "  Cost changed")
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  Metric\tBase\tTarget\tDelta")
+		fmt.Fprintf(tw, "  RTP\t%.4f%%\t%.4f%%\t%+.4f%%\n", d.RTP.Base*100, d.RTP.Target*100, d.RTP.Delta*100)
+		fmt.Fprintf(tw, "  Hit rate\t%.2f%%\t%.2f%%\t%+.2f%%\n", d.HitRate.Base*100, d.HitRate.Target*100, d.HitRate.Delta*100)
+		fmt.Fprintf(tw, "  Volatility\t%.4f\t%.4f\t%+.4f\n", d.Volatility.Base, d.Volatility.Target, d.Volatility.Delta)
+		fmt.Fprintf(tw, "  Max payout\t%.2fx\t%.2fx\t%+.2fx\n", d.MaxPayout.Base, d.MaxPayout.Target, d.MaxPayout.Delta)
+		fmt.Fprintf(tw, "  Max win odds\t1 in %.0f\t1 in %.0f\t%+.0f\n", d.MaxWinOdds.Base, d.MaxWinOdds.Target, d.MaxWinOdds.Delta)
+		tw.Flush()
+
+		fmt.Fprintln(w, "  Buckets:")
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, b := range d.Buckets {
+			if b.Delta == 0 {
+				continue
+			}
+			fmt.Fprintf(tw, "    %gx - %gx\t%.4f%%\t-> %.4f%%\t(%+.4f%%)\n",
+				b.RangeStart, b.RangeEnd, b.BaseProbability*100, b.TargetProbability*100, b.Delta*100)
+		}
+		tw.Flush()
+
+		if len(d.WeightChanges) > 0 {
+			fmt.Fprintln(w, "  Largest weight changes:")
+			tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			for _, c := range d.WeightChanges {
+				fmt.Fprintf(tw, "    sim_id %d\t%.2fx\tweight %d -> %d\t(%+.6f%%)\n",
+					c.SimID, c.TargetPayout, c.BaseWeight, c.TargetWeight, c.ProbabilityDelta*100)
+			}
+			tw.Flush()
+			if d.ChangesTrimmed {
+				fmt.Fprintf(w, "    ... %d more\n", d.ChangedSimIDs-len(d.WeightChanges))
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/export"
+	"lutexplorer/internal/lut"
+)
+
+// exportedFile describes one file written by the export command.
+type exportedFile struct {
+	Mode    string `json:"mode"`
+	Dataset string `json:"dataset"`
+	Path    string `json:"path"`
+	Rows    int    `json:"rows"`
+}
+
+// runExport writes the selected datasets of each mode to files.
+func runExport(args []string, stdout, stderr io.Writer) int {
+	var flags commonFlags
+	var data, format, outDir string
+	var spins, trials int
+	var seed int64
+
+	fs := newFlagSet("export", stderr)
+	flags.register(fs)
+	fs.StringVar(&data, "data", strings.Join(export.TableDatasets, ","), "Comma-separated datasets: distribution, buckets, outcomes, trials")
+	fs.StringVar(&format, "format", export.FormatCSV, "Export format: csv, xlsx or parquet")
+	fs.StringVar(&outDir, "out", ".", "Directory to write <mode>_<dataset>.<format> files to")
+	fs.IntVar(&spins, "spins", common.DefaultSpins, "Spins per trial for the trials dataset")
+	fs.IntVar(&trials, "trials", common.DefaultTrials, "Number of trials for the trials dataset")
+	fs.Int64Var(&seed, "seed", 0, "RNG seed for a reproducible trials dataset (0 picks one)")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if !export.ValidFormat(format) {
+		fmt.Fprintf(stderr, "Error: -format must be csv, xlsx or parquet\n")
+		return ExitUsage
+	}
+	if spins <= 0 || spins > common.MaxSpins {
+		fmt.Fprintf(stderr, "Error: -spins must be between 1 and %d\n", common.MaxSpins)
+		return ExitUsage
+	}
+	if trials <= 0 || trials > common.MaxTrials {
+		fmt.Fprintf(stderr, "Error: -trials must be between 1 and %d\n", common.MaxTrials)
+		return ExitUsage
+	}
+
+	var datasets []string
+	for _, name := range strings.Split(data, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !export.ValidDataset(name) {
+			fmt.Fprintf(stderr, "Error: unknown dataset %q (want distribution, buckets, outcomes or trials)\n", name)
+			return ExitUsage
+		}
+		datasets = append(datasets, name)
+	}
+	if len(datasets) == 0 {
+		fmt.Fprintf(stderr, "Error: -data selects no datasets\n")
+		return ExitUsage
+	}
+
+	loader, modes, err := loadLibrary(flags)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	var files []exportedFile
+	for _, mode := range modes {
+		table, err := loader.GetMode(mode)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+
+		for _, name := range datasets {
+			var d *export.Dataset
+			if name == export.DatasetTrials {
+				// Use mode cost as bet, same as the API
+				bet := table.Cost
+				if bet <= 0 {
+					bet = 1.0
+				}
+				d = export.Trials(loader.Simulator().RunSimulation(table, lut.SimulationConfig{
+					Spins:             spins,
+					Trials:            trials,
+					Bet:               bet,
+					TargetRTP:         0.97,
+					TestSpins:         []int{100, 500, 1000},
+					Seed:              seed,
+					AllTrialSummaries: true,
+				}))
+			} else {
+				d, _ = export.TableDataset(name, table, loader.Analyzer())
+			}
+
+			path := filepath.Join(outDir, export.FileName(mode, d, format))
+			if err := writeExportFile(path, d, format); err != nil {
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				return ExitUsage
+			}
+			files = append(files, exportedFile{Mode: mode, Dataset: name, Path: path, Rows: d.Rows})
+		}
+	}
+
+	if flags.json {
+		if err := writeJSON(stdout, files); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+		return ExitOK
+	}
+	for _, f := range files {
+		fmt.Fprintf(stdout, "%s (%d rows)\n", f.Path, f.Rows)
+	}
+	return ExitOK
+}
+
+// writeExportFile writes the dataset to the file at path, removing the file
+// if it cannot be written in full.
+func writeExportFile(path string, d *export.Dataset, format string) error {
+	if err := export.Check(d, format); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export.Write(f, d, format); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
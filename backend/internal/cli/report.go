@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/lut"
+	"lutexplorer/internal/report"
+)
+
+// runReport writes a certification report for the selected modes.
+func runReport(args []string, stdout, stderr io.Writer) int {
+	var flags commonFlags
+	var opts report.Options
+	var format, output string
+
+	fs := newFlagSet("report", stderr)
+	flags.register(fs)
+	fs.StringVar(&format, "format", report.FormatHTML, "Report format: html or pdf")
+	fs.StringVar(&output, "o", "", "File to write the report to (default: stdout)")
+	fs.StringVar(&opts.Title, "title", report.DefaultTitle, "Report title")
+	fs.StringVar(&opts.Profile, "profile", lut.DefaultComplianceProfileName, "Compliance profile to apply (see "+lut.ComplianceProfilesFile+")")
+	fs.IntVar(&opts.Spins, "spins", common.DefaultSpins, "Simulation spins per trial")
+	fs.IntVar(&opts.Trials, "trials", common.DefaultTrials, "Simulation trials per mode")
+	fs.Int64Var(&opts.Seed, "seed", 0, "RNG seed for a reproducible run (0 picks one and prints it in the report)")
+	fs.IntVar(&opts.DistributionRows, "rows", 0, "Rows of each payout distribution to include (0 = all)")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if format != report.FormatHTML && format != report.FormatPDF {
+		fmt.Fprintf(stderr, "Error: -format must be html or pdf\n")
+		return ExitUsage
+	}
+	if opts.Spins <= 0 || opts.Trials <= 0 {
+		fmt.Fprintf(stderr, "Error: -spins and -trials must be positive\n")
+		return ExitUsage
+	}
+
+	loader, modes, err := loadLibrary(flags)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	rep, err := report.Build(context.Background(), loader, modes, opts, nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	if output == "" {
+		err = writeReport(stdout, rep, format, flags.json)
+	} else {
+		err = writeReportFile(output, rep, format, flags.json)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+	return ExitOK
+}
+
+// writeReport writes the report in format, or its data as JSON.
+func writeReport(w io.Writer, rep *report.Report, format string, asJSON bool) error {
+	switch {
+	case asJSON:
+		return writeJSON(w, rep)
+	case format == report.FormatPDF:
+		return rep.WritePDF(w)
+	default:
+		return rep.WriteHTML(w)
+	}
+}
+
+// writeReportFile writes the report to the file at path.
+func writeReportFile(path string, rep *report.Report, format string, asJSON bool) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeReport(f, rep, format, asJSON); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
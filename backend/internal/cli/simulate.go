@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/lut"
+)
+
+// runSimulate runs a Monte Carlo simulation for each selected mode.
+func runSimulate(args []string, stdout, stderr io.Writer) int {
+	var flags commonFlags
+	var spins, trials int
+	var targetRTP float64
+	var testSpins string
+	var seed int64
+
+	fs := newFlagSet("simulate", stderr)
+	flags.register(fs)
+	fs.IntVar(&spins, "spins", common.DefaultSpins, "Spins per trial")
+	fs.IntVar(&trials, "trials", common.DefaultTrials, "Number of trials")
+	fs.Float64Var(&targetRTP, "target-rtp", 0.97, "Target RTP threshold for success rates")
+	fs.StringVar(&testSpins, "test-spins", "100,500,1000", "Comma-separated spin counts to test RTP at")
+	fs.Int64Var(&seed, "seed", 0, "RNG seed for a reproducible run (0 picks one and prints it)")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if spins <= 0 || spins > common.MaxSpins {
+		fmt.Fprintf(stderr, "Error: -spins must be between 1 and %d\n", common.MaxSpins)
+		return ExitUsage
+	}
+	if trials <= 0 || trials > common.MaxTrials {
+		fmt.Fprintf(stderr, "Error: -trials must be between 1 and %d\n", common.MaxTrials)
+		return ExitUsage
+	}
+
+	testPoints, err := parseIntList(testSpins)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: invalid -test-spins: %v\n", err)
+		return ExitUsage
+	}
+
+	loader, modes, err := loadLibrary(flags)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	results := make([]*lut.SimulationResult, 0, len(modes))
+	for _, mode := range modes {
+		table, err := loader.GetMode(mode)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+
+		// Use mode cost as bet, same as the API
+		bet := table.Cost
+		if bet <= 0 {
+			bet = 1.0
+		}
+
+		results = append(results, loader.Simulator().RunSimulation(table, lut.SimulationConfig{
+			Spins:     spins,
+			Trials:    trials,
+			Bet:       bet,
+			TargetRTP: targetRTP,
+			TestSpins: testPoints,
+			Seed:      seed,
+		}))
+	}
+
+	if flags.json {
+		if err := writeJSON(stdout, results); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+		return ExitOK
+	}
+
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		printSimulation(stdout, r)
+	}
+	return ExitOK
+}
+
+// printSimulation prints a human-readable simulation summary.
+func printSimulation(w io.Writer, r *lut.SimulationResult) {
+	fmt.Fprintf(w, "Mode %q: %d trials x %d spins, seed %d (%dms)\n", r.Mode, r.Config.Trials, r.Config.Spins, r.Config.Seed, r.DurationMs)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if d := r.Diagnostics; d != nil {
+		fmt.Fprintf(tw, "  Actual RTP\t%.4f%% ± %.4f%% (95%% CI %.4f%% - %.4f%%)\n",
+			r.ActualRTP*100, d.RTP.StdError*100, d.RTP.CI95.Low*100, d.RTP.CI95.High*100)
+		fmt.Fprintf(tw, "  Theoretical RTP\t%.4f%% (99%% range %.4f%% - %.4f%%)%s\n",
+			d.TheoreticalRTP*100, d.ExpectedRTPRange.Low*100, d.ExpectedRTPRange.High*100, outOfRange(d.RTPOutOfRange))
+		fmt.Fprintf(tw, "  Hit rate\t%.2f%% (95%% CI %.2f%% - %.2f%%)\n", r.HitRate*100, d.HitRate.CI95.Low*100, d.HitRate.CI95.High*100)
+		fmt.Fprintf(tw, "  Theoretical hit rate\t%.2f%% (99%% range %.2f%% - %.2f%%)%s\n",
+			d.TheoreticalHitRate*100, d.ExpectedHitRateRange.Low*100, d.ExpectedHitRateRange.High*100, outOfRange(d.HitRateOutOfRange))
+	} else {
+		fmt.Fprintf(tw, "  Actual RTP\t%.4f%%\n", r.ActualRTP*100)
+		fmt.Fprintf(tw, "  Hit rate\t%.2f%%\n", r.HitRate*100)
+	}
+	fmt.Fprintf(tw, "  Max win\t%.2fx\n", r.MaxWin)
+	fmt.Fprintf(tw, "  Big / mega wins\t%d / %d\n", r.BigWins, r.MegaWins)
+	for _, p := range r.RTPatSpins {
+		fmt.Fprintf(tw, "  RTP >= %.2f%% at %d spins\t%.2f%% of trials (95%% CI %.2f%% - %.2f%%)\n",
+			r.Config.TargetRTP*100, p.SpinCount, p.SuccessRate*100, p.CI95.Low*100, p.CI95.High*100)
+	}
+	if d := r.Diagnostics; d != nil {
+		for _, p := range d.SpinsForPrecision {
+			fmt.Fprintf(tw, "  Spins for RTP ± %g%%\t%d\n", p.Precision*100, p.Spins)
+		}
+	}
+	tw.Flush()
+}
+
+// outOfRange marks a simulated value outside its theoretical range.
+func outOfRange(out bool) string {
+	if out {
+		return "  OUT OF RANGE"
+	}
+	return ""
+}
+
+// parseIntList parses a comma-separated list of positive integers.
+func parseIntList(s string) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		if v <= 0 {
+			return nil, fmt.Errorf("value must be positive: %d", v)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
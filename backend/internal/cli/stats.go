@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"lutexplorer/internal/lut"
+)
+
+// runStats prints LUT statistics for each selected mode.
+func runStats(args []string, stdout, stderr io.Writer) int {
+	var common commonFlags
+	var top int
+
+	fs := newFlagSet("stats", stderr)
+	common.register(fs)
+	fs.IntVar(&top, "top", 5, "Number of top payouts to print in text mode")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	loader, modes, err := loadLibrary(common)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitUsage
+	}
+
+	analyzer := loader.Analyzer()
+	stats := make([]*lut.Statistics, 0, len(modes))
+	for _, mode := range modes {
+		table, err := loader.GetMode(mode)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+		stats = append(stats, analyzer.Analyze(table))
+	}
+
+	if common.json {
+		if err := writeJSON(stdout, stats); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitUsage
+		}
+		return ExitOK
+	}
+
+	for i, s := range stats {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		printStats(stdout, s, top)
+	}
+	return ExitOK
+}
+
+// printStats prints a human-readable summary of a mode's statistics.
+func printStats(w io.Writer, s *lut.Statistics, top int) {
+	fmt.Fprintf(w, "Mode %q\n", s.Mode)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  Cost\t%.2fx\n", s.Cost)
+	fmt.Fprintf(tw, "  Outcomes\t%d\n", s.TotalOutcomes)
+	fmt.Fprintf(tw, "  Total weight\t%d\n", s.TotalWeight)
+	fmt.Fprintf(tw, "  RTP\t%.4f%%\n", s.RTP*100)
+	fmt.Fprintf(tw, "  Hit rate\t%.2f%%\n", s.HitRate*100)
+	fmt.Fprintf(tw, "  Zero payout rate\t%.2f%%\n", s.ZeroPayoutRate*100)
+	fmt.Fprintf(tw, "  Breakeven rate\t%.2f%%\n", s.BreakevenRate*100)
+	fmt.Fprintf(tw, "  Max payout\t%.2fx\n", s.MaxPayout)
+	fmt.Fprintf(tw, "  Mean / median payout\t%.4fx / %.2fx\n", s.MeanPayout, s.MedianPayout)
+	fmt.Fprintf(tw, "  Std dev\t%.4f\n", s.StdDev)
+	fmt.Fprintf(tw, "  Volatility\t%.4f\n", s.Volatility)
+	tw.Flush()
+
+	if top > 0 && len(s.TopPayouts) > 0 {
+		fmt.Fprintln(w, "  Top payouts:")
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for i, p := range s.TopPayouts {
+			if i >= top {
+				break
+			}
+			fmt.Fprintf(tw, "    %.2fx\t%s\t%d outcome(s)\tsim_id %d\n", p.Payout, p.Odds, p.Count, p.SimID)
+		}
+		tw.Flush()
+	}
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// MaxSeed bounds generated seeds so they survive a round trip through
+// JavaScript numbers, which are exact only up to 2^53.
+const MaxSeed = 1 << 53
+
+// NewSeed returns a random seed in [1, MaxSeed). Zero is reserved to mean
+// "pick a seed" in request bodies.
+func NewSeed() int64 {
+	return randv2.Int64N(MaxSeed-1) + 1
+}
+
+// DeriveSeed returns the seed of an independent stream of a seeded run, such as
+// one trial or one player. The same seed and stream always give the same result,
+// so streams can be simulated in any order or in parallel.
+func DeriveSeed(seed int64, stream int64) int64 {
+	// SplitMix64 finalizer over the seed and stream index
+	z := uint64(seed) + uint64(stream+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// NewRand returns a math/rand generator for a stream of a seeded run.
+func NewRand(seed int64, stream int64) *rand.Rand {
+	return rand.New(rand.NewSource(DeriveSeed(seed, stream)))
+}
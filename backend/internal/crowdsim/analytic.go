@@ -0,0 +1,388 @@
+package crowdsim
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"time"
+
+	"stakergs"
+)
+
+// Bin limits for the analytic engine. The grid must hold every possible
+// session total, so long sessions or large max wins use coarser bins.
+const (
+	DefaultAnalyticMaxBins = 1 << 18
+	MaxAnalyticBins        = 1 << 22
+
+	analyticHistogramBuckets = 40
+)
+
+// AnalyticResult is the exact distribution of session outcomes for players
+// who play every spin of a session, computed by convolving the table's
+// payout distribution instead of simulating players.
+type AnalyticResult struct {
+	Mode            string  `json:"mode"`
+	SpinsPerSession int     `json:"spins_per_session"`
+	InitialBalance  float64 `json:"initial_balance"`
+	TheoreticalRTP  float64 `json:"theoretical_rtp"`
+	DurationMs      int64   `json:"duration_ms"`
+
+	// Session totals are counted on a grid of GridStep bets. When every
+	// payout is a multiple of the step the result is exact up to float
+	// rounding; otherwise payouts are split between their two neighbouring
+	// bins, which keeps the mean exact and slightly widens the spread.
+	GridStep float64 `json:"grid_step"`
+	Bins     int     `json:"bins"`
+	Exact    bool    `json:"exact"`
+
+	// Same meaning as the CrowdSim fields, so the two can be compared directly
+	FinalPoP     float64             `json:"final_pop"`
+	PoPCurve     []float64           `json:"pop_curve"`
+	BalanceCurve []BalanceCurvePoint `json:"balance_curve"`
+	BalanceStats BalanceStats        `json:"balance_stats"`
+
+	// Distribution of the total returned after the session, in bets
+	ReturnDistribution []ReturnBucket `json:"return_distribution"`
+}
+
+// ReturnBucket is the probability that a session returns between RangeStart
+// and RangeEnd bets in total. The first and last buckets include the tails.
+type ReturnBucket struct {
+	RangeStart  float64 `json:"range_start"`
+	RangeEnd    float64 `json:"range_end"`
+	Probability float64 `json:"probability"`
+}
+
+// AnalyticRequest is the request body for analytic session distributions.
+type AnalyticRequest struct {
+	Config  SimConfig `json:"config"`   // Spins per session and initial balance; the rest is used by Compare
+	MaxBins int       `json:"max_bins"` // Grid size cap (0 = DefaultAnalyticMaxBins)
+	Compare bool      `json:"compare"`  // Also run CrowdSim with Config and compare the PoP curves
+}
+
+// AnalyticResponse holds the analytic result and, when requested, the
+// CrowdSim run it was compared against.
+type AnalyticResponse struct {
+	Analytic   *AnalyticResult `json:"analytic"`
+	Simulation *SimResult      `json:"simulation,omitempty"`
+	Comparison *PoPComparison  `json:"comparison,omitempty"`
+}
+
+// PoPComparison compares a simulated PoP curve against the analytic one.
+type PoPComparison struct {
+	FinalDeviation   float64 `json:"final_deviation"` // Simulated - analytic final PoP
+	MaxDeviation     float64 `json:"max_deviation"`   // Largest absolute gap over the session
+	MaxDeviationSpin int     `json:"max_deviation_spin"`
+	MeanAbsDeviation float64 `json:"mean_abs_deviation"`
+}
+
+// CalcAnalyticSession computes the distribution of session outcomes after
+// each of spins spins. Balances and PoP follow CrowdSim: bets are one unit,
+// payouts are normalized by the mode cost and players never stop early.
+// maxBins caps the grid size; 0 uses DefaultAnalyticMaxBins.
+func CalcAnalyticSession(ctx context.Context, lut *stakergs.LookupTable, spins int, initialBalance float64, maxBins int) (*AnalyticResult, error) {
+	start := time.Now()
+	if spins <= 0 {
+		return nil, fmt.Errorf("spins_per_session must be positive: %d", spins)
+	}
+	if maxBins <= 0 {
+		maxBins = DefaultAnalyticMaxBins
+	}
+	if maxBins > MaxAnalyticBins {
+		return nil, fmt.Errorf("max_bins exceeds maximum (%d): %d", MaxAnalyticBins, maxBins)
+	}
+	totalWeight := lut.TotalWeight()
+	if len(lut.Outcomes) == 0 || totalWeight == 0 {
+		return nil, fmt.Errorf("mode %s has no weighted outcomes", lut.Mode)
+	}
+
+	modeCost := lut.Cost
+	if modeCost <= 0 {
+		modeCost = 1.0
+	}
+	betCents := modeCost * 100
+
+	var maxCents uint
+	for _, o := range lut.Outcomes {
+		if o.Weight > 0 && o.Payout > maxCents {
+			maxCents = o.Payout
+		}
+	}
+	step, perSpin, err := analyticGrid(maxCents, spins, maxBins)
+	if err != nil {
+		return nil, err
+	}
+	support := spins*perSpin + 1
+	size := 1 << bits.Len(uint(support-1))
+
+	// Payout distribution of one spin on the grid
+	pmf := make([]complex128, size)
+	exact := true
+	for _, o := range lut.Outcomes {
+		if o.Weight == 0 {
+			continue
+		}
+		p := float64(o.Weight) / float64(totalWeight)
+		lo := o.Payout / step
+		rem := o.Payout % step
+		if rem == 0 {
+			pmf[lo] += complex(p, 0)
+			continue
+		}
+		exact = false
+		frac := float64(rem) / float64(step)
+		pmf[lo] += complex(p*(1-frac), 0)
+		pmf[lo+1] += complex(p*frac, 0)
+	}
+	plan := newFFTPlan(size)
+	plan.transform(pmf, false)
+
+	binBets := float64(step) / betCents
+	result := &AnalyticResult{
+		Mode:            lut.Mode,
+		SpinsPerSession: spins,
+		InitialBalance:  initialBalance,
+		TheoreticalRTP:  round4(lut.RTP()),
+		GridStep:        binBets,
+		Bins:            support,
+		Exact:           exact,
+		PoPCurve:        make([]float64, spins),
+	}
+	balanceAt := func(spin, bin int) float64 {
+		return initialBalance - float64(spin) + float64(bin)*binBets
+	}
+
+	// The PoP after n spins is the tail mass of the n-fold convolution from
+	// the break-even bin up. It is read straight from the spectrum: with
+	// roots[k] = e^(2πik/size), the tail from bin t is
+	// (1/size) Σ_j P_j^n (roots[j*t] - 1) / (1 - roots[j]), and size - t
+	// for j = 0.
+	roots := make([]complex128, size)
+	denominators := make([]complex128, size)
+	for k := range roots {
+		roots[k] = cmplx.Rect(1, 2*math.Pi*float64(k)/float64(size))
+		if k > 0 {
+			denominators[k] = 1 / (1 - roots[k])
+		}
+	}
+	power := make([]complex128, size)
+	for j := range power {
+		power[j] = 1
+	}
+	sampleInterval := spins / 30
+	if sampleInterval < 1 {
+		sampleInterval = 1
+	}
+	result.BalanceCurve = append(result.BalanceCurve, BalanceCurvePoint{
+		Spin: 0, Avg: round2(initialBalance), Median: round2(initialBalance), P5: round2(initialBalance), P95: round2(initialBalance),
+	})
+
+	var dist []float64
+	for n := 1; n <= spins; n++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for j := range power {
+			power[j] *= pmf[j]
+		}
+
+		threshold := int(math.Ceil(float64(n)*betCents/float64(step) - 1e-9))
+		var pop float64
+		switch {
+		case threshold <= 0:
+			pop = 1
+		case threshold > n*perSpin:
+			pop = 0
+		default:
+			sum := power[0] * complex(float64(size-threshold), 0)
+			for j := 1; j < size; j++ {
+				sum += power[j] * (roots[(j*threshold)%size] - 1) * denominators[j]
+			}
+			pop = clamp01(real(sum) / float64(size))
+		}
+		result.PoPCurve[n-1] = round4(pop)
+
+		if n%sampleInterval == 0 || n == spins {
+			dist = inverseDistribution(plan, power, n*perSpin+1)
+			balances := make([]float64, n*perSpin+1)
+			for bin := range balances {
+				balances[bin] = balanceAt(n, bin)
+			}
+			var mean float64
+			for bin, p := range dist {
+				mean += p * balances[bin]
+			}
+			result.BalanceCurve = append(result.BalanceCurve, BalanceCurvePoint{
+				Spin:   n,
+				Avg:    round2(mean),
+				Median: round2(balances[quantileBin(dist, 0.50)]),
+				P5:     round2(balances[quantileBin(dist, 0.05)]),
+				P95:    round2(balances[quantileBin(dist, 0.95)]),
+			})
+		}
+	}
+	result.FinalPoP = result.PoPCurve[spins-1]
+
+	result.BalanceStats = analyticBalanceStats(dist, func(bin int) float64 { return balanceAt(spins, bin) })
+	result.ReturnDistribution = returnHistogram(dist, binBets)
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// ComparePoPCurves compares a simulated PoP curve with the analytic curve of
+// the same session length.
+func ComparePoPCurves(simulated, analytic []float64) PoPComparison {
+	n := len(simulated)
+	if len(analytic) < n {
+		n = len(analytic)
+	}
+	if n == 0 {
+		return PoPComparison{}
+	}
+
+	var cmp PoPComparison
+	var sumAbs float64
+	for i := 0; i < n; i++ {
+		d := math.Abs(simulated[i] - analytic[i])
+		sumAbs += d
+		if d > cmp.MaxDeviation {
+			cmp.MaxDeviation = d
+			cmp.MaxDeviationSpin = i + 1
+		}
+	}
+	cmp.MaxDeviation = round4(cmp.MaxDeviation)
+	cmp.MeanAbsDeviation = round4(sumAbs / float64(n))
+	cmp.FinalDeviation = math.Round((simulated[n-1]-analytic[n-1])*10000) / 10000
+	return cmp
+}
+
+// analyticGrid picks the finest grid step, in payout cents from the 1-2-5
+// series, whose bins hold every session total within maxBins. It returns the
+// step and the bins one spin can move a session total.
+func analyticGrid(maxCents uint, spins, maxBins int) (step uint, perSpin int, err error) {
+	for _, decade := range []uint{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000} {
+		for _, mult := range []uint{1, 2, 5} {
+			step = decade * mult
+			perSpin = int((maxCents + step - 1) / step)
+			if spins*perSpin+1 <= maxBins {
+				return step, perSpin, nil
+			}
+			if step > maxCents {
+				return 0, 0, fmt.Errorf("max_bins (%d) is too small for %d spins", maxBins, spins)
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("max payout %d is too large for the analytic grid", maxCents)
+}
+
+// inverseDistribution transforms a spectrum back to probabilities and returns
+// the first n bins. Float noise below zero is clamped.
+func inverseDistribution(plan *fftPlan, spectrum []complex128, n int) []float64 {
+	buf := make([]complex128, len(spectrum))
+	copy(buf, spectrum)
+	plan.transform(buf, true)
+
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Max(0, real(buf[i]))
+	}
+	return dist
+}
+
+// quantileBin returns the first bin whose cumulative probability reaches q.
+func quantileBin(dist []float64, q float64) int {
+	var cum float64
+	for bin, p := range dist {
+		cum += p
+		if cum >= q-1e-12 {
+			return bin
+		}
+	}
+	return len(dist) - 1
+}
+
+// analyticBalanceStats summarizes a balance distribution like CalcBalanceStats
+// summarizes players. Min and max are the extremes with any probability.
+func analyticBalanceStats(dist []float64, balance func(bin int) float64) BalanceStats {
+	if len(dist) == 0 {
+		return BalanceStats{}
+	}
+
+	var mean, total float64
+	for bin, p := range dist {
+		mean += p * balance(bin)
+		total += p
+	}
+	var variance float64
+	for bin, p := range dist {
+		d := balance(bin) - mean
+		variance += p * d * d
+	}
+
+	lo, hi := 0, len(dist)-1
+	for lo < hi && dist[lo] < 1e-15 {
+		lo++
+	}
+	for hi > lo && dist[hi] < 1e-15 {
+		hi--
+	}
+
+	percentiles := make(map[string]float64)
+	for _, p := range []int{5, 10, 25, 50, 75, 90, 95} {
+		percentiles[fmt.Sprint(p)] = round2(balance(quantileBin(dist, float64(p)/100)))
+	}
+
+	return BalanceStats{
+		Mean:        round2(mean / total),
+		Median:      percentiles["50"],
+		StdDev:      round2(math.Sqrt(variance / total)),
+		Min:         round2(balance(lo)),
+		Max:         round2(balance(hi)),
+		Percentiles: percentiles,
+	}
+}
+
+// returnHistogram buckets the session return, in bets, between its 0.1st and
+// 99.9th percentiles.
+func returnHistogram(dist []float64, binBets float64) []ReturnBucket {
+	if len(dist) == 0 {
+		return nil
+	}
+	lo, hi := quantileBin(dist, 0.001), quantileBin(dist, 0.999)
+	if hi <= lo {
+		hi = lo + 1
+	}
+
+	width := float64(hi-lo) / analyticHistogramBuckets
+	if width < 1 {
+		width = 1
+	}
+	count := int(math.Ceil(float64(hi-lo) / width))
+	buckets := make([]ReturnBucket, count)
+	for i := range buckets {
+		buckets[i].RangeStart = round2((float64(lo) + float64(i)*width) * binBets)
+		buckets[i].RangeEnd = round2((float64(lo) + float64(i+1)*width) * binBets)
+	}
+	for bin, p := range dist {
+		i := int(float64(bin-lo) / width)
+		if bin < lo {
+			i = 0
+		}
+		if i >= count {
+			i = count - 1
+		}
+		buckets[i].Probability += p
+	}
+
+	for i := range buckets {
+		buckets[i].Probability = math.Round(buckets[i].Probability*1e6) / 1e6
+	}
+	return buckets
+}
+
+func clamp01(v float64) float64 {
+	return math.Min(1, math.Max(0, v))
+}
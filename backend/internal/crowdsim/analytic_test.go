@@ -0,0 +1,120 @@
+package crowdsim
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"stakergs"
+)
+
+func standardTable() *stakergs.LookupTable {
+	return &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 600, Payout: 0},
+		{SimID: 1, Weight: 250, Payout: 50},
+		{SimID: 2, Weight: 100, Payout: 200},
+		{SimID: 3, Weight: 45, Payout: 1000},
+		{SimID: 4, Weight: 5, Payout: 10000},
+	}}
+}
+
+func TestCalcAnalyticSession_MatchesBinomial(t *testing.T) {
+	// Each spin pays 0 or 2x with equal chance, so a session is in profit
+	// when at least half its spins win
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 1, Payout: 0},
+		{SimID: 1, Weight: 1, Payout: 200},
+	}}
+	result, err := CalcAnalyticSession(context.Background(), table, 60, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Exact {
+		t.Error("expected an exact grid")
+	}
+
+	for n := 1; n <= 60; n++ {
+		var want float64
+		for k := (n + 1) / 2; k <= n; k++ {
+			want += binomial(n, k) / math.Pow(2, float64(n))
+		}
+		if math.Abs(result.PoPCurve[n-1]-want) > 1e-4 {
+			t.Errorf("PoP after %d spins = %v, want %.4f", n, result.PoPCurve[n-1], want)
+		}
+	}
+	if result.BalanceStats.Mean != 100 || result.BalanceStats.Median != 100 {
+		t.Errorf("fair game should keep mean and median at 100: %+v", result.BalanceStats)
+	}
+	if want := math.Sqrt(60); math.Abs(result.BalanceStats.StdDev-want) > 0.01 {
+		t.Errorf("std dev %v, want %.2f", result.BalanceStats.StdDev, want)
+	}
+}
+
+func TestCalcAnalyticSession_AgreesWithCrowdSim(t *testing.T) {
+	table := standardTable()
+	config := DefaultConfig()
+	config.PlayerCount = 4000
+	config.SpinsPerSession = 100
+	config.Seed = 11
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	analytic, err := CalcAnalyticSession(context.Background(), table, config.SpinsPerSession, config.InitialBalance, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simulated := NewCrowdSimulator(table, nil, config).Run(nil)
+
+	// Four standard errors of a proportion over 4000 players
+	tolerance := 4 * math.Sqrt(0.25/4000)
+	cmp := ComparePoPCurves(simulated.PoPCurve, analytic.PoPCurve)
+	if cmp.MaxDeviation > tolerance {
+		t.Errorf("simulated PoP strays %.4f from theory at spin %d", cmp.MaxDeviation, cmp.MaxDeviationSpin)
+	}
+
+	// RTP 1.275 gains 0.275 bets a spin on average
+	if math.Abs(analytic.BalanceStats.Mean-127.5) > 0.01 {
+		t.Errorf("mean final balance %v, want 127.5", analytic.BalanceStats.Mean)
+	}
+	var total float64
+	for _, b := range analytic.ReturnDistribution {
+		total += b.Probability
+	}
+	if math.Abs(total-1) > 1e-4 {
+		t.Errorf("return distribution sums to %v", total)
+	}
+}
+
+func TestCalcAnalyticSession_CoarseGridKeepsMean(t *testing.T) {
+	table := standardTable()
+	fine, err := CalcAnalyticSession(context.Background(), table, 50, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 50 spins of up to 100x need 5001 bins at one bet per bin, so 4096
+	// bins force two bets per bin and split the 0.5x payouts
+	coarse, err := CalcAnalyticSession(context.Background(), table, 50, 100, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !fine.Exact || coarse.Exact || coarse.GridStep != 2 {
+		t.Fatalf("unexpected grids: fine exact=%v, coarse exact=%v step=%v", fine.Exact, coarse.Exact, coarse.GridStep)
+	}
+	if math.Abs(coarse.BalanceStats.Mean-fine.BalanceStats.Mean) > 0.01 {
+		t.Errorf("coarse mean %v, fine mean %v", coarse.BalanceStats.Mean, fine.BalanceStats.Mean)
+	}
+
+	if _, err := CalcAnalyticSession(context.Background(), table, 5000, 100, 4096); err == nil {
+		t.Error("expected an error when the sessions outgrow max_bins")
+	}
+}
+
+func binomial(n, k int) float64 {
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
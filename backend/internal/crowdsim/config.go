@@ -4,6 +4,8 @@ package crowdsim
 import (
 	"fmt"
 	"runtime"
+
+	"lutexplorer/internal/common"
 )
 
 // SimConfig holds simulation parameters.
@@ -12,11 +14,26 @@ type SimConfig struct {
 	SpinsPerSession int     `json:"spins_per_session"` // Spins per player session (1-1000)
 	InitialBalance  float64 `json:"initial_balance"`   // Starting balance
 	BetAmount       float64 `json:"bet_amount"`        // Bet per spin
-	BigWinThreshold float64 `json:"big_win_threshold"` // Multiplier threshold for "big win" (e.g., 10.0)
+	BigWinThreshold float64 `json:"big_win_threshold"` // Stake multiple for a "big win"; 0 = the game's big-win tier
 	DangerThreshold float64 `json:"danger_threshold"`  // Balance fraction for "danger" events (e.g., 0.1)
 	UseCryptoRNG    bool    `json:"use_crypto_rng"`    // Use crypto/rand for secure randomness
 	StreamingMode   bool    `json:"streaming_mode"`    // Memory-efficient mode (no full history)
 	ParallelWorkers int     `json:"parallel_workers"`  // Number of goroutines for simulation
+	Seed            int64   `json:"seed"`              // RNG seed (0 = random); ignored with crypto RNG
+
+	// Player behaviour mix; players are split between strategies by share.
+	// Without strategies every player bets the base bet for every spin.
+	Strategies []StrategyConfig `json:"strategies,omitempty"`
+
+	// Player population; players are split between segments by share, each
+	// with its own bankroll, stake and session length. Without a population
+	// every player starts with InitialBalance bets and plays every spin.
+	Population []PopulationSegment `json:"population,omitempty"`
+
+	// Mode mix every player follows: the chance each spin is played in
+	// another mode instead, when the balance covers its cost. Strategy bonus
+	// buys come first; the rest of the spins are in the simulated mode.
+	Journey []BonusBuy `json:"journey,omitempty"`
 }
 
 // DefaultConfig returns a reasonable default configuration.
@@ -26,7 +43,7 @@ func DefaultConfig() SimConfig {
 		SpinsPerSession: 200,
 		InitialBalance:  100.0,
 		BetAmount:       1.0,
-		BigWinThreshold: 10.0,
+		BigWinThreshold: 0,
 		DangerThreshold: 0.1,
 		UseCryptoRNG:    false,
 		StreamingMode:   false,
@@ -42,7 +59,7 @@ var (
 		SpinsPerSession: 100,
 		InitialBalance:  100.0,
 		BetAmount:       1.0,
-		BigWinThreshold: 10.0,
+		BigWinThreshold: 0,
 		DangerThreshold: 0.1,
 		UseCryptoRNG:    false,
 		StreamingMode:   false,
@@ -55,7 +72,7 @@ var (
 		SpinsPerSession: 200,
 		InitialBalance:  100.0,
 		BetAmount:       1.0,
-		BigWinThreshold: 10.0,
+		BigWinThreshold: 0,
 		DangerThreshold: 0.1,
 		UseCryptoRNG:    false,
 		StreamingMode:   false,
@@ -68,7 +85,7 @@ var (
 		SpinsPerSession: 300,
 		InitialBalance:  100.0,
 		BetAmount:       1.0,
-		BigWinThreshold: 10.0,
+		BigWinThreshold: 0,
 		DangerThreshold: 0.1,
 		UseCryptoRNG:    true,
 		StreamingMode:   false,
@@ -104,8 +121,8 @@ func (c *SimConfig) Validate() error {
 		return fmt.Errorf("bet_amount (%v) cannot exceed initial_balance (%v)", c.BetAmount, c.InitialBalance)
 	}
 
-	if c.BigWinThreshold <= 0 {
-		c.BigWinThreshold = 10.0
+	if c.BigWinThreshold < 0 {
+		c.BigWinThreshold = 0
 	}
 
 	if c.DangerThreshold <= 0 || c.DangerThreshold >= 1.0 {
@@ -119,9 +136,60 @@ func (c *SimConfig) Validate() error {
 		c.ParallelWorkers = 64
 	}
 
+	names := make(map[string]bool, len(c.Strategies))
+	for i := range c.Strategies {
+		if err := c.Strategies[i].Validate(i); err != nil {
+			return err
+		}
+		if names[c.Strategies[i].Name] {
+			return fmt.Errorf("duplicate strategy name: %s", c.Strategies[i].Name)
+		}
+		names[c.Strategies[i].Name] = true
+	}
+
+	segments := make(map[string]bool, len(c.Population))
+	for i := range c.Population {
+		if err := c.Population[i].Validate(i); err != nil {
+			return err
+		}
+		if segments[c.Population[i].Name] {
+			return fmt.Errorf("duplicate segment name: %s", c.Population[i].Name)
+		}
+		segments[c.Population[i].Name] = true
+	}
+
+	if err := validateModeMix("journey", c.Journey); err != nil {
+		return err
+	}
+
+	// Pick the seed here so modes compared in one request share it
+	if c.Seed == 0 && !c.UseCryptoRNG {
+		c.Seed = common.NewSeed()
+	}
+
 	return nil
 }
 
+// BonusModes returns the modes the journey and strategies buy, in
+// first-use order.
+func (c *SimConfig) BonusModes() []string {
+	var modes []string
+	seen := make(map[string]bool)
+	add := func(buys []BonusBuy) {
+		for _, b := range buys {
+			if !seen[b.Mode] {
+				seen[b.Mode] = true
+				modes = append(modes, b.Mode)
+			}
+		}
+	}
+	add(c.Journey)
+	for _, s := range c.Strategies {
+		add(s.BonusBuys)
+	}
+	return modes
+}
+
 // RankingWeights holds weights for composite score calculation.
 type RankingWeights struct {
 	ProfitWeight     float64 `json:"profit_weight"`     // Weight for PoP
@@ -0,0 +1,61 @@
+package crowdsim
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// fftPlan holds the twiddle factors for radix-2 FFTs of one power-of-two
+// size, so repeated transforms skip the trigonometry.
+type fftPlan struct {
+	size     int
+	twiddles []complex128 // e^(-2πik/size) for k < size/2
+}
+
+func newFFTPlan(size int) *fftPlan {
+	p := &fftPlan{size: size, twiddles: make([]complex128, size/2)}
+	for k := range p.twiddles {
+		p.twiddles[k] = cmplx.Rect(1, -2*math.Pi*float64(k)/float64(size))
+	}
+	return p
+}
+
+// transform transforms a in place; len(a) must be the plan size. The inverse
+// transform includes the 1/size scaling.
+func (p *fftPlan) transform(a []complex128, invert bool) {
+	n := p.size
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		half := length / 2
+		stride := n / length
+		for i := 0; i < n; i += length {
+			for k := 0; k < half; k++ {
+				w := p.twiddles[k*stride]
+				if invert {
+					w = cmplx.Conj(w)
+				}
+				u := a[i+k]
+				v := a[i+k+half] * w
+				a[i+k] = u + v
+				a[i+k+half] = u - v
+			}
+		}
+	}
+
+	if invert {
+		scale := complex(1/float64(n), 0)
+		for i := range a {
+			a[i] *= scale
+		}
+	}
+}
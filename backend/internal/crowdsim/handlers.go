@@ -1,28 +1,70 @@
 package crowdsim
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 
 	"lutexplorer/internal/common"
+	"lutexplorer/internal/jobs"
 	"lutexplorer/internal/lut"
 	"lutexplorer/internal/ws"
+	"stakergs"
 )
 
 // Handlers provides HTTP handlers for CrowdSim API.
 type Handlers struct {
 	loader *lut.Loader
 	hub    *ws.Hub
+	jobs   *jobs.Manager
 }
 
 // NewHandlers creates new CrowdSim handlers.
-func NewHandlers(loader *lut.Loader, hub *ws.Hub) *Handlers {
+func NewHandlers(loader *lut.Loader, hub *ws.Hub, jobManager *jobs.Manager) *Handlers {
 	return &Handlers{
 		loader: loader,
 		hub:    hub,
+		jobs:   jobManager,
 	}
 }
 
+// decodeConfig reads a SimConfig from the request body, using the defaults
+// for an empty body, and validates it.
+func decodeConfig(r *http.Request) (SimConfig, error) {
+	var config SimConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		// io.EOF means empty body - use defaults
+		// Other errors indicate invalid JSON
+		if err.Error() != "EOF" {
+			return config, fmt.Errorf("invalid request body: %w", err)
+		}
+		config = DefaultConfig()
+	}
+
+	// Validate and apply defaults
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// newSimulator creates a simulator for table with the library's win tiers
+// and the bonus modes the config's journey and strategies buy.
+func (h *Handlers) newSimulator(table *stakergs.LookupTable, config SimConfig) (*CrowdSimulator, error) {
+	simulator := NewCrowdSimulator(table, h.loader.Sampler(table, 0), config)
+	simulator.SetWinTiers(h.loader.WinTiers())
+	for _, mode := range config.BonusModes() {
+		bonus, err := h.loader.GetMode(mode)
+		if err != nil {
+			return nil, fmt.Errorf("bonus mode %s: %w", mode, err)
+		}
+		simulator.AddBonusMode(bonus, h.loader.Sampler(bonus, 0))
+	}
+	return simulator, nil
+}
+
 // HandleSimulate runs a CrowdSim simulation for a single mode.
 // POST /api/crowdsim/{mode}/simulate
 func (h *Handlers) HandleSimulate(w http.ResponseWriter, r *http.Request) {
@@ -39,25 +81,18 @@ func (h *Handlers) HandleSimulate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse config from request body
-	var config SimConfig
-	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
-		// io.EOF means empty body - use defaults
-		// Other errors indicate invalid JSON
-		if err.Error() != "EOF" {
-			common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
-			return
-		}
-		config = DefaultConfig()
-	}
-
-	// Validate and apply defaults
-	if err := config.Validate(); err != nil {
+	config, err := decodeConfig(r)
+	if err != nil {
 		common.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Create simulator
-	simulator := NewCrowdSimulator(table, config)
+	simulator, err := h.newSimulator(table, config)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Run simulation with progress reporting via WebSocket
 	var result *SimResult
@@ -107,6 +142,192 @@ func (h *Handlers) HandleSimulate(w http.ResponseWriter, r *http.Request) {
 	common.WriteSuccess(w, result)
 }
 
+// HandleSimulateJob starts a CrowdSim simulation as a background job. Progress
+// is broadcast as job_progress messages; the result is fetched from /api/jobs.
+// POST /api/crowdsim/{mode}/jobs
+func (h *Handlers) HandleSimulateJob(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := h.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	config, err := decodeConfig(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	simulator, err := h.newSimulator(table, config)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	info := h.jobs.Start(jobs.KindCrowdSim, mode, func(ctx context.Context, report func(jobs.Progress)) (interface{}, error) {
+		return simulator.RunContext(ctx, func(p Progress) {
+			report(jobs.Progress{
+				Done:   int64(p.PlayersComplete),
+				Total:  int64(p.TotalPlayers),
+				Detail: p,
+			})
+		})
+	})
+	jobs.WriteStarted(w, info)
+}
+
+// HandleAnalytic computes session outcome distributions for a mode by
+// convolution rather than simulation, optionally comparing them to CrowdSim.
+// POST /api/crowdsim/{mode}/analytic
+func (h *Handlers) HandleAnalytic(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := h.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	req := AnalyticRequest{Config: DefaultConfig()}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := req.Config.Validate(); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	analytic, err := CalcAnalyticSession(r.Context(), table, req.Config.SpinsPerSession, req.Config.InitialBalance, req.MaxBins)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	response := AnalyticResponse{Analytic: analytic}
+
+	if req.Compare {
+		// Full history is needed for the simulated PoP curve, and the
+		// analytic model is of players who play every spin
+		req.Config.StreamingMode = false
+		req.Config.Strategies = nil
+		req.Config.Journey = nil
+		simulator, err := h.newSimulator(table, req.Config)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		simulation, err := simulator.RunContext(r.Context(), nil)
+		if err != nil {
+			common.WriteError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		simulation.PlayerSummaries = nil
+		comparison := ComparePoPCurves(simulation.PoPCurve, analytic.PoPCurve)
+		response.Simulation = simulation
+		response.Comparison = &comparison
+	}
+
+	common.WriteSuccess(w, response)
+}
+
+// decodeRuinConfig reads a RuinConfig from the request body, using the
+// defaults for an empty body, and validates it.
+func decodeRuinConfig(r *http.Request) (RuinConfig, error) {
+	config := DefaultRuinConfig()
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil && err != io.EOF {
+		return config, fmt.Errorf("invalid request body: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// HandleRuin computes the risk of ruin and expected session length for a mode.
+// POST /api/crowdsim/{mode}/ruin
+func (h *Handlers) HandleRuin(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := h.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	config, err := decodeRuinConfig(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := CalcRuin(r.Context(), table, config)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, result)
+}
+
+// HandleRuinCompare computes the risk of ruin for multiple modes with the
+// same bankroll and ranks them from safest to riskiest.
+// POST /api/crowdsim/ruin/compare
+func (h *Handlers) HandleRuinCompare(w http.ResponseWriter, r *http.Request) {
+	var req RuinCompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if len(req.Modes) == 0 {
+		common.WriteError(w, http.StatusBadRequest, "at least one mode required")
+		return
+	}
+
+	if err := req.Config.Validate(); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results := make([]RuinResult, 0, len(req.Modes))
+	for _, mode := range req.Modes {
+		table, err := h.loader.GetMode(mode)
+		if err != nil {
+			continue // Skip invalid modes
+		}
+
+		result, err := CalcRuin(r.Context(), table, req.Config)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("mode %s: %v", mode, err))
+			return
+		}
+		results = append(results, *result)
+	}
+
+	if len(results) == 0 {
+		common.WriteError(w, http.StatusNotFound, "no valid modes found")
+		return
+	}
+
+	common.WriteSuccess(w, RuinCompareResult{
+		Results: results,
+		Ranking: RankRuinResults(results),
+	})
+}
+
 // HandleCompare runs CrowdSim for multiple modes and ranks them.
 // POST /api/crowdsim/compare
 func (h *Handlers) HandleCompare(w http.ResponseWriter, r *http.Request) {
@@ -135,7 +356,11 @@ func (h *Handlers) HandleCompare(w http.ResponseWriter, r *http.Request) {
 			continue // Skip invalid modes
 		}
 
-		simulator := NewCrowdSimulator(table, req.Config)
+		simulator, err := h.newSimulator(table, req.Config)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 
 		var result *SimResult
 		if req.Config.ParallelWorkers > 1 {
@@ -195,7 +420,11 @@ func (h *Handlers) HandleValidate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Run simulation
-	simulator := NewCrowdSimulator(table, config)
+	simulator, err := h.newSimulator(table, config)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	var result *SimResult
 	if config.ParallelWorkers > 1 {
 		result = simulator.RunParallel(nil)
@@ -243,7 +472,11 @@ func (h *Handlers) HandleVolatilityCheck(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Run simulation
-	simulator := NewCrowdSimulator(table, req.Config)
+	simulator, err := h.newSimulator(table, req.Config)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	var result *SimResult
 	if req.Config.ParallelWorkers > 1 {
 		result = simulator.RunParallel(nil)
@@ -0,0 +1,140 @@
+package crowdsim
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// validateModeMix checks a list of per-spin mode rates. owner prefixes errors.
+func validateModeMix(owner string, buys []BonusBuy) error {
+	var totalRate float64
+	seen := make(map[string]bool, len(buys))
+	for _, b := range buys {
+		if b.Mode == "" {
+			return fmt.Errorf("%s: mode required", owner)
+		}
+		if seen[b.Mode] {
+			return fmt.Errorf("%s: mode %s listed twice", owner, b.Mode)
+		}
+		seen[b.Mode] = true
+		if b.Rate < 0 || b.Rate > 1 {
+			return fmt.Errorf("%s: rate for %s must be between 0 and 1: %v", owner, b.Mode, b.Rate)
+		}
+		totalRate += b.Rate
+	}
+	if totalRate > 1 {
+		return fmt.Errorf("%s: rates add up to more than 1: %v", owner, totalRate)
+	}
+	return nil
+}
+
+// journeyBehavior plays a player's behaviour through the config's journey.
+// Spins the behaviour leaves in the simulated mode are replaced by a buy of
+// a journey mode at the journey's rate, when the balance covers its cost.
+type journeyBehavior struct {
+	Behavior
+	journey   []BonusBuy
+	modeCosts map[string]float64
+}
+
+func (b *journeyBehavior) Next(p *Player, spin int, rng *rand.Rand) (Spin, StopReason) {
+	next, stop := b.Behavior.Next(p, spin, rng)
+	if stop != "" || next.Mode != "" {
+		return next, stop
+	}
+
+	u := rng.Float64()
+	for _, mode := range b.journey {
+		if u < mode.Rate {
+			if cost, ok := b.modeCosts[mode.Mode]; ok && cost*p.BaseBet <= p.CurrentBalance {
+				return Spin{Stake: cost * p.BaseBet, Mode: mode.Mode}, ""
+			}
+			break
+		}
+		u -= mode.Rate
+	}
+	return next, ""
+}
+
+// ModePlay totals one player's play in a mode other than the simulated one.
+type ModePlay struct {
+	Spins   int
+	Wagered float64
+	Won     float64
+}
+
+// ModeStats holds the play in one mode of a run that mixes modes.
+type ModeStats struct {
+	Mode            string  `json:"mode"`
+	Cost            float64 `json:"cost"`        // Cost relative to the simulated mode
+	Players         int     `json:"players"`     // Players who played the mode at least once
+	Spins           int     `json:"spins"`       // Spins played, counting a bonus buy as one
+	SpinShare       float64 `json:"spin_share"`  // Percent of all spins
+	Wagered         float64 `json:"wagered"`     // Total staked
+	WagerShare      float64 `json:"wager_share"` // Percent of all stakes
+	Won             float64 `json:"won"`
+	TheoreticalRTP  float64 `json:"theoretical_rtp"`
+	ActualRTP       float64 `json:"actual_rtp"`
+	RTPContribution float64 `json:"rtp_contribution"` // Won over all stakes; the modes add up to the actual RTP
+}
+
+// calcModeStats calculates the play per mode, the simulated mode first, and
+// the RTP expected from the mix of stakes actually placed.
+func (s *CrowdSimulator) calcModeStats(players []*Player) ([]ModeStats, float64) {
+	stats := make([]ModeStats, 1+len(s.bonusOrder))
+	stats[0] = ModeStats{Mode: s.mode, Cost: 1, TheoreticalRTP: s.theoreticalRTP}
+	index := make(map[string]int, len(s.bonusOrder))
+	for i, mode := range s.bonusOrder {
+		stats[i+1] = ModeStats{Mode: mode, Cost: s.bonusCosts[mode], TheoreticalRTP: s.bonusModes[mode].rtp}
+		index[mode] = i + 1
+	}
+
+	var totalSpins int
+	var totalWagered float64
+	for _, p := range players {
+		totalSpins += p.TotalSpins
+		totalWagered += p.TotalWagered
+
+		home := ModePlay{Spins: p.TotalSpins, Wagered: p.TotalWagered, Won: p.TotalWon}
+		for mode, play := range p.Modes {
+			st := &stats[index[mode]]
+			st.Players++
+			st.Spins += play.Spins
+			st.Wagered += play.Wagered
+			st.Won += play.Won
+			home.Spins -= play.Spins
+			home.Wagered -= play.Wagered
+			home.Won -= play.Won
+		}
+		if home.Spins > 0 {
+			stats[0].Players++
+		}
+		stats[0].Spins += home.Spins
+		stats[0].Wagered += home.Wagered
+		stats[0].Won += home.Won
+	}
+
+	var expected float64
+	for i := range stats {
+		st := &stats[i]
+		expected += st.Wagered * st.TheoreticalRTP
+		if totalSpins > 0 {
+			st.SpinShare = round2(float64(st.Spins) / float64(totalSpins) * 100)
+		}
+		if st.Wagered > 0 {
+			st.ActualRTP = round4(st.Won / st.Wagered)
+		}
+		if totalWagered > 0 {
+			st.WagerShare = round2(st.Wagered / totalWagered * 100)
+			st.RTPContribution = round4(st.Won / totalWagered)
+		}
+		st.Cost = round4(st.Cost)
+		st.TheoreticalRTP = round4(st.TheoreticalRTP)
+		st.Wagered = round2(st.Wagered)
+		st.Won = round2(st.Won)
+	}
+	if totalWagered == 0 {
+		return stats, 0
+	}
+	return stats, round4(expected / totalWagered)
+}
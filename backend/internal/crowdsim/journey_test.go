@@ -0,0 +1,100 @@
+package crowdsim
+
+import (
+	"math"
+	"testing"
+
+	"stakergs"
+)
+
+func TestJourney_ModeMix(t *testing.T) {
+	// A bonus costing 100 base bets that returns 98% on average
+	bonus := &stakergs.LookupTable{Mode: "bonus", Cost: 100, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 1, Payout: 4800},
+		{SimID: 1, Weight: 1, Payout: 14800},
+	}}
+	config := DefaultConfig()
+	config.PlayerCount = 1000
+	config.SpinsPerSession = 300
+	config.InitialBalance = 500
+	config.ParallelWorkers = 1
+	config.Seed = 22
+	config.Journey = []BonusBuy{{Mode: "bonus", Rate: 0.05}}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	simulator := NewCrowdSimulator(standardTable(), nil, config)
+	simulator.AddBonusMode(bonus, nil)
+	result := simulator.Run(nil)
+
+	if len(result.ModeStats) != 2 {
+		t.Fatalf("mode stats %+v, want base and bonus", result.ModeStats)
+	}
+	base, buy := result.ModeStats[0], result.ModeStats[1]
+	if base.Mode != "base" || buy.Mode != "bonus" || buy.Cost != 100 || buy.TheoreticalRTP != 0.98 {
+		t.Fatalf("unexpected modes %+v, %+v", base, buy)
+	}
+	if base.Spins+buy.Spins != 300000 {
+		t.Errorf("%d + %d spins, want 300000", base.Spins, buy.Spins)
+	}
+	// Buys only happen while the balance covers them, so a little under 5%
+	if buy.SpinShare > 5.2 || buy.SpinShare < 3 {
+		t.Errorf("bonus spin share %v%%, want a little under 5%%", buy.SpinShare)
+	}
+	if math.Abs(base.WagerShare+buy.WagerShare-100) > 0.02 {
+		t.Errorf("wager shares %v + %v, want 100", base.WagerShare, buy.WagerShare)
+	}
+	if math.Abs(base.RTPContribution+buy.RTPContribution-result.ActualRTP) > 0.0002 {
+		t.Errorf("contributions %v + %v, actual RTP %v", base.RTPContribution, buy.RTPContribution, result.ActualRTP)
+	}
+
+	// The blended RTP weights each mode's RTP by its stakes
+	want := (base.Wagered*base.TheoreticalRTP + buy.Wagered*buy.TheoreticalRTP) / (base.Wagered + buy.Wagered)
+	if math.Abs(result.BlendedRTP-want) > 1e-4 || result.BlendedRTP >= result.TheoreticalRTP {
+		t.Errorf("blended RTP %v, want %v", result.BlendedRTP, want)
+	}
+	if math.Abs(result.ActualRTP-result.BlendedRTP) > 0.05 {
+		t.Errorf("actual RTP %v far from blended %v", result.ActualRTP, result.BlendedRTP)
+	}
+}
+
+func TestJourney_OnlyWhenBalanceAllows(t *testing.T) {
+	bonus := &stakergs.LookupTable{Mode: "bonus", Cost: 100, Outcomes: []stakergs.Outcome{{SimID: 0, Weight: 1, Payout: 0}}}
+	config := DefaultConfig()
+	config.PlayerCount = 50
+	config.ParallelWorkers = 1
+	config.Seed = 3
+	config.Journey = []BonusBuy{{Mode: "bonus", Rate: 1}}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	base := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{{SimID: 0, Weight: 1, Payout: 0}}}
+	simulator := NewCrowdSimulator(base, nil, config)
+	simulator.AddBonusMode(bonus, nil)
+	result := simulator.Run(nil)
+
+	// Every player buys once from 100, loses it, and can only afford base spins after
+	buy := result.ModeStats[1]
+	if buy.Players != 50 || buy.Spins != 50 || result.ModeStats[0].Spins != 50*199 {
+		t.Errorf("unexpected mode stats %+v", result.ModeStats)
+	}
+}
+
+func TestJourney_Validate(t *testing.T) {
+	config := DefaultConfig()
+	config.Journey = []BonusBuy{{Mode: "bonus", Rate: 0.1}, {Mode: "bonus", Rate: 0.1}}
+	if err := config.Validate(); err == nil {
+		t.Error("expected an error for a mode listed twice")
+	}
+
+	config.Journey = []BonusBuy{{Mode: "bonus", Rate: 0.1}}
+	config.Strategies = []StrategyConfig{{BonusBuys: []BonusBuy{{Mode: "super", Rate: 0.01}, {Mode: "bonus", Rate: 0.01}}}}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if modes := config.BonusModes(); len(modes) != 2 || modes[0] != "bonus" || modes[1] != "super" {
+		t.Errorf("BonusModes = %v", modes)
+	}
+}
@@ -23,10 +23,11 @@ func CalcPoP(players []*Player) float64 {
 	return round4(float64(profitable) / float64(len(players)))
 }
 
-// CalcPoPCurve calculates the PoP at each spin number.
+// CalcPoPCurve calculates the PoP at each spin number, against each player's
+// own initial balance.
 // Returns slice of length spinsPerSession (index 0 = after spin 1).
 // Spin 0 (before any play) is excluded as it's meaningless (everyone at initial balance).
-func CalcPoPCurve(players []*Player, spinsPerSession int, initialBalance float64) []float64 {
+func CalcPoPCurve(players []*Player, spinsPerSession int) []float64 {
 	if len(players) == 0 {
 		return nil
 	}
@@ -53,7 +54,7 @@ func CalcPoPCurve(players []*Player, spinsPerSession int, initialBalance float64
 		inProfit := 0
 		for _, p := range players {
 			if p.BalanceHistory != nil && spin < len(p.BalanceHistory) {
-				if p.BalanceHistory[spin] >= initialBalance {
+				if p.BalanceHistory[spin] >= p.InitialBalance {
 					inProfit++
 				}
 			}
@@ -2,24 +2,32 @@ package crowdsim
 
 // Player represents a single simulated player session.
 type Player struct {
-	ID              int       // Player identifier
-	InitialBalance  float64   // Starting balance
-	CurrentBalance  float64   // Current balance
-	BalanceHistory  []float64 // Balance after each spin (nil in streaming mode)
-	PeakBalance     float64   // Maximum balance reached
-	MinBalance      float64   // Minimum balance reached
-	MaxDrawdown     float64   // Maximum drawdown (initial - min) / initial
-	CurrentStreak   int       // Current streak: positive=winning, negative=losing
-	MaxWinStreak    int       // Longest winning streak
-	MaxLoseStreak   int       // Longest losing streak
-	TotalWins       int       // Count of winning spins (payout > bet)
-	TotalLosses     int       // Count of losing spins (payout <= bet)
-	BreakevenSpins  int       // Count of spins where payout >= 1.0 (breakeven or better)
-	FirstBigWinSpin int       // Spin number of first big win (-1 if never)
-	DangerEvents    int       // Count of spins where balance was below danger threshold
-	TotalWagered    float64   // Total amount bet
-	TotalWon        float64   // Total payouts received
-	TotalSpins      int       // Total number of spins played
+	ID              int        // Player identifier
+	InitialBalance  float64    // Starting balance
+	CurrentBalance  float64    // Current balance
+	BalanceHistory  []float64  // Balance after each spin (nil in streaming mode)
+	PeakBalance     float64    // Maximum balance reached
+	MinBalance      float64    // Minimum balance reached
+	MaxDrawdown     float64    // Maximum drawdown (initial - min) / initial
+	CurrentStreak   int        // Current streak: positive=winning, negative=losing
+	MaxWinStreak    int        // Longest winning streak
+	MaxLoseStreak   int        // Longest losing streak
+	TotalWins       int        // Count of winning spins (payout > bet)
+	TotalLosses     int        // Count of losing spins (payout <= bet)
+	BreakevenSpins  int        // Count of spins where payout >= 1.0 (breakeven or better)
+	FirstBigWinSpin int        // Spin number of first big win (-1 if never)
+	DangerEvents    int        // Count of spins where balance was below danger threshold
+	TotalWagered    float64    // Total amount bet
+	TotalWon        float64    // Total payouts received
+	TotalSpins      int        // Total number of spins played
+	MaxBet          float64    // Largest stake placed on one spin
+	BonusBuys       int        // Count of bonus buys
+	BaseBet         float64    // Base stake per spin
+	Segment         string     // Name of the population segment (empty without a population)
+	Strategy        string     // Name of the strategy played (empty without strategies)
+	StopReason      StopReason // Why the session ended
+
+	Modes map[string]*ModePlay // Play in bought modes (nil if none)
 }
 
 // NewPlayer creates a new player with initial balance.
@@ -31,6 +39,8 @@ func NewPlayer(id int, initialBalance float64, trackHistory bool, historySize in
 		PeakBalance:     initialBalance,
 		MinBalance:      initialBalance,
 		FirstBigWinSpin: -1,
+		StopReason:      StopSessionEnd,
+		BaseBet:         1,
 	}
 
 	if trackHistory && historySize > 0 {
@@ -51,6 +61,9 @@ func (p *Player) ProcessSpin(spinNum int, payout, betAmount, bigWinThreshold, da
 	// Deduct bet
 	p.CurrentBalance -= betAmount
 	p.TotalWagered += betAmount
+	if betAmount > p.MaxBet {
+		p.MaxBet = betAmount
+	}
 
 	// Add payout
 	winAmount := payout * betAmount
@@ -121,6 +134,17 @@ func (p *Player) ProcessSpin(spinNum int, payout, betAmount, bigWinThreshold, da
 	}
 }
 
+// End finishes the session early. The balance history is filled up to
+// spinsPerSession with the final balance, since a player who stopped keeps it.
+func (p *Player) End(reason StopReason, spinsPerSession int) {
+	p.StopReason = reason
+	if p.BalanceHistory != nil {
+		for len(p.BalanceHistory) <= spinsPerSession {
+			p.BalanceHistory = append(p.BalanceHistory, p.CurrentBalance)
+		}
+	}
+}
+
 // FinalProfit returns the net profit (final balance - initial balance).
 func (p *Player) FinalProfit() float64 {
 	return p.CurrentBalance - p.InitialBalance
@@ -155,31 +179,43 @@ func (p *Player) HitBigWin() bool {
 
 // PlayerSummary is a condensed view of player results.
 type PlayerSummary struct {
-	ID            int     `json:"id"`
-	FinalBalance  float64 `json:"final_balance"`
-	PeakBalance   float64 `json:"peak_balance"`
-	MinBalance    float64 `json:"min_balance"`
-	MaxDrawdown   float64 `json:"max_drawdown"`
-	MaxWinStreak  int     `json:"max_win_streak"`
-	MaxLoseStreak int     `json:"max_lose_streak"`
-	IsProfitable  bool    `json:"is_profitable"`
-	HitBigWin     bool    `json:"hit_big_win"`
-	ActualRTP     float64 `json:"actual_rtp"`
+	ID             int        `json:"id"`
+	FinalBalance   float64    `json:"final_balance"`
+	PeakBalance    float64    `json:"peak_balance"`
+	MinBalance     float64    `json:"min_balance"`
+	MaxDrawdown    float64    `json:"max_drawdown"`
+	MaxWinStreak   int        `json:"max_win_streak"`
+	MaxLoseStreak  int        `json:"max_lose_streak"`
+	IsProfitable   bool       `json:"is_profitable"`
+	HitBigWin      bool       `json:"hit_big_win"`
+	ActualRTP      float64    `json:"actual_rtp"`
+	Segment        string     `json:"segment,omitempty"`
+	InitialBalance float64    `json:"initial_balance"`
+	BetAmount      float64    `json:"bet_amount"`
+	Strategy       string     `json:"strategy,omitempty"`
+	StopReason     StopReason `json:"stop_reason"`
+	Spins          int        `json:"spins"`
 }
 
 // Summary returns a condensed summary of the player's session.
 func (p *Player) Summary() PlayerSummary {
 	return PlayerSummary{
-		ID:            p.ID,
-		FinalBalance:  round2(p.CurrentBalance),
-		PeakBalance:   round2(p.PeakBalance),
-		MinBalance:    round2(p.MinBalance),
-		MaxDrawdown:   round4(p.MaxDrawdown),
-		MaxWinStreak:  p.MaxWinStreak,
-		MaxLoseStreak: p.MaxLoseStreak,
-		IsProfitable:  p.IsProfitable(),
-		HitBigWin:     p.HitBigWin(),
-		ActualRTP:     round4(p.ActualRTP()),
+		ID:             p.ID,
+		FinalBalance:   round2(p.CurrentBalance),
+		PeakBalance:    round2(p.PeakBalance),
+		MinBalance:     round2(p.MinBalance),
+		MaxDrawdown:    round4(p.MaxDrawdown),
+		MaxWinStreak:   p.MaxWinStreak,
+		MaxLoseStreak:  p.MaxLoseStreak,
+		IsProfitable:   p.IsProfitable(),
+		HitBigWin:      p.HitBigWin(),
+		ActualRTP:      round4(p.ActualRTP()),
+		Segment:        p.Segment,
+		InitialBalance: round2(p.InitialBalance),
+		BetAmount:      round2(p.BaseBet),
+		Strategy:       p.Strategy,
+		StopReason:     p.StopReason,
+		Spins:          p.TotalSpins,
 	}
 }
 
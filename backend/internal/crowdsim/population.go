@@ -0,0 +1,238 @@
+package crowdsim
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Distribution types for population parameters.
+const (
+	DistributionFixed     = "fixed"
+	DistributionUniform   = "uniform"
+	DistributionLogNormal = "lognormal"
+	DistributionPareto    = "pareto"
+)
+
+// populationStream is the stream of the run seed that player parameters are
+// drawn from, apart from the players' spin streams.
+const populationStream = -1
+
+// goldenRatioConjugate spreads segments over player IDs independently of the
+// even spread used for strategies.
+const goldenRatioConjugate = 0.6180339887498949
+
+// Distribution describes how a player parameter varies. The zero value means
+// the parameter is not set.
+type Distribution struct {
+	Type   string  `json:"type"`             // fixed, uniform, lognormal or pareto
+	Value  float64 `json:"value,omitempty"`  // fixed value
+	Min    float64 `json:"min,omitempty"`    // Uniform lower bound and pareto scale; a floor for every type
+	Max    float64 `json:"max,omitempty"`    // Uniform upper bound; a cap for every type (0 = none)
+	Median float64 `json:"median,omitempty"` // Lognormal median
+	Sigma  float64 `json:"sigma,omitempty"`  // Lognormal shape (std dev of the log)
+	Alpha  float64 `json:"alpha,omitempty"`  // Pareto tail index; lower is longer-tailed
+}
+
+// Validate checks the distribution's parameters. name is used in errors.
+func (d Distribution) Validate(name string) error {
+	if d.Max < 0 || d.Min < 0 || (d.Max > 0 && d.Max < d.Min) {
+		return fmt.Errorf("%s: invalid bounds [%v, %v]", name, d.Min, d.Max)
+	}
+	switch d.Type {
+	case "":
+	case DistributionFixed:
+		if d.Value <= 0 {
+			return fmt.Errorf("%s: fixed value must be positive: %v", name, d.Value)
+		}
+	case DistributionUniform:
+		if d.Min <= 0 || d.Max <= 0 {
+			return fmt.Errorf("%s: uniform needs positive min and max", name)
+		}
+	case DistributionLogNormal:
+		if d.Median <= 0 || d.Sigma < 0 {
+			return fmt.Errorf("%s: lognormal needs a positive median and non-negative sigma", name)
+		}
+	case DistributionPareto:
+		if d.Min <= 0 || d.Alpha <= 0 {
+			return fmt.Errorf("%s: pareto needs positive min and alpha", name)
+		}
+	default:
+		return fmt.Errorf("%s: unknown distribution type %q", name, d.Type)
+	}
+	return nil
+}
+
+// Sample draws a value, or returns fallback when the distribution is not set.
+func (d Distribution) Sample(rng *rand.Rand, fallback float64) float64 {
+	var v float64
+	switch d.Type {
+	case DistributionFixed:
+		v = d.Value
+	case DistributionUniform:
+		v = d.Min + rng.Float64()*(d.Max-d.Min)
+	case DistributionLogNormal:
+		v = d.Median * math.Exp(d.Sigma*rng.NormFloat64())
+	case DistributionPareto:
+		v = d.Min * math.Pow(1-rng.Float64(), -1/d.Alpha)
+	default:
+		return fallback
+	}
+	v = math.Max(v, d.Min)
+	if d.Max > 0 {
+		v = math.Min(v, d.Max)
+	}
+	return v
+}
+
+// PopulationSegment is a group of players with their own bankrolls, stakes
+// and session lengths. Balances and bets share one currency; unset
+// parameters use the config's initial balance, a bet of 1 and the config's
+// spins per session.
+type PopulationSegment struct {
+	Name            string       `json:"name"`
+	Share           float64      `json:"share"` // Relative share of players (0 = same as 1)
+	InitialBalance  Distribution `json:"initial_balance"`
+	BetAmount       Distribution `json:"bet_amount"`
+	SpinsPerSession Distribution `json:"spins_per_session"` // Capped at the config's spins per session
+}
+
+// Validate checks the segment and applies defaults. index names unnamed segments.
+func (s *PopulationSegment) Validate(index int) error {
+	if s.Name == "" {
+		s.Name = fmt.Sprintf("segment_%d", index+1)
+	}
+	if s.Share < 0 {
+		return fmt.Errorf("segment %s: share cannot be negative: %v", s.Name, s.Share)
+	}
+	if s.Share == 0 {
+		s.Share = 1
+	}
+	if err := s.InitialBalance.Validate("segment " + s.Name + " initial_balance"); err != nil {
+		return err
+	}
+	if err := s.BetAmount.Validate("segment " + s.Name + " bet_amount"); err != nil {
+		return err
+	}
+	return s.SpinsPerSession.Validate("segment " + s.Name + " spins_per_session")
+}
+
+// playerParams are the drawn parameters of one player.
+type playerParams struct {
+	segment        string
+	initialBalance float64
+	betAmount      float64
+	spins          int
+}
+
+// draw draws one player's parameters. The bet is capped at the balance and
+// the session at maxSpins.
+func (s PopulationSegment) draw(rng *rand.Rand, initialBalance float64, maxSpins int) playerParams {
+	balance := s.InitialBalance.Sample(rng, initialBalance)
+	bet := math.Min(s.BetAmount.Sample(rng, 1), balance)
+	spins := int(math.Round(s.SpinsPerSession.Sample(rng, float64(maxSpins))))
+	if spins < 1 {
+		spins = 1
+	}
+	if spins > maxSpins {
+		spins = maxSpins
+	}
+	return playerParams{segment: s.Name, initialBalance: balance, betAmount: bet, spins: spins}
+}
+
+// segmentIndex picks a player's segment in proportion to the shares. It walks
+// player IDs with a golden-ratio sequence, which keeps the proportions close
+// while staying independent of the strategy assignment.
+func segmentIndex(segments []PopulationSegment, playerID int) int {
+	var total float64
+	for _, s := range segments {
+		total += s.Share
+	}
+	_, frac := math.Modf((float64(playerID) + 0.5) * goldenRatioConjugate)
+	position := frac * total
+	var cumulative float64
+	for i, s := range segments {
+		cumulative += s.Share
+		if position < cumulative {
+			return i
+		}
+	}
+	return len(segments) - 1
+}
+
+// SegmentStats holds metrics for the players of one population segment.
+type SegmentStats struct {
+	Name              string            `json:"name"`
+	Players           int               `json:"players"`
+	AvgInitialBalance float64           `json:"avg_initial_balance"`
+	AvgBetAmount      float64           `json:"avg_bet_amount"`
+	AvgSpins          float64           `json:"avg_spins"`
+	ActualRTP         float64           `json:"actual_rtp"`
+	FinalPoP          float64           `json:"final_pop"`
+	BalanceStats      BalanceStats      `json:"balance_stats"`
+	PeakStats         PeakStats         `json:"peak_stats"`
+	DrawdownStats     DrawdownStats     `json:"drawdown_stats"`
+	VolatilityProfile VolatilityProfile `json:"volatility_profile"`
+}
+
+// CalcSegmentStats calculates metrics per segment, in segment order. Balance
+// stats are in the segment's currency; the volatility class is judged against
+// the segment's average starting balance.
+func CalcSegmentStats(players []*Player, segments []PopulationSegment) []SegmentStats {
+	if len(segments) == 0 {
+		return nil
+	}
+
+	groups := make(map[string][]*Player, len(segments))
+	for _, p := range players {
+		groups[p.Segment] = append(groups[p.Segment], p)
+	}
+
+	stats := make([]SegmentStats, 0, len(segments))
+	for _, s := range segments {
+		group := groups[s.Name]
+		st := SegmentStats{Name: s.Name, Players: len(group)}
+		if len(group) == 0 {
+			stats = append(stats, st)
+			continue
+		}
+
+		var initial, bet, spins, wagered, won float64
+		for _, p := range group {
+			initial += p.InitialBalance
+			bet += p.BaseBet
+			spins += float64(p.TotalSpins)
+			wagered += p.TotalWagered
+			won += p.TotalWon
+		}
+		n := float64(len(group))
+		avgInitial := initial / n
+
+		st.AvgInitialBalance = round2(avgInitial)
+		st.AvgBetAmount = round2(bet / n)
+		st.AvgSpins = round2(spins / n)
+		if wagered > 0 {
+			st.ActualRTP = round4(won / wagered)
+		}
+		st.FinalPoP = CalcPoP(group)
+		st.BalanceStats = CalcBalanceStats(group)
+		st.BalanceStats.Distribution = nil // Buckets assume a starting balance of 100
+		st.PeakStats = CalcPeakStats(group)
+		st.DrawdownStats = CalcDrawdownStats(group)
+		st.VolatilityProfile = ClassifyVolatility(st.FinalPoP, st.BalanceStats, st.PeakStats, avgInitial)
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// averageInitialBalance returns the mean starting balance of the players.
+func averageInitialBalance(players []*Player) float64 {
+	if len(players) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, p := range players {
+		sum += p.InitialBalance
+	}
+	return sum / float64(len(players))
+}
@@ -0,0 +1,116 @@
+package crowdsim
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDistribution_Sample(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	lognormal := Distribution{Type: DistributionLogNormal, Median: 50, Sigma: 1, Max: 400}
+	pareto := Distribution{Type: DistributionPareto, Min: 10, Alpha: 1.5}
+
+	var below, capped int
+	for i := 0; i < 20000; i++ {
+		v := lognormal.Sample(rng, 0)
+		if v < 50 {
+			below++
+		}
+		if v > 400 {
+			t.Fatalf("lognormal draw %v above its cap", v)
+		}
+		if v == 400 {
+			capped++
+		}
+		if p := pareto.Sample(rng, 0); p < 10 {
+			t.Fatalf("pareto draw %v below its scale", p)
+		}
+	}
+	if math.Abs(float64(below)/20000-0.5) > 0.02 {
+		t.Errorf("%d of 20000 lognormal draws below the median", below)
+	}
+	// P(Z > ln 8) for a standard normal is about 2%
+	if capped < 200 || capped > 600 {
+		t.Errorf("%d of 20000 lognormal draws capped, want about 400", capped)
+	}
+
+	if v := (Distribution{}).Sample(rng, 42); v != 42 {
+		t.Errorf("unset distribution gave %v, want the fallback", v)
+	}
+	if err := (Distribution{Type: "zipf"}).Validate("x"); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
+
+func TestPopulation_SegmentsReportedSeparately(t *testing.T) {
+	config := DefaultConfig()
+	config.PlayerCount = 1000
+	config.SpinsPerSession = 200
+	config.ParallelWorkers = 1
+	config.Seed = 21
+	config.Population = []PopulationSegment{
+		{
+			Name:            "casual",
+			Share:           4,
+			InitialBalance:  Distribution{Type: DistributionUniform, Min: 20, Max: 50},
+			BetAmount:       Distribution{Type: DistributionFixed, Value: 0.5},
+			SpinsPerSession: Distribution{Type: DistributionUniform, Min: 50, Max: 150},
+		},
+		{
+			Name:           "whale",
+			InitialBalance: Distribution{Type: DistributionPareto, Min: 5000, Alpha: 2},
+			BetAmount:      Distribution{Type: DistributionFixed, Value: 50},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewCrowdSimulator(standardTable(), nil, config).Run(nil)
+	casual, whale := result.SegmentStats[0], result.SegmentStats[1]
+	if casual.Players+whale.Players != 1000 || math.Abs(float64(whale.Players)-200) > 5 {
+		t.Fatalf("segments have %d and %d players, want about 800 and 200", casual.Players, whale.Players)
+	}
+	if casual.AvgBetAmount != 0.5 || whale.AvgBetAmount != 50 || whale.AvgSpins != 200 {
+		t.Errorf("unexpected segment parameters: casual %+v, whale %+v", casual, whale)
+	}
+	if casual.AvgSpins < 90 || casual.AvgSpins > 110 {
+		t.Errorf("casual players averaged %v spins, want about 100", casual.AvgSpins)
+	}
+	if whale.AvgInitialBalance < 5000 || whale.BalanceStats.Mean < 1000 {
+		t.Errorf("whale balances look wrong: %+v", whale.BalanceStats)
+	}
+
+	// Every player is judged against their own bankroll
+	for _, s := range result.PlayerSummaries {
+		if s.IsProfitable != (s.FinalBalance >= s.InitialBalance) {
+			t.Fatalf("player %d profitable=%v with %v from %v", s.ID, s.IsProfitable, s.FinalBalance, s.InitialBalance)
+		}
+	}
+	if len(result.PoPCurve) != 200 || result.PoPCurve[199] != result.FinalPoP {
+		t.Errorf("PoP curve ends at %v, final PoP %v", result.PoPCurve[len(result.PoPCurve)-1], result.FinalPoP)
+	}
+
+	// The draws are seeded, and players keep their parameters with more workers
+	config.ParallelWorkers = 4
+	again := NewCrowdSimulator(standardTable(), nil, config).RunParallel(nil)
+	if again.SegmentStats[1].BalanceStats.Mean != whale.BalanceStats.Mean {
+		t.Errorf("whale mean %v on a parallel rerun, want %v", again.SegmentStats[1].BalanceStats.Mean, whale.BalanceStats.Mean)
+	}
+}
+
+func TestSegmentIndex_IndependentOfStrategies(t *testing.T) {
+	segments := []PopulationSegment{{Name: "a", Share: 1}, {Name: "b", Share: 1}}
+	strategies := []StrategyConfig{{Name: "x", Share: 1}, {Name: "y", Share: 1}}
+
+	counts := map[[2]int]int{}
+	for id := 0; id < 1000; id++ {
+		counts[[2]int{segmentIndex(segments, id), strategyIndex(strategies, id, 1000)}]++
+	}
+	for key, n := range counts {
+		if n < 200 || n > 300 {
+			t.Errorf("segment %d with strategy %d has %d players, want about 250", key[0], key[1], n)
+		}
+	}
+}
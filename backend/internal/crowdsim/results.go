@@ -41,6 +41,18 @@ type SimResult struct {
 	VolatilityProfile VolatilityProfile `json:"volatility_profile"`
 	CompositeScore    float64           `json:"composite_score"`
 
+	// Per-strategy breakdown (when the config has strategies)
+	StrategyStats []StrategyStats `json:"strategy_stats,omitempty"`
+
+	// Per-segment breakdown (when the config has a population)
+	SegmentStats []SegmentStats `json:"segment_stats,omitempty"`
+
+	// Per-mode breakdown (when players buy bonuses or follow a journey).
+	// BlendedRTP is the RTP expected from the stakes placed in each mode, to
+	// compare ActualRTP against; TheoreticalRTP is the simulated mode's alone.
+	ModeStats  []ModeStats `json:"mode_stats,omitempty"`
+	BlendedRTP float64     `json:"blended_rtp,omitempty"`
+
 	// Detailed Data (when not in streaming mode and player count <= 1000)
 	PlayerSummaries []PlayerSummary `json:"player_summaries,omitempty"`
 }
@@ -0,0 +1,319 @@
+package crowdsim
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"time"
+
+	"stakergs"
+)
+
+// State limits for the ruin calculator. The balance grid must reach MaxSpins
+// bets above zero, so long horizons use coarser balance steps.
+const (
+	DefaultRuinMaxStates = 1 << 13
+	MaxRuinStates        = 1 << 16
+	MaxRuinSpins         = 50000
+
+	ruinCurvePoints = 50
+)
+
+// ruinResolutions are the balance steps per bet the calculator tries, finest first.
+var ruinResolutions = []int{100, 50, 20, 10, 5, 2, 1}
+
+// RuinConfig holds risk-of-ruin parameters. A player is ruined once their
+// balance is below one bet, when they can no longer place a spin.
+type RuinConfig struct {
+	InitialBalance float64 `json:"initial_balance"` // Starting balance
+	BetAmount      float64 `json:"bet_amount"`      // Bet per spin
+	MaxSpins       int     `json:"max_spins"`       // Horizon in spins (1-50000)
+	MaxStates      int     `json:"max_states"`      // Balance grid size cap (0 = DefaultRuinMaxStates)
+}
+
+// DefaultRuinConfig returns a 100-bet bankroll over 1000 spins.
+func DefaultRuinConfig() RuinConfig {
+	return RuinConfig{
+		InitialBalance: 100.0,
+		BetAmount:      1.0,
+		MaxSpins:       1000,
+		MaxStates:      DefaultRuinMaxStates,
+	}
+}
+
+// Validate checks if the configuration is valid and applies defaults.
+func (c *RuinConfig) Validate() error {
+	if c.InitialBalance <= 0 {
+		c.InitialBalance = 100.0
+	}
+	if c.BetAmount <= 0 {
+		c.BetAmount = 1.0
+	}
+	if c.BetAmount > c.InitialBalance {
+		return fmt.Errorf("bet_amount (%v) cannot exceed initial_balance (%v)", c.BetAmount, c.InitialBalance)
+	}
+
+	if c.MaxSpins <= 0 {
+		c.MaxSpins = 1000
+	}
+	if c.MaxSpins > MaxRuinSpins {
+		return fmt.Errorf("max_spins exceeds maximum (%d): %d", MaxRuinSpins, c.MaxSpins)
+	}
+
+	if c.MaxStates <= 0 {
+		c.MaxStates = DefaultRuinMaxStates
+	}
+	if c.MaxStates > MaxRuinStates {
+		return fmt.Errorf("max_states exceeds maximum (%d): %d", MaxRuinStates, c.MaxStates)
+	}
+	return nil
+}
+
+// SurvivalPoint is the probability of still being able to bet after Spin spins.
+type SurvivalPoint struct {
+	Spin     int     `json:"spin"`
+	Survival float64 `json:"survival"`
+}
+
+// RuinResult holds risk-of-ruin results for one mode.
+type RuinResult struct {
+	Mode       string     `json:"mode"`
+	Config     RuinConfig `json:"config"`
+	DurationMs int64      `json:"duration_ms"`
+
+	// Balances are tracked in steps of 1/Resolution bets up to States steps.
+	// When every payout is a multiple of the step the chain is exact;
+	// otherwise payouts are split between neighbouring steps, keeping the mean.
+	Resolution int  `json:"resolution"`
+	States     int  `json:"states"`
+	Exact      bool `json:"exact"`
+
+	RuinProbability     float64 `json:"ruin_probability"`     // Ruined within MaxSpins
+	SurvivalProbability float64 `json:"survival_probability"` // Still betting after MaxSpins
+	// Expected spins played before ruin, counting at most MaxSpins. When
+	// Converged is set almost every player is ruined by then, so this is the
+	// expected session length until bust.
+	ExpectedSpins     float64         `json:"expected_spins"`
+	Converged         bool            `json:"converged"`
+	MedianSpinsToRuin int             `json:"median_spins_to_ruin"` // -1 if survival stays above 50%
+	SurvivalCurve     []SurvivalPoint `json:"survival_curve"`
+}
+
+// RuinCompareRequest is the request body for comparing ruin across modes.
+type RuinCompareRequest struct {
+	Modes  []string   `json:"modes"`
+	Config RuinConfig `json:"config"`
+}
+
+// RuinCompareResult holds ruin results ranked from safest to riskiest.
+type RuinCompareResult struct {
+	Results []RuinResult   `json:"results"`
+	Ranking []RankedResult `json:"ranking"` // Score is the ruin probability
+}
+
+// CalcRuin computes the probability of ruin within config.MaxSpins spins for
+// a player betting config.BetAmount from config.InitialBalance, as a Markov
+// chain over the discretized balance. config must be validated. Payouts are
+// normalized by the mode cost, as in CrowdSim.
+func CalcRuin(ctx context.Context, lut *stakergs.LookupTable, config RuinConfig) (*RuinResult, error) {
+	start := time.Now()
+	totalWeight := lut.TotalWeight()
+	if len(lut.Outcomes) == 0 || totalWeight == 0 {
+		return nil, fmt.Errorf("mode %s has no weighted outcomes", lut.Mode)
+	}
+	modeCost := lut.Cost
+	if modeCost <= 0 {
+		modeCost = 1.0
+	}
+
+	// Balances above N+1 bets cannot be ruined within N spins, so the grid
+	// stops there and anything beyond escapes as a survivor
+	startBets := config.InitialBalance / config.BetAmount
+	capBets := int(math.Ceil(math.Max(startBets, float64(config.MaxSpins)))) + 1
+	resolution := 0
+	for _, r := range ruinResolutions {
+		if capBets*r <= config.MaxStates {
+			resolution = r
+			break
+		}
+	}
+	if resolution == 0 {
+		return nil, fmt.Errorf("max_states (%d) is too small for %d spins from %.0f bets; need at least %d",
+			config.MaxStates, config.MaxSpins, startBets, capBets)
+	}
+	states := capBets * resolution
+
+	// Step distribution: the bet costs resolution steps and the payout
+	// returns bin steps. Payouts past the grid are dropped; those players
+	// escape the grid and survive the horizon.
+	kernel := make([]float64, states)
+	exact := true
+	add := func(bin int, p float64) {
+		if bin < states {
+			kernel[bin] += p
+		}
+	}
+	for _, o := range lut.Outcomes {
+		if o.Weight == 0 {
+			continue
+		}
+		p := float64(o.Weight) / float64(totalWeight)
+		x := float64(o.Payout) / 100.0 / modeCost * float64(resolution)
+		lo := math.Floor(x + 1e-9)
+		frac := x - lo
+		if frac < 1e-9 {
+			add(int(lo), p)
+			continue
+		}
+		exact = false
+		add(int(lo), p*(1-frac))
+		add(int(lo)+1, p*frac)
+	}
+
+	startState := int(math.Floor(startBets*float64(resolution) + 1e-9))
+	if math.Abs(startBets*float64(resolution)-float64(startState)) > 1e-9 {
+		exact = false
+	}
+
+	result := &RuinResult{
+		Mode:              lut.Mode,
+		Config:            config,
+		Resolution:        resolution,
+		States:            states,
+		Exact:             exact,
+		MedianSpinsToRuin: -1,
+	}
+
+	conv := newRuinConvolver(kernel)
+	balance := make([]float64, states)
+	balance[startState] = 1
+	var ruined float64
+
+	curveInterval := config.MaxSpins / ruinCurvePoints
+	if curveInterval < 1 {
+		curveInterval = 1
+	}
+	result.SurvivalCurve = append(result.SurvivalCurve, SurvivalPoint{Spin: 0, Survival: 1})
+
+	for n := 1; n <= config.MaxSpins; n++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Survival before this spin is the chance of playing it
+		result.ExpectedSpins += 1 - ruined
+
+		next := conv.step(balance[resolution:])
+		for s := range balance {
+			balance[s] = 0
+		}
+		for s, p := range next {
+			if s < resolution {
+				ruined += p
+			} else if s < states {
+				balance[s] = p
+			}
+		}
+
+		if result.MedianSpinsToRuin < 0 && ruined >= 0.5 {
+			result.MedianSpinsToRuin = n
+		}
+		if n%curveInterval == 0 || n == config.MaxSpins {
+			result.SurvivalCurve = append(result.SurvivalCurve, SurvivalPoint{Spin: n, Survival: round4(1 - ruined)})
+		}
+	}
+
+	result.RuinProbability = round4(clamp01(ruined))
+	result.SurvivalProbability = round4(clamp01(1 - ruined))
+	result.ExpectedSpins = round2(result.ExpectedSpins)
+	result.Converged = 1-ruined < 1e-4
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// RankRuinResults ranks results from lowest to highest ruin probability.
+func RankRuinResults(results []RuinResult) []RankedResult {
+	ranked := make([]RankedResult, len(results))
+	for i, r := range results {
+		ranked[i] = RankedResult{Mode: r.Mode, Score: r.RuinProbability}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score < ranked[j].Score })
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked
+}
+
+// ruinConvolver convolves balance vectors with a fixed step kernel, directly
+// for sparse kernels and by FFT otherwise.
+type ruinConvolver struct {
+	kernel   []float64
+	nonzero  []int
+	plan     *fftPlan
+	spectrum []complex128
+	buf      []complex128
+	out      []float64
+}
+
+func newRuinConvolver(kernel []float64) *ruinConvolver {
+	c := &ruinConvolver{kernel: kernel}
+	for j, p := range kernel {
+		if p != 0 {
+			c.nonzero = append(c.nonzero, j)
+		}
+	}
+
+	n := len(kernel)
+	size := 1 << bits.Len(uint(2*n-2))
+	c.out = make([]float64, 2*n)
+	// Direct convolution costs n per kernel entry; FFT a few passes of size log size
+	if len(c.nonzero) > 6*bits.Len(uint(size)) {
+		c.spectrum = make([]complex128, size)
+		for j, p := range kernel {
+			c.spectrum[j] = complex(p, 0)
+		}
+		c.plan = newFFTPlan(size)
+		c.plan.transform(c.spectrum, false)
+		c.buf = make([]complex128, size)
+	}
+	return c
+}
+
+// step returns the convolution of v with the kernel. The slice is reused by
+// the next call.
+func (c *ruinConvolver) step(v []float64) []float64 {
+	out := c.out[:len(v)+len(c.kernel)-1]
+	for i := range out {
+		out[i] = 0
+	}
+
+	if c.spectrum == nil {
+		for s, mass := range v {
+			if mass == 0 {
+				continue
+			}
+			for _, j := range c.nonzero {
+				out[s+j] += mass * c.kernel[j]
+			}
+		}
+		return out
+	}
+
+	for i := range c.buf {
+		c.buf[i] = 0
+	}
+	for i, mass := range v {
+		c.buf[i] = complex(mass, 0)
+	}
+	c.plan.transform(c.buf, false)
+	for i := range c.buf {
+		c.buf[i] *= c.spectrum[i]
+	}
+	c.plan.transform(c.buf, true)
+	for i := range out {
+		out[i] = math.Max(0, real(c.buf[i]))
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+package crowdsim
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/sampling"
+	"stakergs"
+)
+
+func TestCalcRuin_GamblersRuin(t *testing.T) {
+	// Win one bet with probability 0.4, lose it otherwise. From 3 bets a
+	// player with no ceiling busts surely, after 3 / (0.6 - 0.4) = 15 spins
+	// on average.
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 3, Payout: 0},
+		{SimID: 1, Weight: 2, Payout: 200},
+	}}
+	config := RuinConfig{InitialBalance: 3, BetAmount: 1, MaxSpins: 2000}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := CalcRuin(context.Background(), table, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Exact || !result.Converged || result.RuinProbability != 1 {
+		t.Errorf("unexpected result: exact=%v converged=%v ruin=%v", result.Exact, result.Converged, result.RuinProbability)
+	}
+	if math.Abs(result.ExpectedSpins-15) > 0.01 {
+		t.Errorf("expected spins %v, want 15", result.ExpectedSpins)
+	}
+	// Ruin within three spins needs three straight losses
+	for _, p := range result.SurvivalCurve {
+		if p.Spin == 3 && math.Abs(p.Survival-(1-0.216)) > 1e-4 {
+			t.Errorf("survival after 3 spins %v, want %v", p.Survival, 1-0.216)
+		}
+	}
+}
+
+func TestCalcRuin_AgreesWithMonteCarlo(t *testing.T) {
+	table := standardTable()
+	config := RuinConfig{InitialBalance: 20, BetAmount: 1, MaxSpins: 200}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	result, err := CalcRuin(context.Background(), table, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const players = 20000
+	sampler := sampling.New(table)
+	ruined := 0
+	for i := 0; i < players; i++ {
+		rng := common.NewRand(5, int64(i))
+		balance := config.InitialBalance
+		for spin := 0; spin < config.MaxSpins && balance >= config.BetAmount; spin++ {
+			balance += float64(sampler.Sample(rng).Payout)/100 - config.BetAmount
+		}
+		if balance < config.BetAmount {
+			ruined++
+		}
+	}
+
+	simulated := float64(ruined) / players
+	tolerance := 4 * math.Sqrt(simulated*(1-simulated)/players)
+	if math.Abs(simulated-result.RuinProbability) > tolerance {
+		t.Errorf("ruin probability %v, simulated %v ± %v", result.RuinProbability, simulated, tolerance)
+	}
+}
+
+func TestRuinConfig_Validate(t *testing.T) {
+	config := RuinConfig{}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if config != DefaultRuinConfig() {
+		t.Errorf("empty config validated to %+v, want the defaults", config)
+	}
+
+	config = RuinConfig{InitialBalance: 1, BetAmount: 2}
+	if err := config.Validate(); err == nil {
+		t.Error("expected an error for a bet above the balance")
+	}
+
+	config = RuinConfig{MaxSpins: 20000, MaxStates: 1000}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CalcRuin(context.Background(), standardTable(), config); err == nil {
+		t.Error("expected an error when the horizon outgrows max_states")
+	}
+}
+
+func TestRankRuinResults(t *testing.T) {
+	ranked := RankRuinResults([]RuinResult{
+		{Mode: "risky", RuinProbability: 0.6},
+		{Mode: "safe", RuinProbability: 0.1},
+	})
+	if ranked[0].Mode != "safe" || ranked[0].Rank != 1 || ranked[1].Mode != "risky" {
+		t.Errorf("unexpected ranking %+v", ranked)
+	}
+}
+
+func TestRuinConvolver_FFTMatchesDirect(t *testing.T) {
+	kernel := make([]float64, 300)
+	for j := range kernel {
+		kernel[j] = float64(j%7+1) / 1000
+	}
+	v := make([]float64, 280)
+	for i := range v {
+		v[i] = float64(i%5) / 100
+	}
+
+	fast := newRuinConvolver(kernel)
+	if fast.spectrum == nil {
+		t.Fatal("expected a dense kernel to use the FFT")
+	}
+	direct := &ruinConvolver{kernel: kernel, nonzero: fast.nonzero, out: make([]float64, 2*len(kernel))}
+
+	want := append([]float64(nil), direct.step(v)...)
+	got := fast.step(v)
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Fatalf("bin %d: FFT %v, direct %v", i, got[i], want[i])
+		}
+	}
+}
+
+func BenchmarkCalcRuin(b *testing.B) {
+	// Many distinct payouts, as in a real table, take the FFT path
+	outcomes := make([]stakergs.Outcome, 400)
+	for i := range outcomes {
+		outcomes[i] = stakergs.Outcome{SimID: i, Weight: uint64(1000 / (i + 1)), Payout: uint(i * 37)}
+	}
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: outcomes}
+	config := DefaultRuinConfig()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := CalcRuin(context.Background(), table, config); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,74 +1,44 @@
 package crowdsim
 
 import (
-	"crypto/rand"
+	"context"
 	"fmt"
-	"math/big"
-	mrand "math/rand"
-	"sort"
+	"math/rand"
 	"sync"
 	"time"
 
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/lut"
+	"lutexplorer/internal/sampling"
 	"stakergs"
 )
 
-// WeightedSampler provides efficient weighted random sampling.
-type WeightedSampler struct {
-	outcomes    []stakergs.Outcome
-	cumWeights  []uint64
-	totalWeight uint64
-}
-
-// NewWeightedSampler creates a sampler from a lookup table.
-func NewWeightedSampler(lut *stakergs.LookupTable) *WeightedSampler {
-	ws := &WeightedSampler{
-		outcomes:   lut.Outcomes,
-		cumWeights: make([]uint64, len(lut.Outcomes)),
-	}
-
-	var cumulative uint64
-	for i, o := range lut.Outcomes {
-		cumulative += o.Weight
-		ws.cumWeights[i] = cumulative
-	}
-	ws.totalWeight = cumulative
-
-	return ws
-}
-
-// Sample returns a random outcome based on weights using math/rand.
-func (ws *WeightedSampler) Sample(rng *mrand.Rand) stakergs.Outcome {
-	r := rng.Uint64() % ws.totalWeight
-	idx := sort.Search(len(ws.cumWeights), func(i int) bool {
-		return ws.cumWeights[i] > r
-	})
-	return ws.outcomes[idx]
-}
-
-// SampleCrypto returns a random outcome using crypto/rand.
-func (ws *WeightedSampler) SampleCrypto() stakergs.Outcome {
-	n, _ := rand.Int(rand.Reader, big.NewInt(int64(ws.totalWeight)))
-	r := uint64(n.Int64())
-
-	idx := sort.Search(len(ws.cumWeights), func(i int) bool {
-		return ws.cumWeights[i] > r
-	})
-	return ws.outcomes[idx]
-}
-
 // CrowdSimulator handles multi-player simulation.
 type CrowdSimulator struct {
-	sampler        *WeightedSampler
+	sampler        *sampling.Sampler
 	config         SimConfig
 	theoreticalRTP float64
 	mode           string
 	modeCost       float64 // Cost from LUT (bet amount)
 	breakevenRate  float64 // P(payout >= cost)
 	maxPayout      float64 // Maximum payout (normalized by cost)
+	bonusModes     map[string]bonusMode
+	bonusCosts     map[string]float64 // Bonus mode cost relative to modeCost
+	bonusOrder     []string           // Bonus modes in the order they were added
+	newBehavior    func(playerID int) (Behavior, string)
+	tieredBigWin   bool // BigWinThreshold follows the win tiers
+}
+
+// bonusMode is a mode players can buy into from the simulated mode.
+type bonusMode struct {
+	sampler *sampling.Sampler
+	cost    float64
+	rtp     float64
 }
 
 // NewCrowdSimulator creates a new simulator for the given lookup table.
-func NewCrowdSimulator(lut *stakergs.LookupTable, config SimConfig) *CrowdSimulator {
+// sampler is normally the loader's cached sampler for the table; nil builds one.
+func NewCrowdSimulator(lut *stakergs.LookupTable, sampler *sampling.Sampler, config SimConfig) *CrowdSimulator {
 	modeCost := lut.Cost
 	if modeCost <= 0 {
 		modeCost = 1.0
@@ -99,6 +69,10 @@ func NewCrowdSimulator(lut *stakergs.LookupTable, config SimConfig) *CrowdSimula
 	// Max payout normalized by cost
 	maxPayout := float64(maxPayoutCents) / 100.0 / modeCost
 
+	if config.Seed == 0 && !config.UseCryptoRNG {
+		config.Seed = common.NewSeed()
+	}
+
 	// Normalize everything to unit bets:
 	// - BetAmount = 1 (each spin costs 1 unit)
 	// - Payout will be normalized: (outcome.Payout/100) / cost
@@ -106,17 +80,162 @@ func NewCrowdSimulator(lut *stakergs.LookupTable, config SimConfig) *CrowdSimula
 	// This way RTP = avg(payout) / 1 = avg((outcome.Payout/100)/cost) = theoreticalRTP
 	config.BetAmount = 1.0
 
+	// An unset big-win threshold is the big-win tier, which is in base bets;
+	// the default tiers until SetWinTiers gives the game's own
+	tieredBigWin := config.BigWinThreshold <= 0
+	if tieredBigWin {
+		config.BigWinThreshold = common.BigWinMultiplier / modeCost
+	}
+
+	if sampler == nil {
+		sampler = sampling.New(lut)
+	}
+
 	return &CrowdSimulator{
-		sampler:        NewWeightedSampler(lut),
+		sampler:        sampler,
 		config:         config,
 		theoreticalRTP: theoreticalRTP,
 		mode:           lut.Mode,
 		modeCost:       modeCost,
 		breakevenRate:  breakevenRate,
 		maxPayout:      maxPayout,
+		bonusModes:     make(map[string]bonusMode),
+		bonusCosts:     make(map[string]float64),
+		tieredBigWin:   tieredBigWin,
 	}
 }
 
+// SetWinTiers sets the game's win tiers. Unless the config sets its own
+// threshold, big wins are those reaching the first tier.
+func (s *CrowdSimulator) SetWinTiers(tiers *lut.WinTiers) {
+	if s.tieredBigWin {
+		s.config.BigWinThreshold = tiers.BigWin() / s.modeCost
+	}
+}
+
+// AddBonusMode makes a mode available to strategies that buy bonuses and to
+// the journey. sampler is normally the loader's cached sampler for the table;
+// nil builds one.
+func (s *CrowdSimulator) AddBonusMode(lut *stakergs.LookupTable, sampler *sampling.Sampler) {
+	cost := lut.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+	if sampler == nil {
+		sampler = sampling.New(lut)
+	}
+	if _, ok := s.bonusModes[lut.Mode]; !ok {
+		s.bonusOrder = append(s.bonusOrder, lut.Mode)
+	}
+	s.bonusModes[lut.Mode] = bonusMode{sampler: sampler, cost: cost, rtp: lut.RTP()}
+	s.bonusCosts[lut.Mode] = cost / s.modeCost
+}
+
+// SetBehavior replaces the config's strategies with custom player behaviour.
+// newBehavior returns a fresh Behavior for each player and the strategy name
+// reported in player summaries. It is called concurrently by parallel runs.
+func (s *CrowdSimulator) SetBehavior(newBehavior func(playerID int) (Behavior, string)) {
+	s.newBehavior = newBehavior
+}
+
+// behavior returns the behaviour of a player, following the journey if the
+// config has one, and the name of its strategy. Without strategies every
+// player bets the base bet for the whole session.
+func (s *CrowdSimulator) behavior(playerID int) (Behavior, string) {
+	behavior, name := s.strategyBehavior(playerID)
+	if len(s.config.Journey) > 0 {
+		behavior = &journeyBehavior{Behavior: behavior, journey: s.config.Journey, modeCosts: s.bonusCosts}
+	}
+	return behavior, name
+}
+
+func (s *CrowdSimulator) strategyBehavior(playerID int) (Behavior, string) {
+	if s.newBehavior != nil {
+		return s.newBehavior(playerID)
+	}
+	if len(s.config.Strategies) == 0 {
+		return StrategyConfig{Progression: ProgressionFlat}.NewBehavior(nil), ""
+	}
+	strategy := s.config.Strategies[strategyIndex(s.config.Strategies, playerID, s.config.PlayerCount)]
+	return strategy.NewBehavior(s.bonusCosts), strategy.Name
+}
+
+// playSession plays one player's session.
+func (s *CrowdSimulator) playSession(playerID int, trackHistory bool) *Player {
+	params := s.playerParams(playerID)
+	player := NewPlayer(playerID, params.initialBalance, trackHistory, s.config.SpinsPerSession)
+	player.BaseBet = params.betAmount
+	player.Segment = params.segment
+	// Seeding per player rather than per worker keeps results
+	// independent of how players are scheduled
+	rng := s.playerRand(playerID)
+	behavior, strategy := s.behavior(playerID)
+	player.Strategy = strategy
+
+	for spin := 0; spin < params.spins; spin++ {
+		next, stop := behavior.Next(player, spin, rng)
+		if stop != "" {
+			player.End(stop, s.config.SpinsPerSession)
+			break
+		}
+
+		sampler, cost := s.sampler, s.modeCost
+		if next.Mode != "" {
+			bonus := s.bonusModes[next.Mode]
+			sampler, cost = bonus.sampler, bonus.cost
+			player.BonusBuys++
+		}
+		outcome := sampler.Sample(rng)
+		// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
+		// Normalize by cost to get multiplier relative to the stake
+		// Example: bonus cost=350, payout=34055 -> 340.55 / 350 = 0.973x
+		payout := float64(outcome.Payout) / 100.0 / cost
+
+		player.ProcessSpin(spin, payout, next.Stake, s.config.BigWinThreshold, s.config.DangerThreshold)
+		if next.Mode != "" {
+			if player.Modes == nil {
+				player.Modes = make(map[string]*ModePlay)
+			}
+			play := player.Modes[next.Mode]
+			if play == nil {
+				play = &ModePlay{}
+				player.Modes[next.Mode] = play
+			}
+			play.Spins++
+			play.Wagered += next.Stake
+			play.Won += payout * next.Stake
+		}
+		behavior.Result(next, payout*next.Stake)
+	}
+	if player.StopReason == StopSessionEnd && params.spins < s.config.SpinsPerSession {
+		player.End(StopSessionEnd, s.config.SpinsPerSession)
+	}
+	return player
+}
+
+// playerParams returns a player's bankroll, stake and session length. Without
+// a population every player starts with InitialBalance bets and plays every
+// spin. Population draws use their own stream, so they do not shift the spins.
+func (s *CrowdSimulator) playerParams(playerID int) playerParams {
+	params := playerParams{
+		initialBalance: s.config.InitialBalance,
+		betAmount:      s.config.BetAmount,
+		spins:          s.config.SpinsPerSession,
+	}
+	if len(s.config.Population) == 0 {
+		return params
+	}
+
+	var rng *rand.Rand
+	if s.config.UseCryptoRNG {
+		rng = sampling.NewCryptoRand()
+	} else {
+		rng = common.NewRand(common.DeriveSeed(s.config.Seed, populationStream), int64(playerID))
+	}
+	segment := s.config.Population[segmentIndex(s.config.Population, playerID)]
+	return segment.draw(rng, s.config.InitialBalance, s.config.SpinsPerSession)
+}
+
 // Progress reports simulation progress.
 type Progress struct {
 	PlayersComplete int   `json:"players_complete"`
@@ -125,39 +244,48 @@ type Progress struct {
 	ElapsedMs       int64 `json:"elapsed_ms"`
 }
 
+// playerRand returns the generator for one player's session. Each player has
+// its own stream of the seed, so Run and RunParallel give the same results.
+func (s *CrowdSimulator) playerRand(playerID int) *rand.Rand {
+	if s.config.UseCryptoRNG {
+		return sampling.NewCryptoRand()
+	}
+	return common.NewRand(s.config.Seed, int64(playerID))
+}
+
 // Run executes the full simulation sequentially.
 func (s *CrowdSimulator) Run(progressCallback func(Progress)) *SimResult {
+	result, _ := s.runSequential(context.Background(), progressCallback)
+	return result
+}
+
+// RunParallel executes simulation with parallel workers.
+func (s *CrowdSimulator) RunParallel(progressCallback func(Progress)) *SimResult {
+	result, _ := s.runParallel(context.Background(), progressCallback)
+	return result
+}
+
+// RunContext executes the simulation, in parallel when more than one worker
+// is configured. It stops between players once ctx is cancelled and returns
+// ctx.Err().
+func (s *CrowdSimulator) RunContext(ctx context.Context, progressCallback func(Progress)) (*SimResult, error) {
+	if s.config.ParallelWorkers > 1 {
+		return s.runParallel(ctx, progressCallback)
+	}
+	return s.runSequential(ctx, progressCallback)
+}
+
+func (s *CrowdSimulator) runSequential(ctx context.Context, progressCallback func(Progress)) (*SimResult, error) {
 	start := time.Now()
 
 	trackHistory := !s.config.StreamingMode
 	players := make([]*Player, s.config.PlayerCount)
 
-	// Create a single RNG for sequential mode
-	rng := mrand.New(mrand.NewSource(time.Now().UnixNano()))
-
 	for i := 0; i < s.config.PlayerCount; i++ {
-		player := NewPlayer(i, s.config.InitialBalance, trackHistory, s.config.SpinsPerSession)
-
-		// Run session
-		for spin := 0; spin < s.config.SpinsPerSession; spin++ {
-			var payout float64
-			if s.config.UseCryptoRNG {
-				outcome := s.sampler.SampleCrypto()
-				// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
-				// Normalize by cost to get multiplier relative to mode cost
-				// Example: bonus cost=350, payout=34055 -> 340.55 / 350 = 0.973x
-				payout = float64(outcome.Payout) / 100.0 / s.modeCost
-			} else {
-				outcome := s.sampler.Sample(rng)
-				// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
-				// Normalize by cost to get multiplier relative to mode cost
-				payout = float64(outcome.Payout) / 100.0 / s.modeCost
-			}
-
-			player.ProcessSpin(spin, payout, s.config.BetAmount, s.config.BigWinThreshold, s.config.DangerThreshold)
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-
-		players[i] = player
+		players[i] = s.playSession(i, trackHistory)
 
 		// Report progress every 100 players
 		if progressCallback != nil && (i+1)%100 == 0 {
@@ -170,11 +298,10 @@ func (s *CrowdSimulator) Run(progressCallback func(Progress)) *SimResult {
 		}
 	}
 
-	return s.calculateResults(players, time.Since(start))
+	return s.calculateResults(players, time.Since(start)), nil
 }
 
-// RunParallel executes simulation with parallel workers.
-func (s *CrowdSimulator) RunParallel(progressCallback func(Progress)) *SimResult {
+func (s *CrowdSimulator) runParallel(ctx context.Context, progressCallback func(Progress)) (*SimResult, error) {
 	start := time.Now()
 
 	trackHistory := !s.config.StreamingMode
@@ -194,31 +321,11 @@ func (s *CrowdSimulator) RunParallel(progressCallback func(Progress)) *SimResult
 		go func() {
 			defer wg.Done()
 
-			// Each worker has its own RNG
-			rng := mrand.New(mrand.NewSource(time.Now().UnixNano() + int64(mrand.Intn(1000000))))
-
 			for playerID := range playerChan {
-				player := NewPlayer(playerID, s.config.InitialBalance, trackHistory, s.config.SpinsPerSession)
-
-				// Run session
-				for spin := 0; spin < s.config.SpinsPerSession; spin++ {
-					var payout float64
-					if s.config.UseCryptoRNG {
-						outcome := s.sampler.SampleCrypto()
-						// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
-						// Normalize by cost to get multiplier relative to mode cost
-						payout = float64(outcome.Payout) / 100.0 / s.modeCost
-					} else {
-						outcome := s.sampler.Sample(rng)
-						// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
-						// Normalize by cost to get multiplier relative to mode cost
-						payout = float64(outcome.Payout) / 100.0 / s.modeCost
-					}
-
-					player.ProcessSpin(spin, payout, s.config.BetAmount, s.config.BigWinThreshold, s.config.DangerThreshold)
+				if ctx.Err() != nil {
+					return
 				}
-
-				players[playerID] = player
+				players[playerID] = s.playSession(playerID, trackHistory)
 
 				// Update progress
 				if progressCallback != nil {
@@ -246,8 +353,11 @@ func (s *CrowdSimulator) RunParallel(progressCallback func(Progress)) *SimResult
 
 	// Wait for completion
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	return s.calculateResults(players, time.Since(start))
+	return s.calculateResults(players, time.Since(start)), nil
 }
 
 // calculateResults computes all metrics from player data.
@@ -293,7 +403,7 @@ func (s *CrowdSimulator) calculateResults(players []*Player, duration time.Durat
 
 	// Calculate all metrics
 	result.FinalPoP = CalcPoP(players)
-	result.PoPCurve = CalcPoPCurve(players, s.config.SpinsPerSession, s.config.InitialBalance)
+	result.PoPCurve = CalcPoPCurve(players, s.config.SpinsPerSession)
 	result.BalanceCurve = CalcBalanceCurve(players, s.config.SpinsPerSession)
 	result.BalanceStats = CalcBalanceStats(players)
 	result.PeakStats = CalcPeakStats(players)
@@ -301,8 +411,18 @@ func (s *CrowdSimulator) calculateResults(players []*Player, duration time.Durat
 	result.DangerStats = CalcDangerStats(players)
 	result.StreakStats = CalcStreakStats(players)
 	result.BigWinStats = CalcBigWinStats(players)
-	result.VolatilityProfile = ClassifyVolatility(result.FinalPoP, result.BalanceStats, result.PeakStats, s.config.InitialBalance)
-	result.CompositeScore = CalcCompositeScore(result, DefaultRankingWeights(), s.config.InitialBalance)
+	// With a population, balances are judged against the average bankroll
+	initialBalance := s.config.InitialBalance
+	if len(s.config.Population) > 0 {
+		initialBalance = averageInitialBalance(players)
+	}
+	result.VolatilityProfile = ClassifyVolatility(result.FinalPoP, result.BalanceStats, result.PeakStats, initialBalance)
+	result.CompositeScore = CalcCompositeScore(result, DefaultRankingWeights(), initialBalance)
+	result.StrategyStats = CalcStrategyStats(players, s.config.Strategies)
+	result.SegmentStats = CalcSegmentStats(players, s.config.Population)
+	if len(s.bonusOrder) > 0 {
+		result.ModeStats, result.BlendedRTP = s.calcModeStats(players)
+	}
 
 	// Player summaries (limit to avoid huge responses)
 	if !s.config.StreamingMode && len(players) <= 1000 {
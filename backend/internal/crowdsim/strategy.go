@@ -0,0 +1,278 @@
+package crowdsim
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// StopReason says why a player's session ended.
+type StopReason string
+
+const (
+	StopSessionEnd StopReason = "session_end" // Played every spin of the session
+	StopWinTarget  StopReason = "stop_win"    // Profit reached the win target
+	StopLossLimit  StopReason = "stop_loss"   // Losses reached the loss limit
+	StopBust       StopReason = "bust"        // Balance could not cover the base bet
+	StopTimeLimit  StopReason = "time_limit"  // Session time ran out
+)
+
+// Bet progressions for StrategyConfig.Progression.
+const (
+	ProgressionFlat       = "flat"       // Always bet the base bet
+	ProgressionMartingale = "martingale" // Multiply the bet after a loss, reset after a win
+	ProgressionParoli     = "paroli"     // Multiply the bet after a win, reset after a loss
+)
+
+// Strategy defaults.
+const (
+	DefaultProgressionFactor = 2.0
+	DefaultMaxBetMultiple    = 16.0
+	DefaultSpinsPerMinute    = 12.0
+)
+
+// Spin is one spin a player decides to play.
+type Spin struct {
+	Stake float64 // Amount paid for the spin
+	Mode  string  // Bonus mode bought; empty for a spin of the simulated mode
+}
+
+// Behavior decides how one player bets and when their session ends. A new
+// Behavior is made for every player, so it can keep per-session state.
+type Behavior interface {
+	// Next returns the next spin to play, or a stop reason to end the
+	// session before it. spin is the 0-indexed spin number.
+	Next(p *Player, spin int, rng *rand.Rand) (Spin, StopReason)
+	// Result reports the amount won on the spin Next returned.
+	Result(spin Spin, won float64)
+}
+
+// BonusBuy is how often a strategy buys a bonus mode instead of spinning.
+type BonusBuy struct {
+	Mode string  `json:"mode"`
+	Rate float64 `json:"rate"` // Chance each spin is a buy of this mode (0-1)
+}
+
+// StrategyConfig configures a player behaviour. Amounts are in base bets, so
+// a strategy means the same to players of any stake.
+type StrategyConfig struct {
+	Name  string  `json:"name"`
+	Share float64 `json:"share"` // Relative share of players (0 = same as 1)
+
+	StopWin    float64 `json:"stop_win"`     // Stop once profit reaches this (0 = never)
+	StopLoss   float64 `json:"stop_loss"`    // Stop once losses reach this (0 = never)
+	StopOnBust bool    `json:"stop_on_bust"` // Stop when the balance cannot cover the base bet
+
+	Progression       string  `json:"progression"`        // flat, martingale or paroli
+	ProgressionFactor float64 `json:"progression_factor"` // Bet multiplier per step (default 2)
+	MaxBetMultiple    float64 `json:"max_bet_multiple"`   // Bet cap as a multiple of the base bet (default 16)
+
+	BonusBuys []BonusBuy `json:"bonus_buys,omitempty"`
+
+	SessionMinutes float64 `json:"session_minutes"`  // Session time limit (0 = none)
+	SpinsPerMinute float64 `json:"spins_per_minute"` // Pace for the time limit (default 12)
+}
+
+// Validate checks the strategy and applies defaults. index names unnamed strategies.
+func (c *StrategyConfig) Validate(index int) error {
+	if c.Name == "" {
+		c.Name = fmt.Sprintf("strategy_%d", index+1)
+	}
+	if c.Share < 0 {
+		return fmt.Errorf("strategy %s: share cannot be negative: %v", c.Name, c.Share)
+	}
+	if c.Share == 0 {
+		c.Share = 1
+	}
+	if c.StopWin < 0 || c.StopLoss < 0 {
+		return fmt.Errorf("strategy %s: stop_win and stop_loss cannot be negative", c.Name)
+	}
+
+	switch c.Progression {
+	case "":
+		c.Progression = ProgressionFlat
+	case ProgressionFlat, ProgressionMartingale, ProgressionParoli:
+	default:
+		return fmt.Errorf("strategy %s: unknown progression %q", c.Name, c.Progression)
+	}
+	if c.ProgressionFactor <= 0 {
+		c.ProgressionFactor = DefaultProgressionFactor
+	}
+	if c.MaxBetMultiple < 1 {
+		c.MaxBetMultiple = DefaultMaxBetMultiple
+	}
+
+	if err := validateModeMix("strategy "+c.Name, c.BonusBuys); err != nil {
+		return err
+	}
+
+	if c.SessionMinutes < 0 {
+		return fmt.Errorf("strategy %s: session_minutes cannot be negative: %v", c.Name, c.SessionMinutes)
+	}
+	if c.SpinsPerMinute <= 0 {
+		c.SpinsPerMinute = DefaultSpinsPerMinute
+	}
+	return nil
+}
+
+// NewBehavior returns a fresh behaviour following this strategy. modeCosts
+// holds the cost of each bonus mode relative to the simulated mode.
+func (c StrategyConfig) NewBehavior(modeCosts map[string]float64) Behavior {
+	b := &configBehavior{config: c, multiple: 1, spinLimit: -1, modeCosts: modeCosts}
+	if c.SessionMinutes > 0 {
+		b.spinLimit = int(c.SessionMinutes * c.SpinsPerMinute)
+	}
+	return b
+}
+
+// configBehavior is the Behavior described by a StrategyConfig.
+type configBehavior struct {
+	config    StrategyConfig
+	multiple  float64 // Current bet as a multiple of the base bet
+	spinLimit int     // Spins the time limit allows; -1 for none
+	modeCosts map[string]float64
+}
+
+func (b *configBehavior) Next(p *Player, spin int, rng *rand.Rand) (Spin, StopReason) {
+	c := b.config
+	profit := (p.CurrentBalance - p.InitialBalance) / p.BaseBet
+	switch {
+	case b.spinLimit >= 0 && spin >= b.spinLimit:
+		return Spin{}, StopTimeLimit
+	case c.StopWin > 0 && profit >= c.StopWin:
+		return Spin{}, StopWinTarget
+	case c.StopLoss > 0 && -profit >= c.StopLoss:
+		return Spin{}, StopLossLimit
+	case c.StopOnBust && p.CurrentBalance < p.BaseBet:
+		return Spin{}, StopBust
+	}
+
+	// Only draw when bonus buys are configured, so plain strategies use the
+	// same random stream as players without one
+	if len(c.BonusBuys) > 0 {
+		u := rng.Float64()
+		for _, buy := range c.BonusBuys {
+			if u < buy.Rate {
+				cost, ok := b.modeCosts[buy.Mode]
+				cost *= p.BaseBet
+				if ok && (!c.StopOnBust || p.CurrentBalance >= cost) {
+					return Spin{Stake: cost, Mode: buy.Mode}, ""
+				}
+				break
+			}
+			u -= buy.Rate
+		}
+	}
+
+	stake := b.multiple * p.BaseBet
+	if c.StopOnBust && stake > p.CurrentBalance {
+		// Bet what is left, in whole base bets, rather than more than the balance
+		stake = math.Max(1, math.Floor(p.CurrentBalance/p.BaseBet)) * p.BaseBet
+	}
+	return Spin{Stake: stake}, ""
+}
+
+func (b *configBehavior) Result(spin Spin, won float64) {
+	if spin.Mode != "" {
+		return // Bonus buys leave the progression alone
+	}
+	c := b.config
+	win := won > spin.Stake
+	switch {
+	case c.Progression == ProgressionMartingale && !win,
+		c.Progression == ProgressionParoli && win:
+		b.multiple = math.Min(b.multiple*c.ProgressionFactor, c.MaxBetMultiple)
+	default:
+		b.multiple = 1
+	}
+}
+
+// strategyIndex spreads strategies over players in proportion to their
+// shares, by player ID, so the mix does not depend on the RNG.
+func strategyIndex(strategies []StrategyConfig, playerID, players int) int {
+	var total float64
+	for _, s := range strategies {
+		total += s.Share
+	}
+	position := (float64(playerID) + 0.5) / float64(players) * total
+	var cumulative float64
+	for i, s := range strategies {
+		cumulative += s.Share
+		if position < cumulative {
+			return i
+		}
+	}
+	return len(strategies) - 1
+}
+
+// StrategyStats holds metrics for the players following one strategy.
+type StrategyStats struct {
+	Name               string                 `json:"name"`
+	Players            int                    `json:"players"`
+	FinalPoP           float64                `json:"final_pop"`
+	AvgFinalBalance    float64                `json:"avg_final_balance"`
+	MedianFinalBalance float64                `json:"median_final_balance"`
+	AvgSpins           float64                `json:"avg_spins"`
+	AvgWagered         float64                `json:"avg_wagered"`
+	ActualRTP          float64                `json:"actual_rtp"`
+	AvgMaxBet          float64                `json:"avg_max_bet"`
+	AvgBonusBuys       float64                `json:"avg_bonus_buys"`
+	AvgMaxDrawdown     float64                `json:"avg_max_drawdown"`
+	StopReasons        map[StopReason]float64 `json:"stop_reasons"` // Percent of players per reason
+}
+
+// CalcStrategyStats calculates metrics per strategy, in strategy order.
+func CalcStrategyStats(players []*Player, strategies []StrategyConfig) []StrategyStats {
+	if len(strategies) == 0 {
+		return nil
+	}
+
+	groups := make(map[string][]*Player, len(strategies))
+	for _, p := range players {
+		groups[p.Strategy] = append(groups[p.Strategy], p)
+	}
+
+	stats := make([]StrategyStats, 0, len(strategies))
+	for _, s := range strategies {
+		group := groups[s.Name]
+		st := StrategyStats{Name: s.Name, Players: len(group), StopReasons: map[StopReason]float64{}}
+		if len(group) == 0 {
+			stats = append(stats, st)
+			continue
+		}
+
+		balances := make([]float64, len(group))
+		var sumBalance, spins, wagered, won, maxBet, buys, drawdown float64
+		for i, p := range group {
+			balances[i] = p.CurrentBalance
+			sumBalance += p.CurrentBalance
+			spins += float64(p.TotalSpins)
+			wagered += p.TotalWagered
+			won += p.TotalWon
+			maxBet += p.MaxBet
+			buys += float64(p.BonusBuys)
+			drawdown += p.MaxDrawdown
+			st.StopReasons[p.StopReason]++
+		}
+		sort.Float64s(balances)
+
+		n := float64(len(group))
+		st.FinalPoP = CalcPoP(group)
+		st.AvgFinalBalance = round2(sumBalance / n)
+		st.MedianFinalBalance = round2(percentile(balances, 50))
+		st.AvgSpins = round2(spins / n)
+		st.AvgWagered = round2(wagered / n)
+		if wagered > 0 {
+			st.ActualRTP = round4(won / wagered)
+		}
+		st.AvgMaxBet = round2(maxBet / n)
+		st.AvgBonusBuys = round2(buys / n)
+		st.AvgMaxDrawdown = round4(drawdown / n)
+		for reason, count := range st.StopReasons {
+			st.StopReasons[reason] = round2(count / n * 100)
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
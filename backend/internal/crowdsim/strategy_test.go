@@ -0,0 +1,164 @@
+package crowdsim
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"stakergs"
+)
+
+func strategyConfig(t *testing.T, strategies ...StrategyConfig) SimConfig {
+	t.Helper()
+	config := DefaultConfig()
+	config.PlayerCount = 400
+	config.SpinsPerSession = 200
+	config.ParallelWorkers = 1
+	config.Seed = 7
+	config.Strategies = strategies
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func TestStrategies_FlatMatchesNoStrategy(t *testing.T) {
+	plain := NewCrowdSimulator(standardTable(), nil, strategyConfig(t)).Run(nil)
+	flat := NewCrowdSimulator(standardTable(), nil, strategyConfig(t, StrategyConfig{Name: "flat"})).Run(nil)
+
+	if plain.FinalPoP != flat.FinalPoP || plain.BalanceStats.Mean != flat.BalanceStats.Mean {
+		t.Errorf("a flat strategy changed the results: PoP %v vs %v, mean %v vs %v",
+			plain.FinalPoP, flat.FinalPoP, plain.BalanceStats.Mean, flat.BalanceStats.Mean)
+	}
+	if len(plain.StrategyStats) != 0 || len(flat.StrategyStats) != 1 || flat.StrategyStats[0].Players != 400 {
+		t.Errorf("unexpected strategy stats %+v / %+v", plain.StrategyStats, flat.StrategyStats)
+	}
+}
+
+func TestStrategies_StopsAndShares(t *testing.T) {
+	config := strategyConfig(t,
+		StrategyConfig{Name: "cautious", Share: 3, StopWin: 20, StopLoss: 30, StopOnBust: true},
+		StrategyConfig{Name: "timed", SessionMinutes: 5, SpinsPerMinute: 10},
+	)
+	result := NewCrowdSimulator(standardTable(), nil, config).Run(nil)
+
+	cautious, timed := result.StrategyStats[0], result.StrategyStats[1]
+	if cautious.Players != 300 || timed.Players != 100 {
+		t.Fatalf("players split %d/%d, want 300/100", cautious.Players, timed.Players)
+	}
+	if timed.AvgSpins != 50 || timed.StopReasons[StopTimeLimit] != 100 {
+		t.Errorf("timed players averaged %v spins, stop reasons %v", timed.AvgSpins, timed.StopReasons)
+	}
+
+	var total float64
+	for _, pct := range cautious.StopReasons {
+		total += pct
+	}
+	if math.Abs(total-100) > 0.05 || cautious.StopReasons[StopWinTarget] == 0 || cautious.StopReasons[StopLossLimit] == 0 {
+		t.Errorf("unexpected stop reasons %v", cautious.StopReasons)
+	}
+
+	for _, s := range result.PlayerSummaries {
+		if s.Strategy != "cautious" {
+			continue
+		}
+		// One spin can overshoot a limit by at most its net result
+		if s.StopReason == StopLossLimit && (s.FinalBalance > 70 || s.FinalBalance < 69) {
+			t.Errorf("player %d stopped at a loss with balance %v", s.ID, s.FinalBalance)
+		}
+		if s.StopReason == StopWinTarget && s.FinalBalance < 120 {
+			t.Errorf("player %d stopped at the win target with balance %v", s.ID, s.FinalBalance)
+		}
+	}
+
+	// Stopped players keep their balance for the rest of the PoP curve
+	if len(result.PoPCurve) != config.SpinsPerSession {
+		t.Errorf("PoP curve has %d points, want %d", len(result.PoPCurve), config.SpinsPerSession)
+	}
+}
+
+func TestStrategies_Martingale(t *testing.T) {
+	config := StrategyConfig{Progression: ProgressionMartingale, MaxBetMultiple: 4}
+	if err := config.Validate(0); err != nil {
+		t.Fatal(err)
+	}
+	behavior := config.NewBehavior(nil)
+	player := NewPlayer(0, 100, false, 0)
+	rng := rand.New(rand.NewSource(1))
+
+	var stakes []float64
+	for _, won := range []bool{false, false, false, false, true, false} {
+		spin, _ := behavior.Next(player, 0, rng)
+		stakes = append(stakes, spin.Stake)
+		payout := 0.0
+		if won {
+			payout = 2 * spin.Stake
+		}
+		behavior.Result(spin, payout)
+	}
+
+	want := []float64{1, 2, 4, 4, 4, 1}
+	for i := range want {
+		if stakes[i] != want[i] {
+			t.Fatalf("stakes %v, want %v", stakes, want)
+		}
+	}
+}
+
+func TestStrategies_BonusBuys(t *testing.T) {
+	bonus := &stakergs.LookupTable{Mode: "bonus", Cost: 100, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 1, Payout: 5000},
+		{SimID: 1, Weight: 1, Payout: 15000},
+	}}
+	config := strategyConfig(t, StrategyConfig{
+		Name:      "buyer",
+		BonusBuys: []BonusBuy{{Mode: "bonus", Rate: 1}},
+	})
+	if modes := config.BonusModes(); len(modes) != 1 || modes[0] != "bonus" {
+		t.Fatalf("BonusModes = %v", modes)
+	}
+
+	simulator := NewCrowdSimulator(standardTable(), nil, config)
+	simulator.AddBonusMode(bonus, nil)
+	stats := simulator.Run(nil).StrategyStats[0]
+
+	// Every spin is a buy costing 100 base bets and paying 50 or 150
+	if stats.AvgBonusBuys != 200 || stats.AvgWagered != 20000 || stats.AvgMaxBet != 100 {
+		t.Errorf("unexpected bonus buy stats %+v", stats)
+	}
+	if math.Abs(stats.ActualRTP-1) > 0.05 {
+		t.Errorf("actual RTP %v, want about 1", stats.ActualRTP)
+	}
+
+	bad := DefaultConfig()
+	bad.Strategies = []StrategyConfig{{BonusBuys: []BonusBuy{{Mode: "a", Rate: 0.6}, {Mode: "b", Rate: 0.6}}}}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected an error for bonus buy rates above 1")
+	}
+}
+
+// doubleOrNothing stakes its whole balance once, then stops.
+type doubleOrNothing struct{ played bool }
+
+func (d *doubleOrNothing) Next(p *Player, spin int, rng *rand.Rand) (Spin, StopReason) {
+	if d.played {
+		return Spin{}, StopWinTarget
+	}
+	return Spin{Stake: p.CurrentBalance}, ""
+}
+
+func (d *doubleOrNothing) Result(spin Spin, won float64) { d.played = true }
+
+func TestSetBehavior_CustomStrategy(t *testing.T) {
+	simulator := NewCrowdSimulator(standardTable(), nil, strategyConfig(t))
+	simulator.SetBehavior(func(playerID int) (Behavior, string) {
+		return &doubleOrNothing{}, "all_in"
+	})
+	result := simulator.Run(nil)
+
+	for _, s := range result.PlayerSummaries {
+		if s.Strategy != "all_in" || s.Spins != 1 || s.StopReason != StopWinTarget {
+			t.Fatalf("unexpected summary %+v", s)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// writeCSV writes a header row and then one line per row.
+func writeCSV(w io.Writer, d *Dataset) error {
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(d.Columns))
+	for i, c := range d.Columns {
+		record[i] = c.Name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	err := d.Each(func(row []interface{}) error {
+		for i, v := range row {
+			record[i] = formatValue(v)
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatValue formats a value as text. Floats use the fewest digits that
+// read back to the same value.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
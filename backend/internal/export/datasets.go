@@ -0,0 +1,197 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lutexplorer/internal/lut"
+	"stakergs"
+)
+
+// Dataset names.
+const (
+	DatasetDistribution = "distribution"
+	DatasetBuckets      = "buckets"
+	DatasetOutcomes     = "outcomes"
+	DatasetTrials       = "trials"
+)
+
+// TableDatasets lists the datasets built from a mode's table alone.
+var TableDatasets = []string{DatasetDistribution, DatasetBuckets, DatasetOutcomes}
+
+// ValidDataset reports whether name is a dataset, including trials.
+func ValidDataset(name string) bool {
+	for _, d := range TableDatasets {
+		if d == name {
+			return true
+		}
+	}
+	return name == DatasetTrials
+}
+
+// TableDataset returns the named dataset of a table.
+func TableDataset(name string, table *stakergs.LookupTable, analyzer *lut.Analyzer) (*Dataset, error) {
+	switch name {
+	case DatasetDistribution:
+		return Distribution(table, analyzer), nil
+	case DatasetBuckets:
+		return Buckets(table, analyzer), nil
+	case DatasetOutcomes:
+		return Outcomes(table), nil
+	default:
+		return nil, fmt.Errorf("unknown dataset %q (want distribution, buckets or outcomes)", name)
+	}
+}
+
+// Distribution is one row per distinct payout, highest first, with all its
+// sim_ids. Payouts are in base bets.
+func Distribution(table *stakergs.LookupTable, analyzer *lut.Analyzer) *Dataset {
+	totalWeight := table.TotalWeight()
+	items := analyzer.BuildFullDistribution(table, totalWeight)
+	maxText := 0
+	for _, item := range items {
+		maxText = max(maxText, len(item.Odds), joinedLen(item.SimIDs))
+	}
+	return &Dataset{
+		Name: DatasetDistribution,
+		Columns: []Column{
+			{"payout", Float}, {"weight", Uint}, {"probability", Float}, {"odds", String},
+			{"count", Int}, {"sim_ids", String},
+		},
+		Rows:       len(items),
+		MaxTextLen: maxText,
+		Each: func(fn func(row []interface{}) error) error {
+			row := make([]interface{}, 6)
+			for _, item := range items {
+				row[0] = item.Payout
+				row[1] = item.Weight
+				row[2] = probability(item.Weight, totalWeight)
+				row[3] = item.Odds
+				row[4] = int64(item.Count)
+				row[5] = joinIDs(item.SimIDs)
+				if err := fn(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Buckets is one row per non-empty payout range of the stats histogram,
+// with the sim_ids of its outcomes.
+func Buckets(table *stakergs.LookupTable, analyzer *lut.Analyzer) *Dataset {
+	buckets := analyzer.BuildPayoutBuckets(table, table.TotalWeight())
+	simIDs := analyzer.PayoutBucketSimIDs(table, buckets)
+	maxText := 0
+	for _, ids := range simIDs {
+		maxText = max(maxText, joinedLen(ids))
+	}
+	return &Dataset{
+		Name: DatasetBuckets,
+		Columns: []Column{
+			{"range_start", Float}, {"range_end", Float}, {"count", Int}, {"weight", Uint},
+			{"probability", Float}, {"sim_ids", String},
+		},
+		Rows:       len(buckets),
+		MaxTextLen: maxText,
+		Each: func(fn func(row []interface{}) error) error {
+			row := make([]interface{}, 6)
+			for i, b := range buckets {
+				row[0] = b.RangeStart
+				row[1] = b.RangeEnd
+				row[2] = int64(b.Count)
+				row[3] = b.Weight
+				row[4] = b.Probability
+				row[5] = joinIDs(simIDs[i])
+				if err := fn(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Outcomes is one row per outcome of the table, in table order.
+func Outcomes(table *stakergs.LookupTable) *Dataset {
+	totalWeight := table.TotalWeight()
+	return &Dataset{
+		Name:    DatasetOutcomes,
+		Columns: []Column{{"sim_id", Int}, {"weight", Uint}, {"payout", Float}, {"probability", Float}},
+		Rows:    len(table.Outcomes),
+		Each: func(fn func(row []interface{}) error) error {
+			row := make([]interface{}, 4)
+			for _, o := range table.Outcomes {
+				row[0] = int64(o.SimID)
+				row[1] = o.Weight
+				row[2] = float64(o.Payout) / 100.0
+				row[3] = probability(o.Weight, totalWeight)
+				if err := fn(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Trials is one row per trial of a simulation run with AllTrialSummaries.
+func Trials(result *lut.SimulationResult) *Dataset {
+	trials := result.TrialSummaries
+	return &Dataset{
+		Name: DatasetTrials,
+		Columns: []Column{
+			{"trial", Int}, {"total_won", Float}, {"rtp", Float}, {"hit_count", Int},
+			{"max_win", Float}, {"passed_rtp", Bool},
+		},
+		Rows: len(trials),
+		Each: func(fn func(row []interface{}) error) error {
+			row := make([]interface{}, 6)
+			for _, t := range trials {
+				row[0] = int64(t.Trial)
+				row[1] = t.TotalWon
+				row[2] = t.RTP
+				row[3] = int64(t.HitCount)
+				row[4] = t.MaxWin
+				row[5] = t.PassedRTP
+				if err := fn(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func probability(weight, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(weight) / float64(total)
+}
+
+// joinedLen returns the length of joinIDs(ids) without building it.
+func joinedLen(ids []int) int {
+	if len(ids) == 0 {
+		return 0
+	}
+	n := len(ids) - 1
+	for _, id := range ids {
+		n += len(strconv.Itoa(id))
+	}
+	return n
+}
+
+// joinIDs lists sim_ids separated by spaces.
+func joinIDs(ids []int) string {
+	var b strings.Builder
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strconv.Itoa(id))
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+// Package export streams tabular LUT and simulation data as CSV, XLSX or
+// Parquet files for spreadsheets and notebooks. Unlike the JSON API, exports
+// are never truncated: distributions and buckets list every sim_id.
+package export
+
+import (
+	"fmt"
+	"io"
+)
+
+// Export formats.
+const (
+	FormatCSV     = "csv"
+	FormatXLSX    = "xlsx"
+	FormatParquet = "parquet"
+)
+
+// Formats lists the export formats.
+var Formats = []string{FormatCSV, FormatXLSX, FormatParquet}
+
+// ColumnType is the type of a column's values.
+type ColumnType int
+
+const (
+	Int    ColumnType = iota // int64
+	Uint                     // uint64, for weights, which may exceed the int64 range
+	Float                    // float64
+	String                   // string
+	Bool                     // bool
+)
+
+// Column is a named, typed column of a dataset.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Dataset is a table whose rows are produced one at a time, so exports are
+// written as they are generated.
+type Dataset struct {
+	Name    string
+	Columns []Column
+	Rows    int // Number of rows Each produces
+
+	// MaxTextLen is the length in bytes of the longest String value, so
+	// formats with a cell size limit can refuse the dataset up front.
+	MaxTextLen int
+
+	// Each calls fn with every row in order, stopping at the first error.
+	// Values match the column types; the row slice may be reused.
+	Each func(fn func(row []interface{}) error) error
+}
+
+// ContentType returns the MIME type of a format.
+func ContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// FileName returns the file name for a dataset of a mode.
+func FileName(mode string, d *Dataset, format string) string {
+	return mode + "_" + d.Name + "." + format
+}
+
+// ValidFormat reports whether format is an export format.
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Check reports whether the dataset can be written in format, so callers can
+// reject an export before they start streaming it. XLSX refuses datasets with
+// more rows than a worksheet or more text than a cell holds, rather than
+// cutting them short.
+func Check(d *Dataset, format string) error {
+	switch format {
+	case FormatCSV, FormatParquet:
+		return nil
+	case FormatXLSX:
+		return checkXLSX(d)
+	default:
+		return fmt.Errorf("unknown export format %q (want csv, xlsx or parquet)", format)
+	}
+}
+
+// Write streams the dataset to w in format.
+func Write(w io.Writer, d *Dataset, format string) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, d)
+	case FormatXLSX:
+		return writeXLSX(w, d)
+	case FormatParquet:
+		return writeParquet(w, d)
+	default:
+		return fmt.Errorf("unknown export format %q (want csv, xlsx or parquet)", format)
+	}
+}
@@ -0,0 +1,402 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"lutexplorer/internal/lut"
+	"lutexplorer/internal/sampling"
+	"stakergs"
+)
+
+// exportTable has 12 sim_ids paying 2x, more than the JSON distribution lists.
+func exportTable() *stakergs.LookupTable {
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1}
+	for i := 0; i < 20; i++ {
+		payout := uint(0)
+		switch {
+		case i < 12:
+			payout = 200
+		case i == 19:
+			payout = 10000
+		}
+		table.Outcomes = append(table.Outcomes, stakergs.Outcome{SimID: i, Weight: uint64(i + 1), Payout: payout})
+	}
+	return table
+}
+
+// rowsOf collects a dataset's rows formatted as text.
+func rowsOf(t *testing.T, d *Dataset) [][]string {
+	t.Helper()
+	var rows [][]string
+	err := d.Each(func(row []interface{}) error {
+		if len(row) != len(d.Columns) {
+			t.Fatalf("row has %d values for %d columns", len(row), len(d.Columns))
+		}
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = formatValue(v)
+		}
+		rows = append(rows, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != d.Rows {
+		t.Fatalf("%s produced %d rows, says %d", d.Name, len(rows), d.Rows)
+	}
+	return rows
+}
+
+func TestDatasets_FullSimIDs(t *testing.T) {
+	table := exportTable()
+	analyzer := lut.NewAnalyzer()
+
+	dist := rowsOf(t, Distribution(table, analyzer))
+	if len(dist) != 3 || dist[1][0] != "2" || dist[1][4] != "12" {
+		t.Fatalf("unexpected distribution %v", dist)
+	}
+	if got := len(strings.Fields(dist[1][5])); got != 12 {
+		t.Errorf("2x payout lists %d sim_ids, want all 12", got)
+	}
+
+	buckets := rowsOf(t, Buckets(table, analyzer))
+	var ids int
+	for _, b := range buckets {
+		if got := len(strings.Fields(b[5])); formatValue(int64(got)) != b[2] {
+			t.Errorf("bucket %v lists %d sim_ids", b[:5], got)
+		}
+		ids += len(strings.Fields(b[5]))
+	}
+	if ids != 20 {
+		t.Errorf("buckets list %d sim_ids, want 20", ids)
+	}
+
+	outcomes := rowsOf(t, Outcomes(table))
+	if len(outcomes) != 20 || outcomes[19][0] != "19" || outcomes[19][2] != "100" {
+		t.Errorf("unexpected outcomes %v", outcomes[19])
+	}
+
+	result := lut.NewSimulator(sampling.NewCache()).RunSimulation(table, lut.SimulationConfig{
+		Spins: 10, Trials: 150, Bet: 1, TargetRTP: 0.97, Seed: 1, AllTrialSummaries: true,
+	})
+	if trials := rowsOf(t, Trials(result)); len(trials) != 150 || trials[149][0] != "150" {
+		t.Errorf("expected all 150 trials, got %d", len(trials))
+	}
+
+	if _, err := TableDataset("trials", table, analyzer); err == nil {
+		t.Error("expected an error for a dataset that needs a simulation")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	d := Distribution(exportTable(), lut.NewAnalyzer())
+
+	var buf bytes.Buffer
+	if err := Write(&buf, d, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(records[0], ",") != "payout,weight,probability,odds,count,sim_ids" {
+		t.Errorf("unexpected header %v", records[0])
+	}
+	want := rowsOf(t, d)
+	for i, row := range want {
+		if strings.Join(records[i+1], ",") != strings.Join(row, ",") {
+			t.Errorf("row %d is %v, want %v", i, records[i+1], row)
+		}
+	}
+
+	if err := Write(&buf, d, "json"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	d := Outcomes(exportTable())
+
+	var buf bytes.Buffer
+	if err := Write(&buf, d, FormatXLSX); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("workbook is missing %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="outcomes"`) {
+		t.Error("sheet is not named after the dataset")
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr" s="1"><is><t>sim_id</t></is></c>`,
+		`<row r="21"><c r="A21"><v>19</v></c><c r="B21"><v>20</v></c><c r="C21"><v>100</v></c>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet is missing %s", want)
+		}
+	}
+
+	// Long sim_id lists are refused rather than cut short
+	wide := &stakergs.LookupTable{Mode: "base", Cost: 1}
+	for i := 0; i < 10000; i++ {
+		wide.Outcomes = append(wide.Outcomes, stakergs.Outcome{SimID: i, Weight: 1, Payout: 200})
+	}
+	dist := Distribution(wide, lut.NewAnalyzer())
+	if Check(dist, FormatXLSX) == nil || Check(dist, FormatCSV) != nil {
+		t.Error("expected Check to reject a sim_id list longer than a cell for xlsx only")
+	}
+	if err := Write(io.Discard, dist, FormatXLSX); err == nil {
+		t.Error("expected an error writing a sim_id list longer than a cell")
+	}
+
+	tooBig := &Dataset{Name: "big", Columns: d.Columns, Rows: xlsxMaxRows, Each: d.Each}
+	if err := Write(io.Discard, tooBig, FormatXLSX); err == nil {
+		t.Error("expected an error for more rows than a worksheet holds")
+	}
+	if Check(tooBig, FormatXLSX) == nil || Check(tooBig, FormatCSV) != nil {
+		t.Error("expected Check to reject only the worksheet export")
+	}
+
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestWrite_WeightsAboveInt64(t *testing.T) {
+	const weight = math.MaxUint64 - 1
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: weight, Payout: 0}, {SimID: 1, Weight: 1, Payout: 200},
+	}}
+	d := Outcomes(table)
+	want := "18446744073709551614"
+
+	var buf bytes.Buffer
+	if err := Write(&buf, d, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\n0,"+want+",0,") {
+		t.Errorf("csv weight wrapped: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := Write(&buf, d, FormatParquet); err != nil {
+		t.Fatal(err)
+	}
+	if got := readParquet(t, buf.Bytes(), d.Columns); got[0][1] != want {
+		t.Errorf("parquet weight is %s, want %s", got[0][1], want)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, d, FormatXLSX); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, _ := f.Open()
+		sheet, _ := io.ReadAll(rc)
+		rc.Close()
+		if !strings.Contains(string(sheet), `<c r="B2" t="inlineStr"><is><t>`+want+`</t></is></c>`) {
+			t.Errorf("xlsx weight not kept exactly: %s", sheet)
+		}
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	// More outcomes than a row group holds, plus every column type
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1}
+	for i := 0; i < parquetRowGroupRows+100; i++ {
+		table.Outcomes = append(table.Outcomes, stakergs.Outcome{SimID: i, Weight: uint64(1 + i%5), Payout: uint(i % 7 * 100)})
+	}
+	result := lut.NewSimulator(sampling.NewCache()).RunSimulation(table, lut.SimulationConfig{
+		Spins: 10, Trials: 20, Bet: 1, TargetRTP: 0.97, Seed: 1,
+	})
+
+	for _, d := range []*Dataset{Outcomes(table), Distribution(table, lut.NewAnalyzer()), Trials(result)} {
+		var buf bytes.Buffer
+		if err := Write(&buf, d, FormatParquet); err != nil {
+			t.Fatal(err)
+		}
+		got := readParquet(t, buf.Bytes(), d.Columns)
+		want := rowsOf(t, d)
+		if len(got) != len(want) {
+			t.Fatalf("%s: read %d rows, wrote %d", d.Name, len(got), len(want))
+		}
+		for i := range want {
+			if strings.Join(got[i], ",") != strings.Join(want[i], ",") {
+				t.Fatalf("%s row %d: read %v, wrote %v", d.Name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+// readParquet decodes a file written by writeParquet following the format
+// spec, checking the schema against columns.
+func readParquet(t *testing.T, data []byte, columns []Column) [][]string {
+	t.Helper()
+
+	if string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &compactReader{b: data[len(data)-8-footerLen : len(data)-8]}
+	meta := footer.readStruct()
+
+	schema := meta[2].([]interface{})
+	if len(schema) != len(columns)+1 || schema[0].(map[int16]interface{})[5] != int64(len(columns)) {
+		t.Fatalf("unexpected schema %v", schema)
+	}
+	for i, c := range columns {
+		leaf := schema[i+1].(map[int16]interface{})
+		if leaf[4] != c.Name || leaf[3] != int64(repetitionReq) {
+			t.Fatalf("schema column %d is %v, want %s", i, leaf, c.Name)
+		}
+		if c.Type == Uint && leaf[6] != int64(convertedUint) {
+			t.Fatalf("schema column %s is not UINT_64: %v", c.Name, leaf)
+		}
+	}
+
+	var rows [][]string
+	for _, g := range meta[4].([]interface{}) {
+		group := g.(map[int16]interface{})
+		n := int(group[3].(int64))
+		start := len(rows)
+		for i := 0; i < n; i++ {
+			rows = append(rows, make([]string, len(columns)))
+		}
+
+		for col, chunk := range group[1].([]interface{}) {
+			md := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			page := &compactReader{b: data, pos: int(md[9].(int64))}
+			header := page.readStruct()
+			values := data[page.pos : page.pos+int(header[3].(int64))]
+			if count := header[5].(map[int16]interface{})[1].(int64); int(count) != n {
+				t.Fatalf("page has %d values for %d rows", count, n)
+			}
+
+			for i := 0; i < n; i++ {
+				var v interface{}
+				switch md[1].(int64) {
+				case parquetInt64:
+					if columns[col].Type == Uint {
+						v = binary.LittleEndian.Uint64(values)
+					} else {
+						v = int64(binary.LittleEndian.Uint64(values))
+					}
+					values = values[8:]
+				case parquetDouble:
+					v = math.Float64frombits(binary.LittleEndian.Uint64(values))
+					values = values[8:]
+				case parquetBoolean:
+					v = values[i/8]&(1<<(i%8)) != 0
+				case parquetByteArray:
+					size := int(binary.LittleEndian.Uint32(values))
+					v = string(values[4 : 4+size])
+					values = values[4+size:]
+				}
+				rows[start+i][col] = formatValue(v)
+			}
+		}
+	}
+	if meta[3].(int64) != int64(len(rows)) {
+		t.Fatalf("file has %d rows, row groups hold %d", meta[3], len(rows))
+	}
+	return rows
+}
+
+// compactReader decodes thrift compact protocol structs into maps by field ID.
+type compactReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *compactReader) byte() byte {
+	b := r.b[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) readStruct() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(unzigzag(r.uvarint()))
+		}
+		last = id
+		fields[id] = r.readValue(header & 0x0f)
+	}
+}
+
+func (r *compactReader) readValue(typ byte) interface{} {
+	switch typ {
+	case compactI32, compactI64:
+		return unzigzag(r.uvarint())
+	case compactBinary:
+		n := int(r.uvarint())
+		r.pos += n
+		return string(r.b[r.pos-n : r.pos])
+	case compactList:
+		header := r.byte()
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.readValue(header & 0x0f)
+		}
+		return list
+	case compactStruct:
+		return r.readStruct()
+	}
+	panic("unexpected thrift type")
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
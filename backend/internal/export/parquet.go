@@ -0,0 +1,350 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// parquetRowGroupRows bounds the rows buffered before a row group is written.
+const parquetRowGroupRows = 65536
+
+// Parquet physical types, from the format's thrift definitions.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+)
+
+// Parquet encodings, converted types and page types.
+const (
+	encodingPlain  = 0
+	encodingRLE    = 3
+	convertedUTF8  = 0
+	convertedUint  = 14 // UINT_64
+	pageTypeData   = 0
+	repetitionReq  = 0
+	codecNone      = 0
+	parquetVersion = 1
+)
+
+// parquetColumn buffers the PLAIN-encoded values of one column of the row
+// group being written.
+type parquetColumn struct {
+	Column
+	values bytes.Buffer
+	bools  []bool
+}
+
+func (c *parquetColumn) physicalType() int32 {
+	switch c.Type {
+	case Int, Uint:
+		return parquetInt64
+	case Float:
+		return parquetDouble
+	case Bool:
+		return parquetBoolean
+	default:
+		return parquetByteArray
+	}
+}
+
+func (c *parquetColumn) add(v interface{}) {
+	var b [8]byte
+	switch v := v.(type) {
+	case int64:
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		c.values.Write(b[:])
+	case uint64:
+		binary.LittleEndian.PutUint64(b[:], v)
+		c.values.Write(b[:])
+	case float64:
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+		c.values.Write(b[:])
+	case bool:
+		c.bools = append(c.bools, v)
+	case string:
+		binary.LittleEndian.PutUint32(b[:4], uint32(len(v)))
+		c.values.Write(b[:4])
+		c.values.WriteString(v)
+	}
+}
+
+// page returns the column's PLAIN-encoded values and resets the buffer.
+// Booleans are bit-packed, first value in the lowest bit.
+func (c *parquetColumn) page() []byte {
+	if c.Type == Bool {
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, v := range c.bools {
+			if v {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		c.bools = c.bools[:0]
+		return packed
+	}
+	page := append([]byte(nil), c.values.Bytes()...)
+	c.values.Reset()
+	return page
+}
+
+// parquetChunk locates one column's data in the file.
+type parquetChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+// parquetRowGroup records the chunks of a written row group.
+type parquetRowGroup struct {
+	chunks []parquetChunk
+	rows   int64
+}
+
+// countingWriter tracks the file offset for the footer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// writeParquet writes an uncompressed Parquet file with one required column
+// per dataset column, in row groups of up to parquetRowGroupRows rows.
+// Strings are UTF-8 byte arrays and unsigned integers are UINT_64 int64s.
+func writeParquet(w io.Writer, d *Dataset) error {
+	cw := &countingWriter{w: w}
+	if _, err := io.WriteString(cw, "PAR1"); err != nil {
+		return err
+	}
+
+	columns := make([]*parquetColumn, len(d.Columns))
+	for i, c := range d.Columns {
+		columns[i] = &parquetColumn{Column: c}
+	}
+
+	var groups []parquetRowGroup
+	var rows int64
+	flush := func() error {
+		if rows == 0 {
+			return nil
+		}
+		group := parquetRowGroup{rows: rows}
+		for _, c := range columns {
+			values := c.page()
+			header := &compactWriter{}
+			header.i32(1, pageTypeData)
+			header.i32(2, int32(len(values)))
+			header.i32(3, int32(len(values)))
+			header.beginStruct(5)
+			header.i32(1, int32(rows))
+			header.i32(2, encodingPlain)
+			header.i32(3, encodingRLE)
+			header.i32(4, encodingRLE)
+			header.endStruct()
+			header.stop()
+
+			chunk := parquetChunk{offset: cw.n, values: rows}
+			if _, err := cw.Write(header.buf.Bytes()); err != nil {
+				return err
+			}
+			if _, err := cw.Write(values); err != nil {
+				return err
+			}
+			chunk.size = cw.n - chunk.offset
+			group.chunks = append(group.chunks, chunk)
+		}
+		groups = append(groups, group)
+		rows = 0
+		return nil
+	}
+
+	err := d.Each(func(row []interface{}) error {
+		for i, v := range row {
+			columns[i].add(v)
+		}
+		rows++
+		if rows == parquetRowGroupRows {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	footer := parquetFooter(columns, groups)
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	for _, b := range [][]byte{footer, length[:], []byte("PAR1")} {
+		if _, err := cw.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parquetFooter encodes the file metadata.
+func parquetFooter(columns []*parquetColumn, groups []parquetRowGroup) []byte {
+	var totalRows int64
+	for _, g := range groups {
+		totalRows += g.rows
+	}
+
+	m := &compactWriter{}
+	m.i32(1, parquetVersion)
+
+	// Schema: a root with one required leaf per column
+	m.listHeader(2, compactStruct, 1+len(columns))
+	m.beginElement()
+	m.str(4, "schema")
+	m.i32(5, int32(len(columns)))
+	m.endStruct()
+	for _, c := range columns {
+		m.beginElement()
+		m.i32(1, c.physicalType())
+		m.i32(3, repetitionReq)
+		m.str(4, c.Name)
+		switch c.Type {
+		case String:
+			m.i32(6, convertedUTF8)
+		case Uint:
+			m.i32(6, convertedUint)
+		}
+		m.endStruct()
+	}
+
+	m.i64(3, totalRows)
+
+	m.listHeader(4, compactStruct, len(groups))
+	for _, g := range groups {
+		m.beginElement()
+		m.listHeader(1, compactStruct, len(g.chunks))
+		var groupSize int64
+		for i, chunk := range g.chunks {
+			c := columns[i]
+			groupSize += chunk.size
+
+			m.beginElement()
+			m.i64(2, chunk.offset)
+			m.beginStruct(3)
+			m.i32(1, c.physicalType())
+			m.listHeader(2, compactI32, 1)
+			m.varint(zigzag(encodingPlain))
+			m.listHeader(3, compactBinary, 1)
+			m.bytes(c.Name)
+			m.i32(4, codecNone)
+			m.i64(5, chunk.values)
+			m.i64(6, chunk.size)
+			m.i64(7, chunk.size)
+			m.i64(9, chunk.offset)
+			m.endStruct()
+			m.endStruct()
+		}
+		m.i64(2, groupSize)
+		m.i64(3, g.rows)
+		m.endStruct()
+	}
+
+	m.str(6, "lutexplorer")
+	m.stop()
+	return m.buf.Bytes()
+}
+
+// Thrift compact protocol type codes.
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes a thrift struct with the compact protocol, which
+// Parquet uses for page headers and file metadata.
+type compactWriter struct {
+	buf   bytes.Buffer
+	last  int16   // Last field ID of the struct being written
+	outer []int16 // Last field IDs of the enclosing structs
+}
+
+func (c *compactWriter) field(id int16, typ byte) {
+	if delta := id - c.last; delta > 0 && delta <= 15 {
+		c.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		c.buf.WriteByte(typ)
+		c.varint(zigzag(int64(id)))
+	}
+	c.last = id
+}
+
+func (c *compactWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	c.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func (c *compactWriter) i32(id int16, v int32) {
+	c.field(id, compactI32)
+	c.varint(zigzag(int64(v)))
+}
+
+func (c *compactWriter) i64(id int16, v int64) {
+	c.field(id, compactI64)
+	c.varint(zigzag(v))
+}
+
+func (c *compactWriter) str(id int16, s string) {
+	c.field(id, compactBinary)
+	c.bytes(s)
+}
+
+// bytes writes a length-prefixed string without a field header, as in lists.
+func (c *compactWriter) bytes(s string) {
+	c.varint(uint64(len(s)))
+	c.buf.WriteString(s)
+}
+
+func (c *compactWriter) listHeader(id int16, elem byte, n int) {
+	c.field(id, compactList)
+	if n < 15 {
+		c.buf.WriteByte(byte(n)<<4 | elem)
+		return
+	}
+	c.buf.WriteByte(0xf0 | elem)
+	c.varint(uint64(n))
+}
+
+// beginStruct starts a struct field; beginElement starts a struct in a list.
+func (c *compactWriter) beginStruct(id int16) {
+	c.field(id, compactStruct)
+	c.beginElement()
+}
+
+func (c *compactWriter) beginElement() {
+	c.outer = append(c.outer, c.last)
+	c.last = 0
+}
+
+func (c *compactWriter) endStruct() {
+	c.stop()
+	c.last = c.outer[len(c.outer)-1]
+	c.outer = c.outer[:len(c.outer)-1]
+}
+
+// stop ends the struct being written.
+func (c *compactWriter) stop() {
+	c.buf.WriteByte(0)
+}
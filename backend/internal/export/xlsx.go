@@ -0,0 +1,173 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Excel worksheet limits.
+const (
+	xlsxMaxRows     = 1048576
+	xlsxMaxCellText = 32767
+)
+
+// xlsxParts are the fixed parts of a workbook with one worksheet. The sheet
+// name is filled into the workbook.
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	// Style 1 is the bold header
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// xlsxMaxExactInt is the largest integer a cell, which holds a double, keeps
+// exactly. Larger unsigned values are written as text.
+const xlsxMaxExactInt = 1 << 53
+
+// checkXLSX rejects datasets with more rows than a worksheet holds or text
+// longer than a cell holds.
+func checkXLSX(d *Dataset) error {
+	if d.Rows+1 > xlsxMaxRows {
+		return fmt.Errorf("%s has %d rows, more than a worksheet holds; export it as csv or parquet", d.Name, d.Rows)
+	}
+	if d.MaxTextLen > xlsxMaxCellText {
+		return fmt.Errorf("%s has values of %d characters, more than a cell holds (%d); export it as csv or parquet",
+			d.Name, d.MaxTextLen, xlsxMaxCellText)
+	}
+	return nil
+}
+
+// writeXLSX writes a workbook with one worksheet named after the dataset, a
+// frozen bold header row and one row per dataset row. Unsigned integers too
+// large for a cell to hold exactly are written as text.
+func writeXLSX(w io.Writer, d *Dataset) error {
+	if err := checkXLSX(d); err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		content := part.content
+		if part.name == "xl/workbook.xml" {
+			content = fmt.Sprintf(content, xmlEscape(d.Name))
+		}
+		if _, err := io.WriteString(f, content); err != nil {
+			return err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	sheet := bufio.NewWriter(f)
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sheet.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><sheetData>`)
+
+	refs := make([]string, len(d.Columns))
+	for i := range d.Columns {
+		refs[i] = columnName(i)
+	}
+
+	sheet.WriteString(`<row r="1">`)
+	for i, c := range d.Columns {
+		fmt.Fprintf(sheet, `<c r="%s1" t="inlineStr" s="1"><is><t>%s</t></is></c>`, refs[i], xmlEscape(c.Name))
+	}
+	sheet.WriteString(`</row>`)
+
+	rowNum := 1
+	err = d.Each(func(row []interface{}) error {
+		rowNum++
+		r := strconv.Itoa(rowNum)
+		sheet.WriteString(`<row r="` + r + `">`)
+		for i, v := range row {
+			ref := refs[i] + r
+			switch v := v.(type) {
+			case string:
+				if v == "" {
+					continue
+				}
+				if len(v) > xlsxMaxCellText {
+					return fmt.Errorf("%s has a value of %d characters, more than a cell holds (%d)", d.Name, len(v), xlsxMaxCellText)
+				}
+				fmt.Fprintf(sheet, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, xmlEscape(v))
+			case uint64:
+				if v > xlsxMaxExactInt {
+					fmt.Fprintf(sheet, `<c r="%s" t="inlineStr"><is><t>%d</t></is></c>`, ref, v)
+				} else {
+					fmt.Fprintf(sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+				}
+			case bool:
+				b := "0"
+				if v {
+					b = "1"
+				}
+				fmt.Fprintf(sheet, `<c r="%s" t="b"><v>%s</v></c>`, ref, b)
+			default:
+				fmt.Fprintf(sheet, `<c r="%s"><v>%s</v></c>`, ref, formatValue(v))
+			}
+		}
+		_, err := sheet.WriteString(`</row>`)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	sheet.WriteString(`</sheetData></worksheet>`)
+	if err := sheet.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// columnName returns the letters of a 0-indexed column: A, B, ..., Z, AA, ...
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
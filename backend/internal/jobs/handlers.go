@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"errors"
+	"net/http"
+
+	"lutexplorer/internal/common"
+)
+
+// Handlers provides HTTP handlers for inspecting and cancelling jobs. Jobs
+// are started by the endpoints of the package that owns the work.
+type Handlers struct {
+	manager *Manager
+}
+
+// NewHandlers creates new job handlers.
+func NewHandlers(manager *Manager) *Handlers {
+	return &Handlers{manager: manager}
+}
+
+// WriteStarted sends the info of a newly started job with 202 Accepted.
+func WriteStarted(w http.ResponseWriter, info Info) {
+	common.WriteJSON(w, http.StatusAccepted, common.Response{
+		Success: true,
+		Data:    info,
+	})
+}
+
+// HandleList returns all known jobs, newest first.
+// GET /api/jobs
+func (h *Handlers) HandleList(w http.ResponseWriter, r *http.Request) {
+	common.WriteSuccess(w, h.manager.List())
+}
+
+// HandleGet returns a job's status and progress.
+// GET /api/jobs/{id}
+func (h *Handlers) HandleGet(w http.ResponseWriter, r *http.Request) {
+	info, err := h.manager.Get(r.PathValue("id"))
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	common.WriteSuccess(w, info)
+}
+
+// HandleResult returns the result of a completed job.
+// GET /api/jobs/{id}/result
+func (h *Handlers) HandleResult(w http.ResponseWriter, r *http.Request) {
+	info, result, err := h.manager.Result(r.PathValue("id"))
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch info.Status {
+	case StatusCompleted:
+		common.WriteSuccess(w, map[string]interface{}{
+			"job":    info,
+			"result": result,
+		})
+	case StatusFailed:
+		common.WriteError(w, http.StatusConflict, "job failed: "+info.Error)
+	default:
+		common.WriteError(w, http.StatusConflict, "job is "+string(info.Status))
+	}
+}
+
+// HandleCancel cancels a running job.
+// DELETE /api/jobs/{id}
+func (h *Handlers) HandleCancel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.manager.Cancel(id); err != nil {
+		status := http.StatusConflict
+		if errors.Is(err, ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		common.WriteError(w, status, err.Error())
+		return
+	}
+
+	info, _ := h.manager.Get(id)
+	common.WriteSuccess(w, info)
+}
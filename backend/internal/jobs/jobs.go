@@ -0,0 +1,295 @@
+// Package jobs runs long simulations and optimizations in the background.
+// Each job gets an ID, broadcasts its progress through the WebSocket hub and
+// can be cancelled; its result is kept for retrieval after it finishes.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"lutexplorer/internal/ws"
+)
+
+// Job kinds.
+const (
+	KindSimulation = "simulation"
+	KindCrowdSim   = "crowdsim"
+	KindOptimizer  = "optimizer"
+	KindReport     = "report"
+)
+
+// Status is the state of a job.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// MaxFinished is how many finished jobs are kept for retrieval. Older ones
+// are dropped, with their results, as new jobs finish.
+const MaxFinished = 50
+
+// progressInterval throttles progress broadcasts so fast jobs do not flood
+// the hub, which drops messages when its buffer is full.
+const progressInterval = 250 * time.Millisecond
+
+var (
+	// ErrNotFound is returned for an unknown or expired job ID.
+	ErrNotFound = errors.New("job not found")
+	// ErrFinished is returned when cancelling a job that already finished.
+	ErrFinished = errors.New("job already finished")
+)
+
+// Progress is the progress a job reports while running.
+type Progress struct {
+	Done    int64       `json:"done"`
+	Total   int64       `json:"total"`
+	Percent float64     `json:"percent"`
+	Detail  interface{} `json:"detail,omitempty"` // Kind-specific, e.g. the optimizer's current RTP
+}
+
+// Info describes a job. It is what the API returns and what progress
+// messages carry.
+type Info struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Mode       string     `json:"mode"`
+	Status     Status     `json:"status"`
+	Progress   Progress   `json:"progress"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ElapsedMs  int64      `json:"elapsed_ms"`
+}
+
+// Func is the work of a job. It should report progress as it goes and return
+// ctx.Err() promptly once ctx is cancelled.
+type Func func(ctx context.Context, report func(Progress)) (interface{}, error)
+
+type job struct {
+	info          Info
+	result        interface{}
+	cancel        context.CancelFunc
+	done          chan struct{}
+	lastBroadcast time.Time
+}
+
+// Manager runs jobs and keeps track of them.
+type Manager struct {
+	hub *ws.Hub
+
+	mu   sync.Mutex
+	jobs map[string]*job
+	seq  int
+}
+
+// NewManager creates a job manager broadcasting through hub, which may be nil.
+func NewManager(hub *ws.Hub) *Manager {
+	return &Manager{
+		hub:  hub,
+		jobs: make(map[string]*job),
+	}
+}
+
+// Start runs fn in the background and returns the new job's info.
+func (m *Manager) Start(kind, mode string, fn Func) Info {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m.mu.Lock()
+	m.seq++
+	j := &job{
+		info: Info{
+			ID:        fmt.Sprintf("%s-%d", kind, m.seq),
+			Kind:      kind,
+			Mode:      mode,
+			Status:    StatusRunning,
+			CreatedAt: time.Now(),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	m.jobs[j.info.ID] = j
+	info := j.info
+	m.mu.Unlock()
+
+	m.broadcast(ws.MsgJobStarted, info)
+	go m.run(ctx, j, fn)
+	return info
+}
+
+func (m *Manager) run(ctx context.Context, j *job, fn Func) {
+	result, err := m.call(ctx, j, fn)
+
+	m.mu.Lock()
+	now := time.Now()
+	j.info.FinishedAt = &now
+	j.info.ElapsedMs = now.Sub(j.info.CreatedAt).Milliseconds()
+	msgType := ws.MsgJobComplete
+	// The status follows what fn returned: a job cancelled too late to stop
+	// has still completed, and its side effects and result stand
+	switch {
+	case errors.Is(err, context.Canceled):
+		j.info.Status = StatusCancelled
+		msgType = ws.MsgJobCancelled
+	case err != nil:
+		j.info.Status = StatusFailed
+		j.info.Error = err.Error()
+		msgType = ws.MsgJobFailed
+	default:
+		j.info.Status = StatusCompleted
+		j.result = result
+		j.info.Progress.Done = j.info.Progress.Total
+		j.info.Progress.Percent = 100
+	}
+	info := j.info
+	m.pruneLocked()
+	m.mu.Unlock()
+
+	j.cancel()
+	close(j.done)
+	m.broadcast(msgType, info)
+}
+
+// call runs fn, turning a panic into an error so one bad job cannot take
+// down the server.
+func (m *Manager) call(ctx context.Context, j *job, fn Func) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return fn(ctx, func(p Progress) { m.report(j, p) })
+}
+
+func (m *Manager) report(j *job, p Progress) {
+	if p.Total > 0 {
+		p.Percent = float64(p.Done) * 100 / float64(p.Total)
+	}
+
+	m.mu.Lock()
+	if j.info.Status != StatusRunning {
+		m.mu.Unlock()
+		return
+	}
+	j.info.Progress = p
+	j.info.ElapsedMs = time.Since(j.info.CreatedAt).Milliseconds()
+	send := time.Since(j.lastBroadcast) >= progressInterval
+	if send {
+		j.lastBroadcast = time.Now()
+	}
+	info := j.info
+	m.mu.Unlock()
+
+	if send {
+		m.broadcast(ws.MsgJobProgress, info)
+	}
+}
+
+// pruneLocked drops the oldest finished jobs beyond MaxFinished.
+func (m *Manager) pruneLocked() {
+	var finished []*job
+	for _, j := range m.jobs {
+		if j.info.Status != StatusRunning {
+			finished = append(finished, j)
+		}
+	}
+	if len(finished) <= MaxFinished {
+		return
+	}
+	sort.Slice(finished, func(a, b int) bool {
+		return finished[a].info.FinishedAt.Before(*finished[b].info.FinishedAt)
+	})
+	for _, j := range finished[:len(finished)-MaxFinished] {
+		delete(m.jobs, j.info.ID)
+	}
+}
+
+func (m *Manager) broadcast(msgType ws.MessageType, info Info) {
+	if m.hub == nil {
+		return
+	}
+	m.hub.Broadcast(ws.Message{Type: msgType, Mode: info.Mode, Payload: info})
+}
+
+// Get returns a job's info.
+func (m *Manager) Get(id string) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return Info{}, ErrNotFound
+	}
+	info := j.info
+	if info.Status == StatusRunning {
+		info.ElapsedMs = time.Since(info.CreatedAt).Milliseconds()
+	}
+	return info, nil
+}
+
+// Result returns a job's info and, once it has completed, its result.
+func (m *Manager) Result(id string) (Info, interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return Info{}, nil, ErrNotFound
+	}
+	return j.info, j.result, nil
+}
+
+// List returns all known jobs, newest first.
+func (m *Manager) List() []Info {
+	m.mu.Lock()
+	infos := make([]Info, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		infos = append(infos, j.info)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(infos, func(a, b int) bool {
+		return infos[a].CreatedAt.After(infos[b].CreatedAt)
+	})
+	return infos
+}
+
+// Cancel stops a running job. The job reports StatusCancelled once its
+// function returns.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if j.info.Status != StatusRunning {
+		return ErrFinished
+	}
+	j.cancel()
+	return nil
+}
+
+// Wait blocks until a job finishes or ctx is done, then returns its info.
+func (m *Manager) Wait(ctx context.Context, id string) (Info, error) {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return Info{}, ErrNotFound
+	}
+
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return Info{}, ctx.Err()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return j.info, nil
+}
@@ -0,0 +1,180 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func waitFor(t *testing.T, m *Manager, id string) Info {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	info, err := m.Wait(ctx, id)
+	if err != nil {
+		t.Fatalf("waiting for %s: %v", id, err)
+	}
+	return info
+}
+
+func TestManager_CompletesWithResult(t *testing.T) {
+	m := NewManager(nil)
+	info := m.Start(KindSimulation, "base", func(ctx context.Context, report func(Progress)) (interface{}, error) {
+		for i := int64(1); i <= 4; i++ {
+			report(Progress{Done: i, Total: 4})
+		}
+		return "done", nil
+	})
+	if info.Status != StatusRunning || info.Kind != KindSimulation || info.Mode != "base" {
+		t.Fatalf("unexpected start info %+v", info)
+	}
+
+	info = waitFor(t, m, info.ID)
+	if info.Status != StatusCompleted || info.Progress.Percent != 100 || info.FinishedAt == nil {
+		t.Errorf("unexpected final info %+v", info)
+	}
+
+	_, result, err := m.Result(info.ID)
+	if err != nil || result != "done" {
+		t.Errorf("Result = %v, %v; want done", result, err)
+	}
+	if err := m.Cancel(info.ID); !errors.Is(err, ErrFinished) {
+		t.Errorf("Cancel of a finished job = %v, want ErrFinished", err)
+	}
+}
+
+func TestManager_CancelStopsJob(t *testing.T) {
+	m := NewManager(nil)
+	started := make(chan struct{})
+	info := m.Start(KindCrowdSim, "bonus", func(ctx context.Context, report func(Progress)) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	<-started
+	if err := m.Cancel(info.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	info = waitFor(t, m, info.ID)
+	if info.Status != StatusCancelled {
+		t.Errorf("status %s, want %s", info.Status, StatusCancelled)
+	}
+	if _, result, _ := m.Result(info.ID); result != nil {
+		t.Errorf("expected no result for a cancelled job, got %v", result)
+	}
+}
+
+func TestManager_LateCancelKeepsResult(t *testing.T) {
+	m := NewManager(nil)
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	info := m.Start(KindOptimizer, "base", func(ctx context.Context, report func(Progress)) (interface{}, error) {
+		close(started)
+		<-cancelled // Past the point of no return, like saved weights
+		return "saved", nil
+	})
+
+	<-started
+	if err := m.Cancel(info.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	close(cancelled)
+	info = waitFor(t, m, info.ID)
+	if info.Status != StatusCompleted {
+		t.Errorf("status %s, want %s", info.Status, StatusCompleted)
+	}
+	if _, result, _ := m.Result(info.ID); result != "saved" {
+		t.Errorf("expected the result of a job that finished, got %v", result)
+	}
+}
+
+func TestManager_FailuresAndPanics(t *testing.T) {
+	m := NewManager(nil)
+	failed := m.Start(KindOptimizer, "base", func(ctx context.Context, report func(Progress)) (interface{}, error) {
+		return nil, errors.New("no convergence")
+	})
+	panicked := m.Start(KindOptimizer, "base", func(ctx context.Context, report func(Progress)) (interface{}, error) {
+		panic("boom")
+	})
+
+	if info := waitFor(t, m, failed.ID); info.Status != StatusFailed || info.Error != "no convergence" {
+		t.Errorf("unexpected failed info %+v", info)
+	}
+	if info := waitFor(t, m, panicked.ID); info.Status != StatusFailed || info.Error != "job panicked: boom" {
+		t.Errorf("unexpected panicked info %+v", info)
+	}
+}
+
+func TestManager_PrunesOldFinishedJobs(t *testing.T) {
+	m := NewManager(nil)
+	var first string
+	for i := 0; i < MaxFinished+5; i++ {
+		info := m.Start(KindSimulation, "base", func(ctx context.Context, report func(Progress)) (interface{}, error) {
+			return i, nil
+		})
+		if i == 0 {
+			first = info.ID
+		}
+		waitFor(t, m, info.ID)
+	}
+
+	if got := len(m.List()); got != MaxFinished {
+		t.Errorf("kept %d jobs, want %d", got, MaxFinished)
+	}
+	if _, err := m.Get(first); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the oldest job to be dropped, got %v", err)
+	}
+}
+
+func TestHandlers_ResultAndCancel(t *testing.T) {
+	m := NewManager(nil)
+	h := NewHandlers(m)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/jobs/{id}/result", h.HandleResult)
+	mux.HandleFunc("DELETE /api/jobs/{id}", h.HandleCancel)
+
+	release := make(chan struct{})
+	info := m.Start(KindSimulation, "base", func(ctx context.Context, report func(Progress)) (interface{}, error) {
+		select {
+		case <-release:
+			return map[string]int{"spins": 10}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	do := func(method, target string) (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		var body map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		return rec.Code, body
+	}
+
+	if code, _ := do(http.MethodGet, "/api/jobs/"+info.ID+"/result"); code != http.StatusConflict {
+		t.Errorf("result of a running job: status %d, want %d", code, http.StatusConflict)
+	}
+	if code, _ := do(http.MethodGet, "/api/jobs/simulation-99/result"); code != http.StatusNotFound {
+		t.Errorf("result of an unknown job: status %d, want %d", code, http.StatusNotFound)
+	}
+
+	close(release)
+	waitFor(t, m, info.ID)
+	code, body := do(http.MethodGet, "/api/jobs/"+info.ID+"/result")
+	if code != http.StatusOK {
+		t.Fatalf("result of a completed job: status %d", code)
+	}
+	result := body["data"].(map[string]interface{})["result"].(map[string]interface{})
+	if result["spins"] != float64(10) {
+		t.Errorf("unexpected result %v", result)
+	}
+
+	if code, _ := do(http.MethodDelete, "/api/jobs/"+info.ID); code != http.StatusConflict {
+		t.Errorf("cancel of a finished job: status %d, want %d", code, http.StatusConflict)
+	}
+}
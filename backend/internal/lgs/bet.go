@@ -0,0 +1,63 @@
+package lgs
+
+import (
+	"fmt"
+	"slices"
+
+	"stakergs"
+)
+
+// RGS error codes returned in ErrorResponse.Code, matching the codes the
+// production RGS uses so the front-end's error handling can be exercised locally.
+const (
+	ErrCodeInvalidRequest      = "ERR_VAL"  // Malformed request or invalid bet
+	ErrCodeUnknownMode         = "ERR_MODE" // The mode is not in the library
+	ErrCodeInsufficientBalance = "ERR_IPB"  // Insufficient player balance
+	ErrCodeGeneral             = "ERR_GEN"  // Any other server-side failure
+)
+
+// RGSError is a request failure carrying an RGS error code.
+type RGSError struct {
+	Code    string
+	Message string
+}
+
+func (e *RGSError) Error() string {
+	return e.Message
+}
+
+// newRGSError creates an RGSError with a formatted message.
+func newRGSError(code, format string, args ...interface{}) *RGSError {
+	return &RGSError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// betModeFor returns the bet limits that apply to a mode: its bet-mode
+// entry if the config has one, otherwise the game-wide limits.
+func betModeFor(config ConfigInfo, mode string) stakergs.BetMode {
+	if betMode, ok := lookupBetMode(config.BetModes, mode); ok {
+		return betMode
+	}
+	return stakergs.BetMode{
+		Name:   mode,
+		ID:     mode,
+		MinBet: uint(config.MinBet),
+		MaxBet: uint(config.MaxBet),
+	}
+}
+
+// validateBet checks a base bet amount (before the mode cost is applied)
+// against the mode limits, the bet step and the configured bet levels.
+func validateBet(config ConfigInfo, mode stakergs.BetMode, amount int64) error {
+	if amount <= 0 || !mode.ValidateBet(uint(amount)) {
+		return newRGSError(ErrCodeInvalidRequest, "invalid bet: amount %d is outside the %s mode limits (%d-%d)",
+			amount, mode.ID, mode.MinBet, mode.MaxBet)
+	}
+	if config.StepBet > 0 && (amount-config.MinBet)%config.StepBet != 0 {
+		return newRGSError(ErrCodeInvalidRequest, "invalid bet: amount %d is not a multiple of the bet step %d",
+			amount, config.StepBet)
+	}
+	if len(config.BetLevels) > 0 && !slices.Contains(config.BetLevels, amount) {
+		return newRGSError(ErrCodeInvalidRequest, "invalid bet: amount %d is not one of the configured bet levels", amount)
+	}
+	return nil
+}
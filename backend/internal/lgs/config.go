@@ -0,0 +1,274 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"lutexplorer/internal/lut"
+	"stakergs"
+)
+
+// GameConfigFile is the per-library LGS configuration file, stored in the library folder.
+const GameConfigFile = "lgs_config.json"
+
+// DefaultCurrency is used when neither the request nor the game config sets a currency.
+const DefaultCurrency = "USD"
+
+// GameConfig holds the game configuration returned by /wallet/authenticate.
+//
+// The configuration is read from GameConfigFile in the library folder. Fields
+// the file leaves empty are derived from index.json and the global defaults,
+// so a library without a config file gets one bet-mode entry per mode.
+type GameConfig struct {
+	loader *lut.Loader
+	path   string // Empty when the loader has no library folder; changes are then kept in memory
+
+	mu     sync.RWMutex
+	config ConfigInfo
+}
+
+// NewGameConfig creates the game config for a loader's library, reading the
+// library's config file if present. A broken file is reported and ignored so
+// the LGS still starts with the derived defaults.
+func NewGameConfig(loader *lut.Loader) *GameConfig {
+	gc := &GameConfig{loader: loader}
+	if dir := loader.LibraryDir(); dir != "" {
+		gc.path = filepath.Join(dir, GameConfigFile)
+	}
+
+	if gc.path != "" {
+		config, err := readGameConfig(gc.path)
+		if err != nil {
+			fmt.Printf("[LGS] Ignoring game config: %v\n", err)
+		} else {
+			gc.config = config
+		}
+	}
+
+	return gc
+}
+
+// readGameConfig reads a config file. A missing file yields an empty config.
+func readGameConfig(path string) (ConfigInfo, error) {
+	var config ConfigInfo
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, fmt.Errorf("failed to read %s: %w", GameConfigFile, err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %w", GameConfigFile, err)
+	}
+	return config, nil
+}
+
+// Path returns the config file path, or an empty string if changes are not persisted.
+func (gc *GameConfig) Path() string {
+	return gc.path
+}
+
+// Get returns the effective configuration.
+func (gc *GameConfig) Get() ConfigInfo {
+	gc.mu.RLock()
+	config := gc.config
+	gc.mu.RUnlock()
+
+	return gc.withDefaults(config)
+}
+
+// Set validates and applies a new configuration, writing it to the library's
+// config file. Returns the effective configuration.
+func (gc *GameConfig) Set(config ConfigInfo) (ConfigInfo, error) {
+	effective := gc.withDefaults(config)
+	if err := gc.validate(config, effective); err != nil {
+		return ConfigInfo{}, err
+	}
+
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	if gc.path != "" {
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return ConfigInfo{}, fmt.Errorf("failed to encode game config: %w", err)
+		}
+		if err := writeFileAtomic(gc.path, data, 0644); err != nil {
+			return ConfigInfo{}, fmt.Errorf("failed to save game config: %w", err)
+		}
+	}
+	gc.config = config
+
+	return effective, nil
+}
+
+// ForCurrency returns the effective configuration with the bet ladder of a
+// currency applied, along with the currency.
+func (gc *GameConfig) ForCurrency(code string) (ConfigInfo, Currency, error) {
+	gc.mu.RLock()
+	config := gc.config
+	gc.mu.RUnlock()
+
+	c, err := resolveCurrency(config, code)
+	if err != nil {
+		return ConfigInfo{}, Currency{}, err
+	}
+	return gc.withDefaults(withCurrencyLadder(config, c)), c, nil
+}
+
+// Currency returns a supported currency by code.
+func (gc *GameConfig) Currency(code string) (Currency, error) {
+	gc.mu.RLock()
+	defer gc.mu.RUnlock()
+	return resolveCurrency(gc.config, code)
+}
+
+// Currencies returns all supported currencies, sorted by code.
+func (gc *GameConfig) Currencies() []Currency {
+	gc.mu.RLock()
+	defer gc.mu.RUnlock()
+	return currencies(gc.config)
+}
+
+// withDefaults fills empty fields from index.json and the global defaults.
+// It never modifies config's maps or slices.
+func (gc *GameConfig) withDefaults(config ConfigInfo) ConfigInfo {
+	defaults := DefaultConfigInfo()
+
+	if config.GameID == "" && gc.loader.LibraryDir() != "" {
+		config.GameID = filepath.Base(gc.loader.LibraryDir())
+	}
+	if config.Currency == "" {
+		config.Currency = DefaultCurrency
+	}
+	if config.MinBet == 0 {
+		config.MinBet = defaults.MinBet
+	}
+	if config.MaxBet == 0 {
+		config.MaxBet = defaults.MaxBet
+	}
+	if config.StepBet == 0 {
+		config.StepBet = defaults.StepBet
+	}
+	if config.DefaultBetLevel == 0 {
+		config.DefaultBetLevel = defaults.DefaultBetLevel
+	}
+	if len(config.BetLevels) == 0 {
+		config.BetLevels = defaults.BetLevels
+	}
+
+	// One entry per mode in index.json. Name and limits can be overridden;
+	// the cost always comes from the index because that is what play charges.
+	betModes := make(map[string]stakergs.BetMode)
+	if index := gc.loader.GetIndex(); index != nil {
+		for _, m := range index.Modes {
+			betMode, _ := lookupBetMode(config.BetModes, m.Name)
+			betMode.ID = m.Name
+			if betMode.Name == "" {
+				betMode.Name = m.Name
+			}
+			betMode.Cost = uint(math.Round(m.Cost * APIMultiplier))
+			if betMode.MinBet == 0 {
+				betMode.MinBet = uint(config.MinBet)
+			}
+			if betMode.MaxBet == 0 {
+				betMode.MaxBet = uint(config.MaxBet)
+			}
+			betModes[m.Name] = betMode
+		}
+	}
+	config.BetModes = betModes
+
+	// Without an explicit list, expensive modes are treated as bonus buys
+	if config.BuyFeatureModes == nil {
+		config.BuyFeatureModes = []string{}
+		if index := gc.loader.GetIndex(); index != nil {
+			for _, m := range index.Modes {
+				if m.Cost >= BuyFeatureMinCost {
+					config.BuyFeatureModes = append(config.BuyFeatureModes, m.Name)
+				}
+			}
+		}
+	}
+
+	return config
+}
+
+// validate checks a configuration before it is applied. config is the
+// configuration as submitted and effective the same with defaults filled in.
+func (gc *GameConfig) validate(config, effective ConfigInfo) error {
+	if err := validateLadder(effective); err != nil {
+		return err
+	}
+
+	if _, err := resolveCurrency(config, effective.Currency); err != nil {
+		return err
+	}
+	for code, c := range config.Currencies {
+		c.Code = strings.ToUpper(code)
+		if err := validateCurrency(c); err != nil {
+			return err
+		}
+		if err := validateLadder(gc.withDefaults(withCurrencyLadder(config, c))); err != nil {
+			return fmt.Errorf("currency %s: %w", c.Code, err)
+		}
+	}
+
+	for id := range config.BetModes {
+		if _, err := gc.loader.GetMode(id); err != nil {
+			return fmt.Errorf("bet mode %q is not a mode of this library", id)
+		}
+	}
+	for _, mode := range config.BuyFeatureModes {
+		if _, err := gc.loader.GetMode(mode); err != nil {
+			return fmt.Errorf("buy feature mode %q is not a mode of this library", mode)
+		}
+	}
+	for id, betMode := range effective.BetModes {
+		if betMode.MaxBet < betMode.MinBet {
+			return fmt.Errorf("bet mode %q: min_bet (%d) exceeds max_bet (%d)", id, betMode.MinBet, betMode.MaxBet)
+		}
+	}
+	return nil
+}
+
+// validateLadder checks that the bet limits, step, levels and default bet agree.
+func validateLadder(effective ConfigInfo) error {
+	if effective.MinBet <= 0 || effective.MaxBet < effective.MinBet {
+		return fmt.Errorf("minBet (%d) must be positive and not exceed maxBet (%d)", effective.MinBet, effective.MaxBet)
+	}
+	if effective.StepBet <= 0 {
+		return fmt.Errorf("stepBet must be positive")
+	}
+
+	global := stakergs.BetMode{ID: "game", MinBet: uint(effective.MinBet), MaxBet: uint(effective.MaxBet)}
+	for _, level := range effective.BetLevels {
+		if err := validateBet(ConfigInfo{MinBet: effective.MinBet, StepBet: effective.StepBet}, global, level); err != nil {
+			return fmt.Errorf("betLevels: %w", err)
+		}
+	}
+	if err := validateBet(effective, global, effective.DefaultBetLevel); err != nil {
+		return fmt.Errorf("defaultBetLevel: %w", err)
+	}
+	return nil
+}
+
+// lookupBetMode finds a mode's entry by ID, ignoring case like Loader.GetMode.
+func lookupBetMode(betModes map[string]stakergs.BetMode, mode string) (stakergs.BetMode, bool) {
+	if betMode, ok := betModes[mode]; ok {
+		return betMode, true
+	}
+	for id, betMode := range betModes {
+		if strings.EqualFold(id, mode) {
+			return betMode, true
+		}
+	}
+	return stakergs.BetMode{}, false
+}
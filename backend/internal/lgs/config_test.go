@@ -0,0 +1,77 @@
+package lgs
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lutexplorer/internal/testutil"
+	"stakergs"
+)
+
+func TestGameConfig_LibraryFileAndRuntimeUpdate(t *testing.T) {
+	dir := testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: testCSV})
+	file := `{
+		"gameID": "test-game",
+		"currency": "EUR",
+		"betModes": {"BASE": {"name": "Base Game", "min_bet": 200000, "max_bet": 1000000}},
+		"jurisdiction": {"disabledTurbo": true}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, GameConfigFile), []byte(file), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h := newLibraryHandlers(t, dir)
+
+	var auth AuthResponse
+	call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate", AuthRequest{SessionID: "qa"}, &auth)
+	config := auth.Config
+	if config.GameID != "test-game" || config.Currency != "EUR" || !config.Jurisdiction.DisabledTurbo {
+		t.Errorf("file values not applied: %+v", config)
+	}
+	if config.MinBet != DefaultConfigInfo().MinBet || len(config.BetLevels) == 0 {
+		t.Errorf("omitted values not defaulted: %+v", config)
+	}
+	base, ok := config.BetModes["base"]
+	if !ok || base.Name != "Base Game" || base.MinBet != 200000 || base.Cost != APIMultiplier {
+		t.Errorf("bet mode: got %+v (found %v)", base, ok)
+	}
+
+	// The mode's own limits apply to play
+	rec := record(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base", Amount: 100000})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bet below mode minimum: got status %d", rec.Code)
+	}
+
+	// Invalid updates are rejected and leave the config untouched
+	if code := call(t, h.SetConfig, http.MethodPost, "/lgs/config",
+		ConfigInfo{BetModes: map[string]stakergs.BetMode{"bonus": {}}}, nil); code != http.StatusBadRequest {
+		t.Errorf("unknown mode: got status %d, want 400", code)
+	}
+	if code := call(t, h.SetConfig, http.MethodPost, "/lgs/config", ConfigInfo{DefaultBetLevel: 150000}, nil); code != http.StatusBadRequest {
+		t.Errorf("default bet level off the levels: got status %d, want 400", code)
+	}
+
+	var updated ConfigInfo
+	if code := call(t, h.SetConfig, http.MethodPost, "/lgs/config", ConfigInfo{GameID: "renamed", Currency: "GBP"}, &updated); code != http.StatusOK {
+		t.Fatalf("update: status %d", code)
+	}
+	if updated.GameID != "renamed" || updated.BetModes["base"].MinBet != uint(updated.MinBet) {
+		t.Errorf("update: got %+v", updated)
+	}
+
+	var saved ConfigInfo
+	data, _ := os.ReadFile(filepath.Join(dir, GameConfigFile))
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Currency != "GBP" {
+		t.Errorf("update not saved: %s (%v)", data, err)
+	}
+
+	// The file is replaced through a temp file that does not linger
+	if info, err := os.Stat(filepath.Join(dir, GameConfigFile)); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("saved config: %v (%v)", info, err)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, GameConfigFile+".*.tmp")); len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
@@ -0,0 +1,162 @@
+package lgs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"lutexplorer/internal/common"
+	"stakergs"
+)
+
+// Currency describes a currency the LGS can hold balances in.
+// Amounts are in API units, where 1000000 is one whole unit of the currency.
+//
+// A currency can carry its own bet ladder for currencies whose unit value is
+// far from the game's default, such as JPY or social-casino coins. Zero or empty
+// ladder fields fall back to the game config.
+type Currency struct {
+	Code           string  `json:"code"`
+	Name           string  `json:"name"`
+	Symbol         string  `json:"symbol"`
+	Decimals       int     `json:"decimals"`         // Decimal places shown to players
+	Social         bool    `json:"social,omitempty"` // Social-casino coin rather than real money
+	DefaultBalance int64   `json:"defaultBalance,omitempty"`
+	MinBet         int64   `json:"minBet,omitempty"`
+	MaxBet         int64   `json:"maxBet,omitempty"`
+	StepBet        int64   `json:"stepBet,omitempty"`
+	DefaultBet     int64   `json:"defaultBetLevel,omitempty"`
+	BetLevels      []int64 `json:"betLevels,omitempty"`
+}
+
+// hasLadder reports whether the currency defines its own bet ladder.
+func (c Currency) hasLadder() bool {
+	return c.MinBet != 0 || c.MaxBet != 0 || c.StepBet != 0 || c.DefaultBet != 0 || len(c.BetLevels) > 0
+}
+
+// startingBalance returns the balance a session gets in this currency.
+func (c Currency) startingBalance() int64 {
+	if c.DefaultBalance > 0 {
+		return c.DefaultBalance
+	}
+	return DefaultBalance
+}
+
+// scaledLevels multiplies the default bet levels by factor.
+func scaledLevels(factor int64) []int64 {
+	levels := common.DefaultBetLevels()
+	for i := range levels {
+		levels[i] *= factor
+	}
+	return levels
+}
+
+// builtinCurrencies are available without configuration, keyed by code.
+// Real-money currencies close in value to USD use the game's bet ladder.
+var builtinCurrencies = map[string]Currency{
+	"USD": {Code: "USD", Name: "US Dollar", Symbol: "$", Decimals: 2},
+	"EUR": {Code: "EUR", Name: "Euro", Symbol: "€", Decimals: 2},
+	"GBP": {Code: "GBP", Name: "British Pound", Symbol: "£", Decimals: 2},
+	"CAD": {Code: "CAD", Name: "Canadian Dollar", Symbol: "CA$", Decimals: 2},
+	"JPY": {
+		Code: "JPY", Name: "Japanese Yen", Symbol: "¥", Decimals: 0,
+		DefaultBalance: DefaultBalance * 100,
+		MinBet:         common.MinBet * 100,
+		MaxBet:         common.MaxBet * 100,
+		StepBet:        common.StepBet * 100,
+		DefaultBet:     common.DefaultBetLevel * 100,
+		BetLevels:      scaledLevels(100),
+	},
+	"GC": {
+		Code: "GC", Name: "Gold Coins", Symbol: "GC", Decimals: 0, Social: true,
+		DefaultBalance: 10_000_000 * 1_000_000,
+		MinBet:         100 * 1_000_000,
+		MaxBet:         100_000 * 1_000_000,
+		StepBet:        100 * 1_000_000,
+		DefaultBet:     1_000 * 1_000_000,
+		BetLevels: []int64{
+			100 * 1_000_000, 200 * 1_000_000, 500 * 1_000_000, 1_000 * 1_000_000,
+			2_000 * 1_000_000, 5_000 * 1_000_000, 10_000 * 1_000_000,
+			20_000 * 1_000_000, 50_000 * 1_000_000, 100_000 * 1_000_000,
+		},
+	},
+	"SC": {Code: "SC", Name: "Sweeps Coins", Symbol: "SC", Decimals: 2, Social: true},
+}
+
+// lookupCurrency returns a currency by code, ignoring case. Currencies in the
+// game config replace built-in ones with the same code.
+func lookupCurrency(config ConfigInfo, code string) (Currency, bool) {
+	code = strings.ToUpper(code)
+	for key, c := range config.Currencies {
+		if strings.ToUpper(key) == code {
+			c.Code = code
+			return c, true
+		}
+	}
+	c, ok := builtinCurrencies[code]
+	return c, ok
+}
+
+// currencies returns all currencies available under the config, sorted by code.
+func currencies(config ConfigInfo) []Currency {
+	codes := make(map[string]bool)
+	for code := range builtinCurrencies {
+		codes[code] = true
+	}
+	for code := range config.Currencies {
+		codes[strings.ToUpper(code)] = true
+	}
+
+	result := make([]Currency, 0, len(codes))
+	for code := range codes {
+		c, _ := lookupCurrency(config, code)
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// resolveCurrency validates a requested currency code and returns the currency.
+func resolveCurrency(config ConfigInfo, code string) (Currency, error) {
+	c, ok := lookupCurrency(config, code)
+	if !ok {
+		return Currency{}, newRGSError(ErrCodeInvalidRequest, "unsupported currency: %s", code)
+	}
+	return c, nil
+}
+
+// withCurrencyLadder returns the raw config with the currency's bet ladder
+// applied. Explicit per-mode limits are in the game's default currency, so they
+// are dropped when the currency brings its own ladder.
+func withCurrencyLadder(config ConfigInfo, c Currency) ConfigInfo {
+	if !c.hasLadder() {
+		return config
+	}
+
+	config.MinBet = c.MinBet
+	config.MaxBet = c.MaxBet
+	config.StepBet = c.StepBet
+	config.DefaultBetLevel = c.DefaultBet
+	config.BetLevels = c.BetLevels
+
+	betModes := make(map[string]stakergs.BetMode, len(config.BetModes))
+	for id, m := range config.BetModes {
+		m.MinBet, m.MaxBet = 0, 0
+		betModes[id] = m
+	}
+	config.BetModes = betModes
+	return config
+}
+
+// validateCurrency checks a configured currency.
+func validateCurrency(c Currency) error {
+	if c.Decimals < 0 || c.Decimals > 6 {
+		return fmt.Errorf("currency %s: decimals must be between 0 and 6", c.Code)
+	}
+	if c.DefaultBalance < 0 {
+		return fmt.Errorf("currency %s: defaultBalance must not be negative", c.Code)
+	}
+	return nil
+}
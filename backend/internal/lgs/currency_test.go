@@ -0,0 +1,181 @@
+package lgs
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"lutexplorer/internal/common"
+)
+
+func TestHandlers_CurrencyBalancesAndLadders(t *testing.T) {
+	h := newTestHandlers(t)
+
+	playErr := func(req PlayRequest) string {
+		t.Helper()
+		req.SessionID = "qa"
+		// Force a losing outcome so the round closes immediately
+		call(t, h.ForceOutcome, http.MethodPost, "/lgs/force-outcome",
+			map[string]interface{}{"sessionID": "qa", "mode": "base", "simID": 0}, nil)
+		rec := record(t, h.Play, http.MethodPost, "/wallet/play", req)
+		if rec.Code == http.StatusOK {
+			return ""
+		}
+		var resp ErrorResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return resp.Code
+	}
+
+	var auth AuthResponse
+	call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate", AuthRequest{SessionID: "qa"}, &auth)
+	if auth.Balance.Currency != DefaultCurrency || auth.Balance.Amount != DefaultBalance {
+		t.Fatalf("new session: got %+v, want %s %d", auth.Balance, DefaultCurrency, int64(DefaultBalance))
+	}
+	if err := playErr(PlayRequest{Mode: "base", Amount: common.DefaultBetLevel}); err != "" {
+		t.Fatalf("usd play: %s", err)
+	}
+	usdBalance := int64(DefaultBalance - common.DefaultBetLevel)
+
+	// JPY uses its own ladder: the USD default bet is too small
+	call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate", AuthRequest{SessionID: "qa", Currency: "jpy"}, &auth)
+	jpy := builtinCurrencies["JPY"]
+	if auth.Balance.Currency != "JPY" || auth.Balance.Amount != jpy.DefaultBalance {
+		t.Errorf("switch to jpy: got %+v, want JPY %d", auth.Balance, jpy.DefaultBalance)
+	}
+	if auth.Config.DefaultBetLevel != jpy.DefaultBet || auth.Config.BetModes["base"].MinBet != uint(jpy.MinBet) {
+		t.Errorf("jpy config: default %d, base min %d, want %d and %d",
+			auth.Config.DefaultBetLevel, auth.Config.BetModes["base"].MinBet, jpy.DefaultBet, jpy.MinBet)
+	}
+	if err := playErr(PlayRequest{Mode: "base", Amount: common.DefaultBetLevel}); err != ErrCodeInvalidRequest {
+		t.Errorf("usd-sized bet in jpy: got %q, want %q", err, ErrCodeInvalidRequest)
+	}
+	if err := playErr(PlayRequest{Mode: "base"}); err != "" {
+		t.Errorf("default jpy bet: %s", err)
+	}
+
+	// Social-casino coins via play's currency field
+	if err := playErr(PlayRequest{Mode: "base", Currency: "GC", Amount: 1000 * 1_000_000}); err != "" {
+		t.Errorf("gc play: %s", err)
+	}
+	if err := playErr(PlayRequest{Mode: "base", Currency: "DOGE"}); err != ErrCodeInvalidRequest {
+		t.Errorf("unsupported currency: got %q, want %q", err, ErrCodeInvalidRequest)
+	}
+
+	// Each currency keeps its own balance
+	var stats map[string]interface{}
+	call(t, h.Stats, http.MethodGet, "/lgs/stats?sessionID=qa", nil, &stats)
+	balances := stats["balances"].(map[string]interface{})
+	want := map[string]int64{
+		"USD": usdBalance,
+		"JPY": jpy.DefaultBalance - jpy.DefaultBet,
+		"GC":  builtinCurrencies["GC"].DefaultBalance - 1000*1_000_000,
+	}
+	if len(balances) != len(want) {
+		t.Errorf("balances: got %v, want %v", balances, want)
+	}
+	for code, amount := range want {
+		if got, _ := balances[code].(float64); int64(got) != amount {
+			t.Errorf("%s balance: got %v, want %d", code, balances[code], amount)
+		}
+	}
+
+	// Switching back restores the earlier balance
+	call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate", AuthRequest{SessionID: "qa", Currency: "USD"}, &auth)
+	if auth.Balance.Amount != usdBalance {
+		t.Errorf("back to usd: got %d, want %d", auth.Balance.Amount, usdBalance)
+	}
+
+	// The currency cannot change while a round is owed in the current one
+	call(t, h.ForceOutcome, http.MethodPost, "/lgs/force-outcome",
+		map[string]interface{}{"sessionID": "qa", "mode": "base", "simID": 4}, nil)
+	call(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"}, nil)
+	if code := call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate",
+		AuthRequest{SessionID: "qa", Currency: "EUR"}, nil); code != http.StatusBadRequest {
+		t.Errorf("switch during active round: got status %d, want 400", code)
+	}
+}
+
+func TestGameConfig_CurrencyOverrides(t *testing.T) {
+	h := newTestHandlers(t)
+
+	// A configured currency with a ladder that disagrees with itself is rejected
+	bad := ConfigInfo{Currencies: map[string]Currency{
+		"gc": {Name: "Gold Coins", MinBet: 100, MaxBet: 50},
+	}}
+	if _, err := h.config.Set(bad); err == nil {
+		t.Error("invalid currency ladder: got nil error")
+	}
+
+	// A custom default currency applies to new sessions
+	config := ConfigInfo{
+		Currency: "XTS",
+		Currencies: map[string]Currency{
+			"xts": {Name: "Test Coins", Symbol: "T", Decimals: 0, Social: true, DefaultBalance: 5_000 * 1_000_000},
+		},
+	}
+	if _, err := h.config.Set(config); err != nil {
+		t.Fatal(err)
+	}
+	h.applySessionDefaults()
+
+	var auth AuthResponse
+	call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate", AuthRequest{SessionID: "fresh"}, &auth)
+	if auth.Balance.Currency != "XTS" || auth.Balance.Amount != 5_000*1_000_000 {
+		t.Errorf("new session: got %+v, want XTS 5000000000", auth.Balance)
+	}
+
+	var list []Currency
+	call(t, h.Currencies, http.MethodGet, "/lgs/currencies", nil, &list)
+	found := false
+	for _, c := range list {
+		found = found || (c.Code == "XTS" && c.Social)
+	}
+	if !found {
+		t.Errorf("currencies: XTS missing from %+v", list)
+	}
+}
+
+func TestHandlers_RejectedPlayKeepsCurrency(t *testing.T) {
+	h := newTestHandlers(t)
+	jpy := builtinCurrencies["JPY"]
+
+	// The session has played in JPY before and has one default bet left there
+	h.sessions.WithSession("qa", func(s *SessionData) error {
+		s.Balances = map[string]int64{"JPY": jpy.DefaultBet}
+		return nil
+	})
+
+	rejected := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    interface{}
+	}{
+		// The USD default bet is off the JPY ladder
+		{"play", h.Play, PlayRequest{SessionID: "qa", Mode: "base", Currency: "JPY", Amount: common.DefaultBetLevel}},
+		{"batch play", h.BatchPlay, BatchPlayRequest{SessionID: "qa", Mode: "base", Currency: "JPY", Amount: common.DefaultBetLevel, Spins: 10}},
+		// More than the JPY balance
+		{"play over balance", h.Play, PlayRequest{SessionID: "qa", Mode: "base", Currency: "JPY", Amount: jpy.MaxBet}},
+		{"batch play over balance", h.BatchPlay, BatchPlayRequest{SessionID: "qa", Mode: "base", Currency: "JPY", Spins: 10}},
+	}
+	for _, tt := range rejected {
+		if rec := record(t, tt.handler, http.MethodPost, "/", tt.body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d %s, want 400", tt.name, rec.Code, rec.Body.String())
+		}
+		h.sessions.View("qa", func(s *SessionData) {
+			if s.Currency != DefaultCurrency || s.Balance != DefaultBalance || s.Balances["JPY"] != jpy.DefaultBet {
+				t.Errorf("%s: session moved to %s with balance %d (%v)", tt.name, s.Currency, s.Balance, s.Balances)
+			}
+		})
+	}
+
+	// An accepted play switches currency and bets from the JPY balance
+	call(t, h.ForceOutcome, http.MethodPost, "/lgs/force-outcome",
+		map[string]interface{}{"sessionID": "qa", "mode": "base", "simID": 0}, nil)
+	var play PlayResponse
+	if code := call(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base", Currency: "JPY"}, &play); code != http.StatusOK {
+		t.Fatalf("jpy play: status %d", code)
+	}
+	if play.Balance.Currency != "JPY" || play.Balance.Amount != 0 {
+		t.Errorf("accepted play: got %+v, want JPY 0", play.Balance)
+	}
+}
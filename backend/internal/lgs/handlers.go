@@ -2,7 +2,9 @@ package lgs
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"time"
 
@@ -19,10 +21,14 @@ type batchPlayStats struct {
 	hitCount     int
 	bigWins      int
 	megaWins     int
+	tierCounts   []int // Wins per win tier
 	maxWin       float64
 }
 
-// processBatchSpins executes multiple spins and returns statistics.
+// processBatchSpins executes multiple spins and returns statistics. Wins are
+// counted per tier, and as big and mega wins by the fixed 10x and 50x
+// thresholds; maxWin is the mode's max payout in base bets.
+// The caller must hold the session lock.
 func processBatchSpins(
 	session *SessionData,
 	sampleOutcome func() stakergs.Outcome,
@@ -30,8 +36,10 @@ func processBatchSpins(
 	betPerSpin int64,
 	baseAmount int64,
 	keepRounds bool,
+	tiers *lut.WinTiers,
+	maxWin float64,
 ) (batchPlayStats, []BatchPlayRound) {
-	var stats batchPlayStats
+	stats := batchPlayStats{tierCounts: make([]int, len(tiers.Tiers))}
 	var rounds []BatchPlayRound
 	if keepRounds {
 		rounds = make([]BatchPlayRound, 0, spins)
@@ -55,6 +63,9 @@ func processBatchSpins(
 		if payout > 0 {
 			stats.hitCount++
 		}
+		if tier := tiers.Classify(payoutMultiplier, maxWin); tier >= 0 {
+			stats.tierCounts[tier]++
+		}
 		if payoutMultiplier >= common.BigWinMultiplier {
 			stats.bigWins++
 		}
@@ -87,6 +98,15 @@ func processBatchSpins(
 	return stats, rounds
 }
 
+// winTierCounts maps tier names to their win counts.
+func winTierCounts(tiers *lut.WinTiers, counts []int) map[string]int {
+	result := make(map[string]int, len(tiers.Tiers))
+	for i, tier := range tiers.Tiers {
+		result[tier.Name] = counts[i]
+	}
+	return result
+}
+
 // extractEvents extracts only the "events" array from a book JSON.
 // Returns the events array or empty array if not found.
 func extractEvents(bookJSON json.RawMessage) json.RawMessage {
@@ -114,62 +134,88 @@ func extractEvents(bookJSON json.RawMessage) json.RawMessage {
 type Handlers struct {
 	loader   *lut.Loader
 	sessions *SessionManager
+	config   *GameConfig
 	wsHub    *ws.Hub
 }
 
 // NewHandlers creates new LGS handlers
 func NewHandlers(loader *lut.Loader, sessions *SessionManager, hub *ws.Hub) *Handlers {
-	return &Handlers{
+	h := &Handlers{
 		loader:   loader,
 		sessions: sessions,
+		config:   NewGameConfig(loader),
 		wsHub:    hub,
 	}
+	h.applySessionDefaults()
+	return h
 }
 
-// broadcastSessionsUpdate sends current sessions state to all WebSocket clients
-func (h *Handlers) broadcastSessionsUpdate() {
-	if h.wsHub == nil {
-		return
+// applySessionDefaults starts new sessions in the game's default currency.
+func (h *Handlers) applySessionDefaults() {
+	c, err := h.config.Currency(h.config.Get().Currency)
+	if err != nil {
+		c = builtinCurrencies[DefaultCurrency]
 	}
+	h.sessions.SetDefaults(c.Code, c.startingBalance())
+}
 
+// sessionConfig returns the game config as a session sees it: with the bet
+// ladder of its currency and the jurisdiction it has selected.
+// The caller must hold the session lock.
+func (h *Handlers) sessionConfig(session *SessionData) ConfigInfo {
+	config, _, err := h.config.ForCurrency(session.Currency)
+	if err != nil {
+		config = h.config.Get()
+	}
+	config.Jurisdiction = jurisdictionFor(config, session.Jurisdiction)
+	return config
+}
+
+// switchCurrency moves a session to a supported currency, keeping the balance
+// of the previous one. The caller must hold the session lock.
+func (h *Handlers) switchCurrency(session *SessionData, code string) error {
+	c, err := h.config.Currency(code)
+	if err != nil {
+		return err
+	}
+	return session.SwitchCurrency(c.Code, c.startingBalance())
+}
+
+// playConfig returns the config a play in code is validated against, and the
+// currency it is made in. An empty code means the session's current currency.
+// The session is not changed: the caller switches currency only once the play
+// is accepted. The caller must hold the session lock.
+func (h *Handlers) playConfig(session *SessionData, code string) (ConfigInfo, Currency, error) {
+	if code == "" {
+		return h.sessionConfig(session), Currency{Code: session.Currency}, nil
+	}
+	config, c, err := h.config.ForCurrency(code)
+	if err != nil {
+		return ConfigInfo{}, Currency{}, err
+	}
+	config.Jurisdiction = jurisdictionFor(config, session.Jurisdiction)
+	return config, c, nil
+}
+
+// sessionsResponse builds the session listing with aggregate statistics.
+// Each session is locked only while its summary is taken.
+func (h *Handlers) sessionsResponse() SessionsResponse {
 	allSessions := h.sessions.GetAll()
 	summaries := make([]SessionSummary, 0, len(allSessions))
 
 	var aggBets, aggWins, aggWagered, aggWon int64
 
 	for _, s := range allSessions {
-		rtp := 0.0
-		if s.TotalWagered > 0 {
-			rtp = float64(s.TotalWon) / float64(s.TotalWagered)
-		}
-		hitRate := 0.0
-		if s.TotalBets > 0 {
-			hitRate = float64(s.TotalWins) / float64(s.TotalBets)
-		}
-		profit := s.TotalWagered - s.TotalWon
-
-		summaries = append(summaries, SessionSummary{
-			SessionID:      s.SessionID,
-			Balance:        s.Balance,
-			Currency:       s.Currency,
-			TotalBets:      s.TotalBets,
-			TotalWins:      s.TotalWins,
-			TotalWagered:   s.TotalWagered,
-			TotalWon:       s.TotalWon,
-			RTP:            rtp,
-			HitRate:        hitRate,
-			Profit:         profit,
-			HistorySize:    len(s.History),
-			CreatedAt:      s.CreatedAt.Format("2006-01-02 15:04:05"),
-			LastActivity:   s.LastActivity.Format("2006-01-02 15:04:05"),
-			ForcedOutcomes: s.GetAllForcedSimIDs(),
-			RTPBias:        s.RTPBias,
-		})
-
-		aggBets += s.TotalBets
-		aggWins += s.TotalWins
-		aggWagered += s.TotalWagered
-		aggWon += s.TotalWon
+		s.Lock()
+		summary := s.summary()
+		s.Unlock()
+
+		summaries = append(summaries, summary)
+
+		aggBets += summary.TotalBets
+		aggWins += summary.TotalWins
+		aggWagered += summary.TotalWagered
+		aggWon += summary.TotalWon
 	}
 
 	overallRTP := 0.0
@@ -181,22 +227,31 @@ func (h *Handlers) broadcastSessionsUpdate() {
 		overallHitRate = float64(aggWins) / float64(aggBets)
 	}
 
-	h.wsHub.Broadcast(ws.Message{
-		Type: ws.MsgLGSSessionsUpdate,
-		Payload: SessionsResponse{
-			Sessions:      summaries,
-			TotalSessions: len(allSessions),
-			TotalCreated:  h.sessions.TotalCreated(),
-			AggregateStats: AggregateStats{
-				TotalBets:      aggBets,
-				TotalWins:      aggWins,
-				TotalWagered:   aggWagered,
-				TotalWon:       aggWon,
-				OverallRTP:     overallRTP,
-				OverallHitRate: overallHitRate,
-				TotalProfit:    aggWagered - aggWon,
-			},
+	return SessionsResponse{
+		Sessions:      summaries,
+		TotalSessions: len(allSessions),
+		TotalCreated:  h.sessions.TotalCreated(),
+		AggregateStats: AggregateStats{
+			TotalBets:      aggBets,
+			TotalWins:      aggWins,
+			TotalWagered:   aggWagered,
+			TotalWon:       aggWon,
+			OverallRTP:     overallRTP,
+			OverallHitRate: overallHitRate,
+			TotalProfit:    aggWagered - aggWon,
 		},
+	}
+}
+
+// broadcastSessionsUpdate sends current sessions state to all WebSocket clients
+func (h *Handlers) broadcastSessionsUpdate() {
+	if h.wsHub == nil {
+		return
+	}
+
+	h.wsHub.Broadcast(ws.Message{
+		Type:    ws.MsgLGSSessionsUpdate,
+		Payload: h.sessionsResponse(),
 	})
 }
 
@@ -215,6 +270,17 @@ func (h *Handlers) sendError(w http.ResponseWriter, message string, status int)
 	h.sendJSON(w, ErrorResponse{Error: message, Success: false}, status)
 }
 
+// sendRGSError sends an error response with an RGS error code.
+// Errors that are not an *RGSError are reported as ERR_GEN.
+func (h *Handlers) sendRGSError(w http.ResponseWriter, err error, status int) {
+	code := ErrCodeGeneral
+	var rgsErr *RGSError
+	if errors.As(err, &rgsErr) {
+		code = rgsErr.Code
+	}
+	h.sendJSON(w, ErrorResponse{Error: err.Error(), Code: code, Success: false}, status)
+}
+
 // Health handles /lgs/health
 func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
 	eventsLoaded := make(map[string]int)
@@ -243,30 +309,53 @@ func (h *Handlers) Authenticate(w http.ResponseWriter, r *http.Request) {
 		req.Language = "en"
 	}
 
-	session := h.sessions.GetOrCreate(req.SessionID)
-	session.Language = req.Language
+	var config ConfigInfo
+	var balance BalanceInfo
+	var round interface{}
+	err := h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		if req.Currency != "" {
+			if err := h.switchCurrency(session, req.Currency); err != nil {
+				return err
+			}
+		}
+		session.Language = req.Language
+		balance = BalanceInfo{Amount: session.Balance, Currency: session.Currency}
+		config = h.sessionConfig(session)
+		// Return an unfinished round so the game can resume it
+		if active := session.ActiveRound(); active != nil {
+			round = active
+		}
+		return nil
+	})
+	if err != nil {
+		h.sendRGSError(w, err, http.StatusBadRequest)
+		return
+	}
 
-	fmt.Printf("[LGS] Authenticate: session=%s, balance=%d\n", req.SessionID, session.Balance)
+	resumed := ""
+	if round != nil {
+		resumed = " [RESUME]"
+	}
+	fmt.Printf("[LGS] Authenticate: session=%s, balance=%d%s\n", req.SessionID, balance.Amount, resumed)
 
 	// Broadcast session update
 	h.broadcastSessionsUpdate()
 
 	h.sendJSON(w, AuthResponse{
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-		Round:  nil,
-		Config: DefaultConfigInfo(),
-		Meta:   nil,
+		Balance: balance,
+		Round:   round,
+		Config:  config,
+		Meta:    nil,
 	}, http.StatusOK)
 }
 
-// Play handles /lgs/play - spins the reels
+// Play handles /wallet/play - debits the bet and starts a round.
+// Winning rounds stay active until /wallet/end-round credits the payout;
+// rounds without a payout are closed immediately.
 func (h *Handlers) Play(w http.ResponseWriter, r *http.Request) {
 	var req PlayRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		h.sendRGSError(w, newRGSError(ErrCodeInvalidRequest, "invalid request body"), http.StatusBadRequest)
 		return
 	}
 
@@ -274,125 +363,152 @@ func (h *Handlers) Play(w http.ResponseWriter, r *http.Request) {
 		req.SessionID = "default-session"
 	}
 	if req.Mode == "" {
-		h.sendError(w, "mode is required", http.StatusBadRequest)
+		h.sendRGSError(w, newRGSError(ErrCodeInvalidRequest, "mode is required"), http.StatusBadRequest)
 		return
 	}
-	if req.Currency == "" {
-		req.Currency = "USD"
-	}
-	if req.Amount == 0 {
-		req.Amount = APIMultiplier
-	}
-
-	// Get session
-	session := h.sessions.GetOrCreate(req.SessionID)
-	session.Currency = req.Currency
 
 	// Get LUT for mode
 	table, err := h.loader.GetMode(req.Mode)
 	if err != nil {
-		h.sendError(w, fmt.Sprintf("mode not found: %s", req.Mode), http.StatusBadRequest)
+		h.sendRGSError(w, newRGSError(ErrCodeUnknownMode, "mode not found: %s", req.Mode), http.StatusBadRequest)
 		return
 	}
 
-	// Calculate total bet (amount * mode cost)
 	modeCost := table.Cost
 	if modeCost == 0 {
 		modeCost = 1.0
 	}
-	totalBet := int64(float64(req.Amount) * modeCost)
-
-	// Check balance
-	if session.Balance < totalBet {
-		h.sendError(w, "insufficient balance", http.StatusBadRequest)
-		return
-	}
-
-	// Deduct bet
-	session.Balance -= totalBet
 
-	// Check for forced outcome first
+	// The whole round runs under the session lock so concurrent plays
+	// against one session can neither overdraw nor lose balance updates
+	var totalBet int64
+	var seed, draw int64
+	var resp PlayResponse
 	var outcome stakergs.Outcome
 	var forced bool
-	if forcedSimID, ok := session.ConsumeForcedSimID(req.Mode); ok {
-		// Find the outcome with this simID
-		for _, o := range table.Outcomes {
-			if o.SimID == forcedSimID {
-				outcome = o
-				forced = true
-				break
-			}
+	var rtpBias float64
+	err = h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		if active := session.ActiveRound(); active != nil {
+			return newRGSError(ErrCodeInvalidRequest, "round %d is still active: call /wallet/end-round first", active.BetID)
 		}
-		if !forced {
-			h.sendError(w, fmt.Sprintf("forced simID %d not found in mode %s", forcedSimID, req.Mode), http.StatusBadRequest)
-			// Refund the bet
-			session.Balance += totalBet
-			return
+
+		// The bet is validated against the ladder of the play's currency
+		config, currency, err := h.playConfig(session, req.Currency)
+		if err != nil {
+			return err
 		}
-	} else {
-		// Use weighted random selection, with bias if set
-		if session.RTPBias != 0 {
-			sampler := lut.NewBiasedWeightedSampler(table, session.RTPBias)
-			outcome = sampler.SampleWithNewRNG()
+		if req.Amount == 0 {
+			req.Amount = config.DefaultBetLevel
+		}
+		if err := validateBet(config, betModeFor(config, req.Mode), req.Amount); err != nil {
+			return err
+		}
+		// Calculate total bet (amount * mode cost)
+		totalBet = int64(float64(req.Amount) * modeCost)
+
+		now := time.Now()
+		if err := checkJurisdiction(config.Jurisdiction, isBuyFeatureMode(config, req.Mode), session.LastPlayAt, now); err != nil {
+			return err
+		}
+
+		rtpBias = session.RTPBias
+
+		// Check balance
+		if session.BalanceIn(currency.Code, currency.startingBalance()) < totalBet {
+			return newRGSError(ErrCodeInsufficientBalance, "insufficient balance")
+		}
+
+		// Check for forced outcome first. The round only counts for the
+		// minimum round duration once it is committed.
+		if forcedSimID, ok := session.GetForcedSimID(req.Mode); ok {
+			// Find the outcome with this simID
+			for _, o := range table.Outcomes {
+				if o.SimID == forcedSimID {
+					outcome = o
+					forced = true
+					break
+				}
+			}
+			// A stale simID, as after a reload, is dropped so later plays are not stuck on it
+			session.ConsumeForcedSimID(req.Mode)
+			if !forced {
+				return newRGSError(ErrCodeInvalidRequest, "forced simID %d not found in mode %s", forcedSimID, req.Mode)
+			}
 		} else {
-			sampler := lut.NewWeightedSampler(table)
-			outcome = sampler.SampleWithNewRNG()
+			// Use weighted random selection from the session's next draw, with bias if set
+			var rng *rand.Rand
+			rng, seed, draw = session.NextRand()
+			outcome = h.loader.Sampler(table, session.RTPBias).Sample(rng)
 		}
-	}
 
-	// Calculate payout
-	payoutMultiplier := float64(outcome.Payout) / 100.0
-	payout := int64(float64(req.Amount) * payoutMultiplier)
+		// The play is accepted: move the session to its currency
+		if err := session.SwitchCurrency(currency.Code, currency.startingBalance()); err != nil {
+			return err
+		}
+		session.LastPlayAt = now
 
-	// Add payout to balance
-	session.Balance += payout
+		// Calculate payout
+		payoutMultiplier := float64(outcome.Payout) / 100.0
+		payout := int64(float64(req.Amount) * payoutMultiplier)
 
-	// Get event data (state) if available - extract only "events" array from book
-	var stateData json.RawMessage
-	eventsLoader := h.loader.EventsLoader()
-	if bookJSON, err := eventsLoader.GetEvent(req.Mode, outcome.SimID, table.SimIDOffset); err == nil {
-		stateData = extractEvents(bookJSON)
-	} else {
-		// Create minimal state if no event data
-		stateData = json.RawMessage(`[]`)
-	}
+		// Debit the bet; the payout is credited at end-round
+		session.Balance -= totalBet
 
-	// Create round info
-	betID := session.NextBetID(outcome.SimID)
-	roundInfo := RoundInfo{
-		BetID:            betID,
-		Amount:           totalBet,
-		Payout:           payout,
-		PayoutMultiplier: payoutMultiplier,
-		Active:           true,
-		State:            stateData,
-		Mode:             req.Mode,
-		Event:            nil,
-	}
+		// Get event data (state) if available - extract only "events" array from book
+		var stateData json.RawMessage
+		eventsLoader := h.loader.EventsLoader()
+		if bookJSON, err := eventsLoader.GetEvent(req.Mode, outcome.SimID, table.SimIDOffset); err == nil {
+			stateData = extractEvents(bookJSON)
+		} else {
+			// Create minimal state if no event data
+			stateData = json.RawMessage(`[]`)
+		}
+
+		// Create round info
+		betID := session.NextBetID(outcome.SimID)
+		roundInfo := RoundInfo{
+			BetID:            betID,
+			Amount:           totalBet,
+			Payout:           payout,
+			PayoutMultiplier: payoutMultiplier,
+			Active:           payout > 0,
+			State:            stateData,
+			Mode:             req.Mode,
+			Event:            nil,
+			Seed:             seed,
+			Draw:             draw,
+		}
+
+		// Add to history
+		session.AddRound(roundInfo)
 
-	// Add to history
-	session.AddRound(roundInfo)
-	h.sessions.Update(session)
+		resp = PlayResponse{
+			Balance: BalanceInfo{
+				Amount:   session.Balance,
+				Currency: session.Currency,
+			},
+			Round: roundInfo,
+		}
+		return nil
+	})
+	if err != nil {
+		h.sendRGSError(w, err, http.StatusBadRequest)
+		return
+	}
 
 	tag := ""
 	if forced {
 		tag = " [FORCED]"
-	} else if session.RTPBias != 0 {
-		tag = fmt.Sprintf(" [BIAS=%.2f]", session.RTPBias)
+	} else if rtpBias != 0 {
+		tag = fmt.Sprintf(" [BIAS=%.2f]", rtpBias)
 	}
 	fmt.Printf("[LGS] Play: session=%s, mode=%s, bet=%d, simID=%d, payout=%d (%.2fx)%s\n",
-		req.SessionID, req.Mode, totalBet, outcome.SimID, payout, payoutMultiplier, tag)
+		req.SessionID, req.Mode, totalBet, outcome.SimID, resp.Round.Payout, resp.Round.PayoutMultiplier, tag)
 
 	// Broadcast session update
 	h.broadcastSessionsUpdate()
 
-	h.sendJSON(w, PlayResponse{
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-		Round: roundInfo,
-	}, http.StatusOK)
+	h.sendJSON(w, resp, http.StatusOK)
 }
 
 // EndRound handles /wallet/end-round
@@ -406,23 +522,30 @@ func (h *Handlers) EndRound(w http.ResponseWriter, r *http.Request) {
 		req.SessionID = "default-session"
 	}
 
-	session := h.sessions.GetOrCreate(req.SessionID)
+	var config ConfigInfo
+	var balance BalanceInfo
+	var settled *RoundInfo
+	h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		// Credit the active round, if any; ending an already closed round is a no-op
+		settled = session.SettleRound()
+		balance = BalanceInfo{Amount: session.Balance, Currency: session.Currency}
+		config = h.sessionConfig(session)
+		return nil
+	})
 
-	// Mark round as inactive
-	if session.LastRound != nil {
-		session.LastRound.Active = false
+	if settled != nil {
+		fmt.Printf("[LGS] End Round: session=%s, betID=%d, payout=%d, balance=%d\n",
+			req.SessionID, settled.BetID, settled.Payout, balance.Amount)
+		h.broadcastSessionsUpdate()
+	} else {
+		fmt.Printf("[LGS] End Round: session=%s, no active round, balance=%d\n", req.SessionID, balance.Amount)
 	}
 
-	fmt.Printf("[LGS] End Round: session=%s, balance=%d\n", req.SessionID, session.Balance)
-
 	h.sendJSON(w, EndRoundResponse{
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-		Round:  nil,
-		Config: DefaultConfigInfo(),
-		Meta:   nil,
+		Balance: balance,
+		Round:   nil,
+		Config:  config,
+		Meta:    nil,
 	}, http.StatusOK)
 }
 
@@ -441,21 +564,24 @@ func (h *Handlers) History(w http.ResponseWriter, r *http.Request) {
 		req.Limit = 50
 	}
 
-	session := h.sessions.GetOrCreate(req.SessionID)
-
-	// Get last N rounds
-	rounds := session.History
-	if len(rounds) > req.Limit {
-		rounds = rounds[len(rounds)-req.Limit:]
-	}
+	var resp HistoryResponse
+	h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		// Copy the last N rounds; History is appended to under the lock
+		rounds := session.History
+		if len(rounds) > req.Limit {
+			rounds = rounds[len(rounds)-req.Limit:]
+		}
+		resp = HistoryResponse{
+			Rounds: append([]RoundInfo(nil), rounds...),
+			Balance: BalanceInfo{
+				Amount:   session.Balance,
+				Currency: session.Currency,
+			},
+		}
+		return nil
+	})
 
-	h.sendJSON(w, HistoryResponse{
-		Rounds: rounds,
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-	}, http.StatusOK)
+	h.sendJSON(w, resp, http.StatusOK)
 }
 
 // Stats handles /lgs/stats - returns session statistics
@@ -465,16 +591,18 @@ func (h *Handlers) Stats(w http.ResponseWriter, r *http.Request) {
 		sessionID = "default-session"
 	}
 
-	session := h.sessions.Get(sessionID)
-	if session == nil {
+	var stats map[string]interface{}
+	found := h.sessions.View(sessionID, func(session *SessionData) {
+		stats = session.GetStats()
+		stats["balance"] = session.Balance
+		stats["currency"] = session.Currency
+		stats["balances"] = session.AllBalances()
+	})
+	if !found {
 		h.sendError(w, "session not found", http.StatusNotFound)
 		return
 	}
 
-	stats := session.GetStats()
-	stats["balance"] = session.Balance
-	stats["currency"] = session.Currency
-
 	h.sendJSON(w, stats, http.StatusOK)
 }
 
@@ -491,22 +619,25 @@ func (h *Handlers) ResetBalance(w http.ResponseWriter, r *http.Request) {
 		req.SessionID = "default-session"
 	}
 
-	session := h.sessions.ResetBalance(req.SessionID)
-	if session == nil {
-		session = h.sessions.GetOrCreate(req.SessionID)
-	}
+	var balance BalanceInfo
+	h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		// Reset to the starting balance of the session's current currency
+		session.Balance = DefaultBalance
+		if c, err := h.config.Currency(session.Currency); err == nil {
+			session.Balance = c.startingBalance()
+		}
+		balance = BalanceInfo{Amount: session.Balance, Currency: session.Currency}
+		return nil
+	})
 
-	fmt.Printf("[LGS] Reset Balance: session=%s, balance=%d\n", req.SessionID, session.Balance)
+	fmt.Printf("[LGS] Reset Balance: session=%s, balance=%d\n", req.SessionID, balance.Amount)
 
 	// Broadcast session update
 	h.broadcastSessionsUpdate()
 
 	h.sendJSON(w, map[string]interface{}{
 		"success": true,
-		"balance": BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
+		"balance": balance,
 	}, http.StatusOK)
 }
 
@@ -531,24 +662,31 @@ func (h *Handlers) SetBalance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session := h.sessions.GetOrCreate(req.SessionID)
-	session.Balance = req.Balance
-	if req.Currency != "" {
-		session.Currency = req.Currency
+	var balance BalanceInfo
+	err := h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		// The balance is set in the given currency, which becomes the current one
+		if req.Currency != "" {
+			if err := h.switchCurrency(session, req.Currency); err != nil {
+				return err
+			}
+		}
+		session.Balance = req.Balance
+		balance = BalanceInfo{Amount: session.Balance, Currency: session.Currency}
+		return nil
+	})
+	if err != nil {
+		h.sendRGSError(w, err, http.StatusBadRequest)
+		return
 	}
-	h.sessions.Update(session)
 
-	fmt.Printf("[LGS] Set Balance: session=%s, balance=%d, currency=%s\n", req.SessionID, session.Balance, session.Currency)
+	fmt.Printf("[LGS] Set Balance: session=%s, balance=%d, currency=%s\n", req.SessionID, balance.Amount, balance.Currency)
 
 	// Broadcast session update
 	h.broadcastSessionsUpdate()
 
 	h.sendJSON(w, map[string]interface{}{
 		"success": true,
-		"balance": BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
+		"balance": balance,
 	}, http.StatusOK)
 }
 
@@ -556,7 +694,7 @@ func (h *Handlers) SetBalance(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) Event(w http.ResponseWriter, r *http.Request) {
 	var req EventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		h.sendRGSError(w, newRGSError(ErrCodeInvalidRequest, "invalid request body"), http.StatusBadRequest)
 		return
 	}
 
@@ -564,8 +702,16 @@ func (h *Handlers) Event(w http.ResponseWriter, r *http.Request) {
 		req.SessionID = "default-session"
 	}
 
-	// For simple slot games, event just acknowledges the event was processed
-	// In more complex games, this would advance game state
+	// Record progress so a resumed round can continue from the last event
+	var recorded bool
+	h.sessions.WithExisting(req.SessionID, func(session *SessionData) {
+		recorded = session.SetRoundEvent(req.Event)
+	})
+	if !recorded {
+		h.sendRGSError(w, newRGSError(ErrCodeInvalidRequest, "no active round"), http.StatusBadRequest)
+		return
+	}
+
 	fmt.Printf("[LGS] Event: session=%s, event=%s\n", req.SessionID, req.Event)
 
 	// Return simple event response
@@ -588,15 +734,9 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		req.SessionID = "default-session"
 	}
 	if req.Mode == "" {
-		h.sendError(w, "mode is required", http.StatusBadRequest)
+		h.sendRGSError(w, newRGSError(ErrCodeInvalidRequest, "mode is required"), http.StatusBadRequest)
 		return
 	}
-	if req.Currency == "" {
-		req.Currency = "USD"
-	}
-	if req.Amount == 0 {
-		req.Amount = APIMultiplier
-	}
 	if req.Spins <= 0 {
 		req.Spins = 100
 	}
@@ -604,46 +744,88 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		req.Spins = 100000
 	}
 
-	// Get session
-	session := h.sessions.GetOrCreate(req.SessionID)
-	session.Currency = req.Currency
-
 	// Get LUT for mode
 	table, err := h.loader.GetMode(req.Mode)
 	if err != nil {
-		h.sendError(w, fmt.Sprintf("mode not found: %s", req.Mode), http.StatusBadRequest)
+		h.sendRGSError(w, newRGSError(ErrCodeUnknownMode, "mode not found: %s", req.Mode), http.StatusBadRequest)
 		return
 	}
 
-	// Calculate bet per spin
 	modeCost := table.Cost
 	if modeCost == 0 {
 		modeCost = 1.0
 	}
-	betPerSpin := int64(float64(req.Amount) * modeCost)
-	totalBetRequired := betPerSpin * int64(req.Spins)
+	tiers := h.loader.WinTiers()
 
-	// Check balance
-	if session.Balance < totalBetRequired {
-		h.sendError(w, fmt.Sprintf("insufficient balance: need %d, have %d", totalBetRequired, session.Balance), http.StatusBadRequest)
-		return
-	}
+	// All spins run under the session lock, so single plays against the
+	// same session wait for the batch instead of interleaving with it
+	var stats batchPlayStats
+	var rounds []BatchPlayRound
+	var balance BalanceInfo
+	var rtpBias float64
+	var seed, draw int64
+	err = h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		if active := session.ActiveRound(); active != nil {
+			return newRGSError(ErrCodeInvalidRequest, "round %d is still active: call /wallet/end-round first", active.BetID)
+		}
 
-	// Create weighted sampler - use biased sampler if RTP bias is set
-	var sampleOutcome func() stakergs.Outcome
-	if session.RTPBias != 0 {
-		biasedSampler := lut.NewBiasedWeightedSampler(table, session.RTPBias)
-		sampleOutcome = biasedSampler.SampleWithNewRNG
-	} else {
-		regularSampler := lut.NewWeightedSampler(table)
-		sampleOutcome = regularSampler.SampleWithNewRNG
-	}
+		// Every spin bets the same amount, validated as a single play's would be
+		config, currency, err := h.playConfig(session, req.Currency)
+		if err != nil {
+			return err
+		}
+		if req.Amount == 0 {
+			req.Amount = config.DefaultBetLevel
+		}
+		if err := validateBet(config, betModeFor(config, req.Mode), req.Amount); err != nil {
+			return err
+		}
 
-	// Play all spins
-	keepRounds := req.Spins <= 1000
-	stats, rounds := processBatchSpins(session, sampleOutcome, req.Spins, betPerSpin, req.Amount, keepRounds)
+		// A batch plays many rounds at once, so it is refused where rounds
+		// must be started by hand or paced; otherwise it counts as one round
+		if err := checkBatchJurisdiction(config.Jurisdiction); err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := checkJurisdiction(config.Jurisdiction, isBuyFeatureMode(config, req.Mode), session.LastPlayAt, now); err != nil {
+			return err
+		}
+		rtpBias = session.RTPBias
 
-	h.sessions.Update(session)
+		// Calculate bet per spin
+		betPerSpin := int64(float64(req.Amount) * modeCost)
+		totalBetRequired := betPerSpin * int64(req.Spins)
+
+		// Check balance
+		if balance := session.BalanceIn(currency.Code, currency.startingBalance()); balance < totalBetRequired {
+			return newRGSError(ErrCodeInsufficientBalance, "insufficient balance: need %d, have %d", totalBetRequired, balance)
+		}
+
+		// The batch is accepted: move the session to its currency
+		if err := session.SwitchCurrency(currency.Code, currency.startingBalance()); err != nil {
+			return err
+		}
+		session.LastPlayAt = now
+
+		// Weighted sampler, biased if RTP bias is set.
+		// The whole batch samples from a single draw of the session.
+		var rng *rand.Rand
+		rng, seed, draw = session.NextRand()
+		sampler := h.loader.Sampler(table, session.RTPBias)
+		sampleOutcome := func() stakergs.Outcome { return sampler.Sample(rng) }
+
+		// Play all spins
+		keepRounds := req.Spins <= 1000
+		stats, rounds = processBatchSpins(session, sampleOutcome, req.Spins, betPerSpin, req.Amount, keepRounds,
+			tiers, float64(table.MaxPayout())/100.0)
+
+		balance = BalanceInfo{Amount: session.Balance, Currency: session.Currency}
+		return nil
+	})
+	if err != nil {
+		h.sendRGSError(w, err, http.StatusBadRequest)
+		return
+	}
 
 	// Calculate rates
 	rtp := 0.0
@@ -658,8 +840,8 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 	durationMs := time.Since(start).Milliseconds()
 
 	biasTag := ""
-	if session.RTPBias != 0 {
-		biasTag = fmt.Sprintf(" [BIAS=%.2f]", session.RTPBias)
+	if rtpBias != 0 {
+		biasTag = fmt.Sprintf(" [BIAS=%.2f]", rtpBias)
 	}
 	fmt.Printf("[LGS] BatchPlay: session=%s, mode=%s, spins=%d, rtp=%.4f, duration=%dms%s\n",
 		req.SessionID, req.Mode, req.Spins, rtp, durationMs, biasTag)
@@ -679,87 +861,23 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		MaxWin:       stats.maxWin,
 		BigWins:      stats.bigWins,
 		MegaWins:     stats.megaWins,
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-		Rounds:     rounds,
-		DurationMs: durationMs,
+		WinTiers:     winTierCounts(tiers, stats.tierCounts),
+		Balance:      balance,
+		Rounds:       rounds,
+		Seed:         seed,
+		Draw:         draw,
+		DurationMs:   durationMs,
 	}, http.StatusOK)
 }
 
 // Sessions handles GET /lgs/sessions - returns all active sessions with RTP
 func (h *Handlers) Sessions(w http.ResponseWriter, r *http.Request) {
-	allSessions := h.sessions.GetAll()
-
-	summaries := make([]SessionSummary, 0, len(allSessions))
-
-	// Aggregate stats
-	var aggBets, aggWins, aggWagered, aggWon int64
-
-	for _, s := range allSessions {
-		rtp := 0.0
-		if s.TotalWagered > 0 {
-			rtp = float64(s.TotalWon) / float64(s.TotalWagered)
-		}
-		hitRate := 0.0
-		if s.TotalBets > 0 {
-			hitRate = float64(s.TotalWins) / float64(s.TotalBets)
-		}
-		profit := s.TotalWagered - s.TotalWon
-
-		summaries = append(summaries, SessionSummary{
-			SessionID:      s.SessionID,
-			Balance:        s.Balance,
-			Currency:       s.Currency,
-			TotalBets:      s.TotalBets,
-			TotalWins:      s.TotalWins,
-			TotalWagered:   s.TotalWagered,
-			TotalWon:       s.TotalWon,
-			RTP:            rtp,
-			HitRate:        hitRate,
-			Profit:         profit,
-			HistorySize:    len(s.History),
-			CreatedAt:      s.CreatedAt.Format("2006-01-02 15:04:05"),
-			LastActivity:   s.LastActivity.Format("2006-01-02 15:04:05"),
-			ForcedOutcomes: s.GetAllForcedSimIDs(),
-			RTPBias:        s.RTPBias,
-		})
-
-		// Accumulate for aggregate
-		aggBets += s.TotalBets
-		aggWins += s.TotalWins
-		aggWagered += s.TotalWagered
-		aggWon += s.TotalWon
-	}
-
-	// Calculate aggregate stats
-	overallRTP := 0.0
-	if aggWagered > 0 {
-		overallRTP = float64(aggWon) / float64(aggWagered)
-	}
-	overallHitRate := 0.0
-	if aggBets > 0 {
-		overallHitRate = float64(aggWins) / float64(aggBets)
-	}
+	resp := h.sessionsResponse()
 
 	fmt.Printf("[LGS] Sessions: count=%d, totalBets=%d, overallRTP=%.4f\n",
-		len(allSessions), aggBets, overallRTP)
+		resp.TotalSessions, resp.AggregateStats.TotalBets, resp.AggregateStats.OverallRTP)
 
-	h.sendJSON(w, SessionsResponse{
-		Sessions:      summaries,
-		TotalSessions: len(allSessions),
-		TotalCreated:  h.sessions.TotalCreated(),
-		AggregateStats: AggregateStats{
-			TotalBets:      aggBets,
-			TotalWins:      aggWins,
-			TotalWagered:   aggWagered,
-			TotalWon:       aggWon,
-			OverallRTP:     overallRTP,
-			OverallHitRate: overallHitRate,
-			TotalProfit:    aggWagered - aggWon,
-		},
-	}, http.StatusOK)
+	h.sendJSON(w, resp, http.StatusOK)
 }
 
 // ClearHistory handles DELETE /lgs/history - clears round history
@@ -769,15 +887,11 @@ func (h *Handlers) ClearHistory(w http.ResponseWriter, r *http.Request) {
 		sessionID = "default-session"
 	}
 
-	session := h.sessions.Get(sessionID)
-	if session == nil {
+	if !h.sessions.WithExisting(sessionID, (*SessionData).ClearHistory) {
 		h.sendError(w, "session not found", http.StatusNotFound)
 		return
 	}
 
-	session.ClearHistory()
-	h.sessions.Update(session)
-
 	fmt.Printf("[LGS] Clear History: session=%s\n", sessionID)
 
 	h.sendJSON(w, map[string]interface{}{
@@ -793,15 +907,11 @@ func (h *Handlers) ClearStats(w http.ResponseWriter, r *http.Request) {
 		sessionID = "default-session"
 	}
 
-	session := h.sessions.Get(sessionID)
-	if session == nil {
+	if !h.sessions.WithExisting(sessionID, (*SessionData).ClearStats) {
 		h.sendError(w, "session not found", http.StatusNotFound)
 		return
 	}
 
-	session.ClearStats()
-	h.sessions.Update(session)
-
 	fmt.Printf("[LGS] Clear Stats: session=%s\n", sessionID)
 
 	// Broadcast session update
@@ -854,9 +964,10 @@ func (h *Handlers) ForceOutcome(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session := h.sessions.GetOrCreate(req.SessionID)
-	session.SetForcedSimID(req.Mode, req.SimID)
-	h.sessions.Update(session)
+	h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		session.SetForcedSimID(req.Mode, req.SimID)
+		return nil
+	})
 
 	fmt.Printf("[LGS] Force Outcome: session=%s, mode=%s, simID=%d, payout=%.2fx\n",
 		req.SessionID, req.Mode, req.SimID, payout)
@@ -879,20 +990,19 @@ func (h *Handlers) ClearForcedOutcome(w http.ResponseWriter, r *http.Request) {
 		sessionID = "default-session"
 	}
 
-	session := h.sessions.Get(sessionID)
-	if session == nil {
+	found := h.sessions.WithExisting(sessionID, func(session *SessionData) {
+		if mode != "" {
+			session.ClearForcedSimID(mode)
+		} else {
+			// Clear all forced outcomes
+			session.ForcedSimID = nil
+		}
+	})
+	if !found {
 		h.sendError(w, "session not found", http.StatusNotFound)
 		return
 	}
 
-	if mode != "" {
-		session.ClearForcedSimID(mode)
-	} else {
-		// Clear all forced outcomes
-		session.ForcedSimID = nil
-	}
-	h.sessions.Update(session)
-
 	fmt.Printf("[LGS] Clear Forced Outcome: session=%s, mode=%s\n", sessionID, mode)
 
 	h.sendJSON(w, map[string]interface{}{
@@ -908,18 +1018,14 @@ func (h *Handlers) GetForcedOutcomes(w http.ResponseWriter, r *http.Request) {
 		sessionID = "default-session"
 	}
 
-	session := h.sessions.Get(sessionID)
-	if session == nil {
-		h.sendJSON(w, map[string]interface{}{
-			"sessionID":      sessionID,
-			"forcedOutcomes": map[string]int{},
-		}, http.StatusOK)
-		return
-	}
+	forcedOutcomes := map[string]int{}
+	h.sessions.View(sessionID, func(session *SessionData) {
+		forcedOutcomes = session.GetAllForcedSimIDs()
+	})
 
 	h.sendJSON(w, map[string]interface{}{
 		"sessionID":      sessionID,
-		"forcedOutcomes": session.GetAllForcedSimIDs(),
+		"forcedOutcomes": forcedOutcomes,
 	}, http.StatusOK)
 }
 
@@ -946,9 +1052,10 @@ func (h *Handlers) SetRTPBias(w http.ResponseWriter, r *http.Request) {
 		req.Bias = 2
 	}
 
-	session := h.sessions.GetOrCreate(req.SessionID)
-	session.RTPBias = req.Bias
-	h.sessions.Update(session)
+	h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		session.RTPBias = req.Bias
+		return nil
+	})
 
 	fmt.Printf("[LGS] Set RTP Bias: session=%s, bias=%.2f\n", req.SessionID, req.Bias)
 
@@ -970,21 +1077,140 @@ func (h *Handlers) GetRTPBias(w http.ResponseWriter, r *http.Request) {
 		sessionID = "default-session"
 	}
 
-	session := h.sessions.Get(sessionID)
-	if session == nil {
-		h.sendJSON(w, map[string]interface{}{
-			"sessionID": sessionID,
-			"bias":      0.0,
-		}, http.StatusOK)
+	bias := 0.0
+	h.sessions.View(sessionID, func(session *SessionData) {
+		bias = session.RTPBias
+	})
+
+	h.sendJSON(w, map[string]interface{}{
+		"sessionID": sessionID,
+		"bias":      bias,
+	}, http.StatusOK)
+}
+
+// SetSeed handles POST /lgs/seed - restarts a session's RNG from a seed.
+// A zero seed picks a random one; draw skips ahead to replay a recorded round.
+func (h *Handlers) SetSeed(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string `json:"sessionID"`
+		Seed      int64  `json:"seed"`
+		Draw      int64  `json:"draw"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+	if req.Draw < 0 {
+		h.sendError(w, "draw must be non-negative", http.StatusBadRequest)
 		return
 	}
 
+	var seed int64
+	h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		session.SetSeed(req.Seed, req.Draw)
+		seed = session.Seed
+		return nil
+	})
+
+	fmt.Printf("[LGS] Set Seed: session=%s, seed=%d, draw=%d\n", req.SessionID, seed, req.Draw)
+
+	// Broadcast session update
+	h.broadcastSessionsUpdate()
+
 	h.sendJSON(w, map[string]interface{}{
-		"sessionID": sessionID,
-		"bias":      session.RTPBias,
+		"success":   true,
+		"sessionID": req.SessionID,
+		"seed":      seed,
+		"draw":      req.Draw,
+	}, http.StatusOK)
+}
+
+// Jurisdictions handles GET /lgs/jurisdictions - lists the jurisdiction presets
+func (h *Handlers) Jurisdictions(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, JurisdictionPresets(), http.StatusOK)
+}
+
+// SetJurisdiction handles POST /lgs/jurisdiction - selects a jurisdiction preset for a session.
+// An empty jurisdiction reverts to the game config's flags.
+func (h *Handlers) SetJurisdiction(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID    string `json:"sessionID"`
+		Jurisdiction string `json:"jurisdiction"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+
+	name := ""
+	if req.Jurisdiction != "" {
+		preset, ok := LookupJurisdictionPreset(req.Jurisdiction)
+		if !ok {
+			h.sendError(w, fmt.Sprintf("unknown jurisdiction: %s", req.Jurisdiction), http.StatusBadRequest)
+			return
+		}
+		name = preset.Name
+	}
+
+	h.sessions.WithSession(req.SessionID, func(session *SessionData) error {
+		session.Jurisdiction = name
+		return nil
+	})
+
+	fmt.Printf("[LGS] Set Jurisdiction: session=%s, jurisdiction=%s\n", req.SessionID, name)
+
+	// Broadcast session update
+	h.broadcastSessionsUpdate()
+
+	h.sendJSON(w, map[string]interface{}{
+		"success":      true,
+		"sessionID":    req.SessionID,
+		"name":         name,
+		"jurisdiction": jurisdictionFor(h.config.Get(), name),
 	}, http.StatusOK)
 }
 
+// Currencies handles GET /lgs/currencies - lists the supported currencies with their bet ladders
+func (h *Handlers) Currencies(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, h.config.Currencies(), http.StatusOK)
+}
+
+// GetConfig handles GET /lgs/config - returns the game configuration
+func (h *Handlers) GetConfig(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, h.config.Get(), http.StatusOK)
+}
+
+// SetConfig handles POST /lgs/config - replaces the game configuration.
+// Omitted fields fall back to the defaults derived from the library.
+func (h *Handlers) SetConfig(w http.ResponseWriter, r *http.Request) {
+	var req ConfigInfo
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.config.Set(req)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.applySessionDefaults()
+
+	fmt.Printf("[LGS] Set Config: gameID=%s, currency=%s, bet=%d-%d\n",
+		config.GameID, config.Currency, config.MinBet, config.MaxBet)
+
+	h.sendJSON(w, config, http.StatusOK)
+}
+
 // Replay handles /bet/replay/{game}/{version}/{mode}/{event} - returns event data for replay
 func (h *Handlers) Replay(w http.ResponseWriter, r *http.Request) {
 	game := r.PathValue("game")
@@ -0,0 +1,389 @@
+package lgs
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/lut"
+	"lutexplorer/internal/testutil"
+	"lutexplorer/internal/ws"
+)
+
+// testCSV is a small table with a mix of losses, small wins and one big win.
+const testCSV = `0,600,0
+1,250,50
+2,100,200
+3,45,1000
+4,5,10000
+`
+
+// newTestHandlers returns handlers backed by a one-mode library in a temp dir.
+func newTestHandlers(t *testing.T) *Handlers {
+	t.Helper()
+	return newLibraryHandlers(t, testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: testCSV}))
+}
+
+// newLibraryHandlers returns handlers for an existing library folder.
+func newLibraryHandlers(t *testing.T, dir string) *Handlers {
+	t.Helper()
+
+	loader := lut.NewLoaderFromLibrary(dir)
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	hub := ws.NewHub()
+	go hub.Run()
+
+	return NewHandlers(loader, NewSessionManager(), hub)
+}
+
+// call invokes a handler and decodes its JSON response into out (if non-nil).
+func call(t *testing.T, handler http.HandlerFunc, method, target string, body interface{}, out interface{}) int {
+	t.Helper()
+	rec := record(t, handler, method, target, body)
+	if out != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Errorf("%s %s: %v", method, target, err)
+		}
+	}
+	return rec.Code
+}
+
+// record invokes a handler and returns the raw response.
+func record(t *testing.T, handler http.HandlerFunc, method, target string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Error(err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(method, target, &buf))
+	return rec
+}
+
+// roundActive reports whether a rejected play was refused because a round is in progress.
+func roundActive(rec *httptest.ResponseRecorder) bool {
+	return rec.Code == http.StatusBadRequest && strings.Contains(rec.Body.String(), "still active")
+}
+
+func TestHandlers_RoundLifecycle(t *testing.T) {
+	h := newTestHandlers(t)
+	call(t, h.ForceOutcome, http.MethodPost, "/lgs/force-outcome",
+		map[string]interface{}{"sessionID": "qa", "mode": "base", "simID": 4}, nil)
+
+	var play PlayResponse
+	if code := call(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"}, &play); code != http.StatusOK {
+		t.Fatalf("play: status %d", code)
+	}
+	if !play.Round.Active || play.Round.Payout != 100*common.DefaultBetLevel {
+		t.Fatalf("round: got active=%v payout=%d, want an active 100x win", play.Round.Active, play.Round.Payout)
+	}
+	if want := int64(DefaultBalance - common.DefaultBetLevel); play.Balance.Amount != want {
+		t.Errorf("balance after play: got %d, want %d (payout must wait for end-round)", play.Balance.Amount, want)
+	}
+
+	// A second play is refused until the round is ended
+	if rec := record(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"}); !roundActive(rec) {
+		t.Errorf("second play: got %d %s, want round-active rejection", rec.Code, rec.Body.String())
+	}
+	if rec := record(t, h.BatchPlay, http.MethodPost, "/lgs/batchplay", BatchPlayRequest{SessionID: "qa", Mode: "base"}); !roundActive(rec) {
+		t.Errorf("batch play: got %d %s, want round-active rejection", rec.Code, rec.Body.String())
+	}
+
+	if code := call(t, h.Event, http.MethodPost, "/bet/event", EventRequest{SessionID: "qa", Event: "3"}, nil); code != http.StatusOK {
+		t.Errorf("event: status %d", code)
+	}
+
+	// Reconnecting resumes the round at the last reported event
+	var auth struct {
+		Balance BalanceInfo `json:"balance"`
+		Round   *RoundInfo  `json:"round"`
+	}
+	call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate", AuthRequest{SessionID: "qa"}, &auth)
+	if auth.Round == nil || auth.Round.BetID != play.Round.BetID || auth.Round.Event != "3" {
+		t.Fatalf("authenticate: got round %+v, want active round %d at event 3", auth.Round, play.Round.BetID)
+	}
+
+	var end EndRoundResponse
+	call(t, h.EndRound, http.MethodPost, "/wallet/end-round", EndRoundRequest{SessionID: "qa"}, &end)
+	if want := int64(DefaultBalance - common.DefaultBetLevel + 100*common.DefaultBetLevel); end.Balance.Amount != want {
+		t.Errorf("balance after end-round: got %d, want %d", end.Balance.Amount, want)
+	}
+
+	auth.Round = nil
+	call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate", AuthRequest{SessionID: "qa"}, &auth)
+	if auth.Round != nil {
+		t.Errorf("authenticate after end-round: got round %+v, want none", auth.Round)
+	}
+	if code := call(t, h.Event, http.MethodPost, "/bet/event", EventRequest{SessionID: "qa", Event: "4"}, nil); code != http.StatusBadRequest {
+		t.Errorf("event without active round: got status %d, want 400", code)
+	}
+
+	h.sessions.View("qa", func(s *SessionData) {
+		if last := s.History[len(s.History)-1]; last.Active || last.Event != "3" {
+			t.Errorf("history entry not settled: %+v", last)
+		}
+	})
+
+	// Losing rounds close immediately
+	call(t, h.ForceOutcome, http.MethodPost, "/lgs/force-outcome",
+		map[string]interface{}{"sessionID": "qa", "mode": "base", "simID": 0}, nil)
+	call(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"}, &play)
+	if play.Round.Active {
+		t.Error("zero-payout round should not stay active")
+	}
+}
+
+func TestHandlers_PlayValidatesBets(t *testing.T) {
+	h := newTestHandlers(t)
+
+	tests := []struct {
+		name  string
+		req   PlayRequest
+		code  string
+		broke bool
+	}{
+		{"below min bet", PlayRequest{Mode: "base", Amount: common.MinBet - common.StepBet/2}, ErrCodeInvalidRequest, false},
+		{"above max bet", PlayRequest{Mode: "base", Amount: common.MaxBet + common.StepBet}, ErrCodeInvalidRequest, false},
+		{"negative", PlayRequest{Mode: "base", Amount: -common.DefaultBetLevel}, ErrCodeInvalidRequest, false},
+		{"off step", PlayRequest{Mode: "base", Amount: common.DefaultBetLevel + 1}, ErrCodeInvalidRequest, false},
+		{"not a bet level", PlayRequest{Mode: "base", Amount: 300000}, ErrCodeInvalidRequest, false},
+		{"missing mode", PlayRequest{}, ErrCodeInvalidRequest, false},
+		{"unknown mode", PlayRequest{Mode: "missing"}, ErrCodeUnknownMode, false},
+		{"insufficient balance", PlayRequest{Mode: "base"}, ErrCodeInsufficientBalance, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.SessionID = "qa-" + tt.name
+			if tt.broke {
+				call(t, h.SetBalance, http.MethodPost, "/lgs/set-balance",
+					map[string]interface{}{"sessionID": tt.req.SessionID, "balance": 0}, nil)
+			}
+
+			rec := record(t, h.Play, http.MethodPost, "/wallet/play", tt.req)
+			var resp ErrorResponse
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			if rec.Code != http.StatusBadRequest || resp.Code != tt.code {
+				t.Errorf("got %d %q (%s), want 400 %q", rec.Code, resp.Code, resp.Error, tt.code)
+			}
+		})
+	}
+
+	// Default and listed bet levels are accepted
+	for _, amount := range []int64{0, common.MinBet, common.MaxBet} {
+		var play PlayResponse
+		req := PlayRequest{SessionID: "qa-valid", Mode: "base", Amount: amount}
+		if code := call(t, h.Play, http.MethodPost, "/wallet/play", req, &play); code != http.StatusOK {
+			t.Errorf("amount %d: status %d", amount, code)
+		}
+		call(t, h.EndRound, http.MethodPost, "/wallet/end-round", EndRoundRequest{SessionID: "qa-valid"}, nil)
+	}
+}
+
+func TestHandlers_BatchPlayValidatesBets(t *testing.T) {
+	h := newTestHandlers(t)
+
+	for _, amount := range []int64{-common.DefaultBetLevel, common.MaxBet + common.StepBet, common.DefaultBetLevel + 1, 300000} {
+		req := BatchPlayRequest{SessionID: "qa", Mode: "base", Amount: amount, Spins: 10}
+		rec := record(t, h.BatchPlay, http.MethodPost, "/lgs/batchplay", req)
+		var resp ErrorResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != http.StatusBadRequest || resp.Code != ErrCodeInvalidRequest {
+			t.Errorf("amount %d: got %d %q (%s), want 400 %q", amount, rec.Code, resp.Code, resp.Error, ErrCodeInvalidRequest)
+		}
+	}
+
+	rec := record(t, h.BatchPlay, http.MethodPost, "/lgs/batchplay", BatchPlayRequest{SessionID: "qa", Mode: "missing", Spins: 10})
+	var resp ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if rec.Code != http.StatusBadRequest || resp.Code != ErrCodeUnknownMode {
+		t.Errorf("unknown mode: got %d %q (%s), want 400 %q", rec.Code, resp.Code, resp.Error, ErrCodeUnknownMode)
+	}
+
+	req := BatchPlayRequest{SessionID: "qa", Mode: "base", Amount: common.MinBet, Spins: 10}
+	if rec := record(t, h.BatchPlay, http.MethodPost, "/lgs/batchplay", req); rec.Code != http.StatusOK {
+		t.Errorf("amount %d: status %d: %s", common.MinBet, rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlers_ConcurrentPlayKeepsBalanceConsistent(t *testing.T) {
+	h := newTestHandlers(t)
+	if err := h.sessions.UseStore(NewFileSessionStore(filepath.Join(t.TempDir(), SessionsFile))); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		players       = 8
+		playsEach     = 25
+		batches       = 4
+		spinsPerBatch = 200
+	)
+
+	// Plays and batches racing another player's open round are refused,
+	// so count what was accepted
+	var played, batched atomic.Int64
+
+	var wg sync.WaitGroup
+	for i := 0; i < players; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < playsEach; j++ {
+				rec := record(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"})
+				switch {
+				case rec.Code == http.StatusOK:
+					played.Add(1)
+				case !roundActive(rec):
+					t.Errorf("play: status %d: %s", rec.Code, rec.Body.String())
+					return
+				}
+				call(t, h.EndRound, http.MethodPost, "/wallet/end-round", EndRoundRequest{SessionID: "qa"}, nil)
+			}
+		}()
+	}
+	for i := 0; i < batches; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := BatchPlayRequest{SessionID: "qa", Mode: "base", Spins: spinsPerBatch}
+			rec := record(t, h.BatchPlay, http.MethodPost, "/lgs/batchplay", req)
+			switch {
+			case rec.Code == http.StatusOK:
+				batched.Add(1)
+			case !roundActive(rec):
+				t.Errorf("batch play: status %d: %s", rec.Code, rec.Body.String())
+			}
+		}()
+	}
+
+	// Readers and control-panel actions racing the plays
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 20; j++ {
+			call(t, h.Sessions, http.MethodGet, "/lgs/sessions", nil, nil)
+			call(t, h.Stats, http.MethodGet, "/lgs/stats?sessionID=qa", nil, nil)
+			call(t, h.History, http.MethodPost, "/lgs/history", HistoryRequest{SessionID: "qa", Limit: 10}, nil)
+			call(t, h.ForceOutcome, http.MethodPost, "/lgs/force-outcome",
+				map[string]interface{}{"sessionID": "qa", "mode": "base", "simID": 4}, nil)
+			call(t, h.GetForcedOutcomes, http.MethodGet, "/lgs/force-outcome?sessionID=qa", nil, nil)
+			if err := h.sessions.Flush(); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	// Settle the round left open by the last winning play, if any
+	call(t, h.EndRound, http.MethodPost, "/wallet/end-round", EndRoundRequest{SessionID: "qa"}, nil)
+
+	if err := h.sessions.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	h.sessions.View("qa", func(s *SessionData) {
+		wantBets := played.Load() + batched.Load()*spinsPerBatch
+		if s.TotalBets != wantBets {
+			t.Errorf("total bets: got %d, want %d", s.TotalBets, wantBets)
+		}
+		if want := DefaultBalance - s.TotalWagered + s.TotalWon; s.Balance != want {
+			t.Errorf("balance: got %d, want %d (wagered %d, won %d)", s.Balance, want, s.TotalWagered, s.TotalWon)
+		}
+	})
+}
+
+func TestHandlers_ConcurrentSetBalanceNeverOverdraws(t *testing.T) {
+	h := newTestHandlers(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				rec := record(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"})
+				if rec.Code != http.StatusOK && !roundActive(rec) && !strings.Contains(rec.Body.String(), "insufficient balance") {
+					t.Errorf("play: status %d: %s", rec.Code, rec.Body.String())
+				}
+				call(t, h.EndRound, http.MethodPost, "/wallet/end-round", EndRoundRequest{SessionID: "qa"}, nil)
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				// Alternate between an empty wallet and room for a few bets
+				balance := int64((i+j)%2) * 3 * common.DefaultBetLevel
+				call(t, h.SetBalance, http.MethodPost, "/lgs/set-balance",
+					map[string]interface{}{"sessionID": "qa", "balance": balance}, nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	h.sessions.View("qa", func(s *SessionData) {
+		if s.Balance < 0 {
+			t.Errorf("balance went negative: %d", s.Balance)
+		}
+	})
+}
+
+func TestHandlers_SessionSeedReplaysOutcomes(t *testing.T) {
+	h := newTestHandlers(t)
+
+	playRounds := func(n int) []RoundInfo {
+		t.Helper()
+		rounds := make([]RoundInfo, n)
+		for i := range rounds {
+			var play PlayResponse
+			if code := call(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"}, &play); code != http.StatusOK {
+				t.Fatalf("play %d: status %d", i, code)
+			}
+			call(t, h.EndRound, http.MethodPost, "/wallet/end-round", EndRoundRequest{SessionID: "qa"}, nil)
+			rounds[i] = play.Round
+		}
+		return rounds
+	}
+	setSeed := func(seed, draw int64) {
+		t.Helper()
+		body := map[string]interface{}{"sessionID": "qa", "seed": seed, "draw": draw}
+		if code := call(t, h.SetSeed, http.MethodPost, "/lgs/seed", body, nil); code != http.StatusOK {
+			t.Fatalf("set seed: status %d", code)
+		}
+	}
+
+	setSeed(42, 0)
+	first := playRounds(30)
+	for i, round := range first {
+		if round.Seed != 42 || round.Draw != int64(i) {
+			t.Fatalf("round %d: seed %d draw %d, want 42 and %d", i, round.Seed, round.Draw, i)
+		}
+	}
+
+	setSeed(42, 0)
+	replay := playRounds(30)
+	for i := range first {
+		if replay[i].BetID != first[i].BetID || replay[i].Payout != first[i].Payout {
+			t.Fatalf("round %d: replay gave simID %d, want %d", i, replay[i].BetID, first[i].BetID)
+		}
+	}
+
+	// Skipping ahead replays a single recorded round
+	setSeed(42, 17)
+	if single := playRounds(1)[0]; single.BetID != first[17].BetID || single.Draw != 17 {
+		t.Errorf("draw 17: got simID %d (draw %d), want %d", single.BetID, single.Draw, first[17].BetID)
+	}
+}
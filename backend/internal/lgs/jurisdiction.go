@@ -0,0 +1,139 @@
+package lgs
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// BuyFeatureMinCost is the mode cost from which a mode counts as a bonus buy
+// when the game config does not list its buy-feature modes explicitly.
+const BuyFeatureMinCost = 10.0
+
+// JurisdictionPreset is a named set of jurisdiction flags a session can simulate.
+type JurisdictionPreset struct {
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	Jurisdiction JurisdictionInfo `json:"jurisdiction"`
+}
+
+// jurisdictionPresets are the built-in presets, keyed by lowercase name.
+// MinimumRoundDuration is in milliseconds.
+var jurisdictionPresets = map[string]JurisdictionPreset{
+	"unrestricted": {
+		Name:        "unrestricted",
+		Description: "No restrictions",
+	},
+	"social": {
+		Name:        "social",
+		Description: "Social casino: play-money wording, no buy feature",
+		Jurisdiction: JurisdictionInfo{
+			SocialCasino:       true,
+			DisabledBuyFeature: true,
+		},
+	},
+	"uk": {
+		Name:        "uk",
+		Description: "Great Britain: no autoplay, turbo, slam stop or buy feature, 2.5s minimum round",
+		Jurisdiction: JurisdictionInfo{
+			DisabledTurbo:        true,
+			DisabledSuperTurbo:   true,
+			DisabledAutoplay:     true,
+			DisabledSlamstop:     true,
+			DisabledSpacebar:     true,
+			DisabledBuyFeature:   true,
+			DisplayNetPosition:   true,
+			DisplayRTP:           true,
+			DisplaySessionTimer:  true,
+			MinimumRoundDuration: 2500,
+		},
+	},
+	"germany": {
+		Name:        "germany",
+		Description: "Germany: no autoplay, turbo or buy feature, 5s minimum round",
+		Jurisdiction: JurisdictionInfo{
+			DisabledTurbo:        true,
+			DisabledSuperTurbo:   true,
+			DisabledAutoplay:     true,
+			DisabledSlamstop:     true,
+			DisabledBuyFeature:   true,
+			DisplaySessionTimer:  true,
+			MinimumRoundDuration: 5000,
+		},
+	},
+	"sweden": {
+		Name:        "sweden",
+		Description: "Sweden: net position and session timer shown, no buy feature",
+		Jurisdiction: JurisdictionInfo{
+			DisabledBuyFeature:  true,
+			DisplayNetPosition:  true,
+			DisplaySessionTimer: true,
+		},
+	},
+}
+
+// JurisdictionPresets returns the built-in presets sorted by name.
+func JurisdictionPresets() []JurisdictionPreset {
+	presets := make([]JurisdictionPreset, 0, len(jurisdictionPresets))
+	for _, preset := range jurisdictionPresets {
+		presets = append(presets, preset)
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+	return presets
+}
+
+// LookupJurisdictionPreset returns a preset by name, ignoring case.
+func LookupJurisdictionPreset(name string) (JurisdictionPreset, bool) {
+	preset, ok := jurisdictionPresets[strings.ToLower(name)]
+	return preset, ok
+}
+
+// jurisdictionFor returns the flags in force for a session: its preset if one
+// is selected, otherwise the game config's jurisdiction.
+func jurisdictionFor(config ConfigInfo, preset string) JurisdictionInfo {
+	if p, ok := LookupJurisdictionPreset(preset); ok {
+		return p.Jurisdiction
+	}
+	return config.Jurisdiction
+}
+
+// isBuyFeatureMode reports whether the config lists a mode as a bonus buy.
+func isBuyFeatureMode(config ConfigInfo, mode string) bool {
+	for _, m := range config.BuyFeatureModes {
+		if strings.EqualFold(m, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkJurisdiction enforces the server-side jurisdiction rules for a play.
+// lastPlay is when the session's previous round started (zero if none).
+func checkJurisdiction(j JurisdictionInfo, buyFeature bool, lastPlay, now time.Time) error {
+	if j.DisabledBuyFeature && buyFeature {
+		return newRGSError(ErrCodeInvalidRequest, "buy feature is disabled in this jurisdiction")
+	}
+	if j.MinimumRoundDuration > 0 && !lastPlay.IsZero() {
+		minDuration := time.Duration(j.MinimumRoundDuration) * time.Millisecond
+		if elapsed := now.Sub(lastPlay); elapsed < minDuration {
+			return newRGSError(ErrCodeInvalidRequest, "round started %dms after the previous one, minimum round duration is %dms",
+				elapsed.Milliseconds(), j.MinimumRoundDuration)
+		}
+	}
+	return nil
+}
+
+// checkBatchJurisdiction refuses batch play where autoplay is disabled or a
+// minimum round duration applies, since a batch plays many rounds at once.
+func checkBatchJurisdiction(j JurisdictionInfo) error {
+	if j.DisabledAutoplay {
+		return newRGSError(ErrCodeInvalidRequest, "batch play is disabled in this jurisdiction: autoplay is not allowed")
+	}
+	if j.MinimumRoundDuration > 0 {
+		return newRGSError(ErrCodeInvalidRequest, "batch play is disabled in this jurisdiction: minimum round duration is %dms",
+			j.MinimumRoundDuration)
+	}
+	return nil
+}
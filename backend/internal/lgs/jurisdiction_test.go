@@ -0,0 +1,143 @@
+package lgs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lutexplorer/internal/testutil"
+)
+
+// newBuyFeatureHandlers serves a library with a base mode and a bonus buy
+// mode, with a funded "qa" session.
+func newBuyFeatureHandlers(t *testing.T) *Handlers {
+	t.Helper()
+	h := newLibraryHandlers(t, testutil.WriteLibrary(t,
+		testutil.Mode{Name: "base", CSV: testCSV},
+		testutil.Mode{Name: "bonus", Cost: 100, CSV: testCSV},
+	))
+	call(t, h.SetBalance, http.MethodPost, "/lgs/set-balance", map[string]interface{}{"sessionID": "qa", "balance": DefaultBalance}, nil)
+	return h
+}
+
+func TestHandlers_JurisdictionPresetsAreEnforced(t *testing.T) {
+	h := newBuyFeatureHandlers(t)
+
+	if code := call(t, h.SetJurisdiction, http.MethodPost, "/lgs/jurisdiction",
+		map[string]string{"sessionID": "qa", "jurisdiction": "atlantis"}, nil); code != http.StatusBadRequest {
+		t.Errorf("unknown jurisdiction: got status %d, want 400", code)
+	}
+	call(t, h.SetJurisdiction, http.MethodPost, "/lgs/jurisdiction", map[string]string{"sessionID": "qa", "jurisdiction": "UK"}, nil)
+
+	var auth AuthResponse
+	call(t, h.Authenticate, http.MethodPost, "/wallet/authenticate", AuthRequest{SessionID: "qa"}, &auth)
+	if !auth.Config.Jurisdiction.DisabledAutoplay || auth.Config.Jurisdiction.MinimumRoundDuration != 2500 {
+		t.Errorf("authenticate config: got %+v, want the uk preset", auth.Config.Jurisdiction)
+	}
+
+	play := func(mode string) *ErrorResponse {
+		t.Helper()
+		// Force a losing outcome so the round closes and the next play is not blocked by it
+		call(t, h.ForceOutcome, http.MethodPost, "/lgs/force-outcome",
+			map[string]interface{}{"sessionID": "qa", "mode": mode, "simID": 0}, nil)
+		rec := record(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: mode})
+		if rec.Code == http.StatusOK {
+			return nil
+		}
+		return &ErrorResponse{Error: rec.Body.String()}
+	}
+
+	if err := play("bonus"); err == nil || !strings.Contains(err.Error, "buy feature") {
+		t.Errorf("bonus buy under uk: got %v, want buy feature rejection", err)
+	}
+	if err := play("base"); err != nil {
+		t.Fatalf("first base play: %s", err.Error)
+	}
+	if err := play("base"); err == nil || !strings.Contains(err.Error, "minimum round duration") {
+		t.Errorf("immediate second play: got %v, want minimum round duration rejection", err)
+	}
+
+	// Once the minimum duration has passed the next round is accepted
+	h.sessions.WithSession("qa", func(s *SessionData) error {
+		s.LastPlayAt = s.LastPlayAt.Add(-3 * time.Second)
+		return nil
+	})
+	if err := play("base"); err != nil {
+		t.Errorf("play after minimum duration: %s", err.Error)
+	}
+
+	// Clearing the preset falls back to the unrestricted game config
+	call(t, h.SetJurisdiction, http.MethodPost, "/lgs/jurisdiction", map[string]string{"sessionID": "qa"}, nil)
+	if err := play("bonus"); err != nil {
+		t.Errorf("bonus buy without jurisdiction: %s", err.Error)
+	}
+}
+
+func TestHandlers_JurisdictionAppliesToBatchPlay(t *testing.T) {
+	h := newBuyFeatureHandlers(t)
+
+	batch := func(mode string) *httptest.ResponseRecorder {
+		t.Helper()
+		return record(t, h.BatchPlay, http.MethodPost, "/lgs/batchplay", BatchPlayRequest{SessionID: "qa", Mode: mode, Spins: 10})
+	}
+	setPreset := func(name string) {
+		t.Helper()
+		call(t, h.SetJurisdiction, http.MethodPost, "/lgs/jurisdiction", map[string]string{"sessionID": "qa", "jurisdiction": name}, nil)
+	}
+
+	// Presets without autoplay or with a minimum round duration refuse batches outright
+	for _, preset := range []string{"uk", "germany"} {
+		setPreset(preset)
+		for _, mode := range []string{"base", "bonus"} {
+			rec := batch(mode)
+			if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "batch play is disabled") ||
+				!strings.Contains(rec.Body.String(), ErrCodeInvalidRequest) {
+				t.Errorf("%s batch under %s: got %d %s, want ERR_VAL batch play rejection", mode, preset, rec.Code, rec.Body.String())
+			}
+		}
+	}
+	h.sessions.View("qa", func(s *SessionData) {
+		if s.Balance != DefaultBalance || s.TotalBets != 0 || !s.LastPlayAt.IsZero() {
+			t.Errorf("rejected batches changed the session: balance %d, bets %d", s.Balance, s.TotalBets)
+		}
+	})
+
+	// Other rules still apply to a batch as to a single round
+	setPreset("sweden")
+	if rec := batch("bonus"); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "buy feature") {
+		t.Errorf("bonus batch under sweden: got %d %s, want buy feature rejection", rec.Code, rec.Body.String())
+	}
+	if rec := batch("base"); rec.Code != http.StatusOK {
+		t.Errorf("base batch under sweden: got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlers_StaleForcedOutcomeIsDropped(t *testing.T) {
+	h := newBuyFeatureHandlers(t)
+	call(t, h.SetJurisdiction, http.MethodPost, "/lgs/jurisdiction", map[string]string{"sessionID": "qa", "jurisdiction": "UK"}, nil)
+
+	// A forced simID missing from the table, as after a reload, fails one play
+	h.sessions.WithSession("qa", func(s *SessionData) error {
+		s.SetForcedSimID("base", 999)
+		return nil
+	})
+	if rec := record(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("play with missing forced simID: got %d, want 400", rec.Code)
+	}
+
+	h.sessions.View("qa", func(s *SessionData) {
+		if !s.LastPlayAt.IsZero() {
+			t.Error("rejected play started the minimum round duration timer")
+		}
+		if simID, ok := s.GetForcedSimID("base"); ok {
+			t.Errorf("stale forced simID %d was kept", simID)
+		}
+	})
+
+	// The next play samples normally instead of failing on the same simID
+	if rec := record(t, h.Play, http.MethodPost, "/wallet/play", PlayRequest{SessionID: "qa", Mode: "base"}); rec.Code != http.StatusOK {
+		t.Errorf("play after stale forced simID: got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -1,10 +1,14 @@
 package lgs
 
 import (
+	"fmt"
+	"math/rand"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"lutexplorer/internal/common"
 )
 
 // DefaultBalance is the initial balance ($1,000,000 in API units where 1000000 = $1)
@@ -13,27 +17,192 @@ const DefaultBalance = 1000000000000
 // MaxHistorySize is the maximum number of rounds to keep in history
 const MaxHistorySize = 100
 
-// SessionData stores session information
+// SessionData stores session information.
+//
+// Handlers run concurrently, so every field is guarded by the session lock.
+// Use SessionManager.WithSession, WithExisting or View rather than locking
+// directly; the SessionData methods below assume the caller holds the lock.
 type SessionData struct {
-	SessionID    string
-	Balance      int64
-	Currency     string
-	Language     string
-	LastRound    *RoundInfo
-	History      []RoundInfo
-	BetIDCounter int64
-	CreatedAt    time.Time
-	LastActivity time.Time
-	TotalBets    int64
-	TotalWins    int64
-	TotalWagered int64
-	TotalWon     int64
+	mu sync.Mutex
+
+	SessionID string `json:"sessionID"`
+	Balance   int64  `json:"balance"`
+	Currency  string `json:"currency"`
+	// Balances holds the balances of currencies used before; Balance is the balance in Currency
+	Balances     map[string]int64 `json:"balances,omitempty"`
+	Language     string           `json:"language"`
+	LastRound    *RoundInfo       `json:"lastRound,omitempty"`
+	History      []RoundInfo      `json:"history"`
+	BetIDCounter int64            `json:"betIDCounter"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	LastActivity time.Time        `json:"lastActivity"`
+	TotalBets    int64            `json:"totalBets"`
+	TotalWins    int64            `json:"totalWins"`
+	TotalWagered int64            `json:"totalWagered"`
+	TotalWon     int64            `json:"totalWon"`
 	// ForcedSimID maps mode -> simID for forcing specific outcomes
-	ForcedSimID map[string]int
+	ForcedSimID map[string]int `json:"forcedSimID,omitempty"`
 	// RTPBias is an exponent that biases sampling toward higher payouts.
 	// 0.0 = normal RTP, positive values boost high payouts (e.g., 0.5 = moderate boost, 1.0 = strong boost)
 	// The weight for each outcome is multiplied by payout^RTPBias
-	RTPBias float64
+	RTPBias float64 `json:"rtpBias"`
+	// Jurisdiction is the selected jurisdiction preset; empty uses the game config's flags
+	Jurisdiction string `json:"jurisdiction,omitempty"`
+	// LastPlayAt is when the last round started, for the minimum round duration
+	LastPlayAt time.Time `json:"lastPlayAt"`
+	// Seed drives all sampling for the session. Draw n uses stream n of the seed,
+	// so replaying the same requests after resetting the seed repeats every outcome.
+	Seed  int64 `json:"seed"`
+	Draws int64 `json:"draws"` // Number of streams used so far
+}
+
+// Lock acquires the session lock.
+func (s *SessionData) Lock() {
+	s.mu.Lock()
+}
+
+// TryLock acquires the session lock if it is free and reports whether it did.
+func (s *SessionData) TryLock() bool {
+	return s.mu.TryLock()
+}
+
+// Unlock releases the session lock.
+func (s *SessionData) Unlock() {
+	s.mu.Unlock()
+}
+
+// clone returns a copy of the session that shares no mutable state with it.
+// The caller must hold the session lock.
+func (s *SessionData) clone() *SessionData {
+	c := &SessionData{
+		SessionID:    s.SessionID,
+		Balance:      s.Balance,
+		Currency:     s.Currency,
+		Language:     s.Language,
+		History:      append(make([]RoundInfo, 0, len(s.History)), s.History...),
+		BetIDCounter: s.BetIDCounter,
+		CreatedAt:    s.CreatedAt,
+		LastActivity: s.LastActivity,
+		TotalBets:    s.TotalBets,
+		TotalWins:    s.TotalWins,
+		TotalWagered: s.TotalWagered,
+		TotalWon:     s.TotalWon,
+		RTPBias:      s.RTPBias,
+		Balances:     s.AllBalances(),
+		Jurisdiction: s.Jurisdiction,
+		LastPlayAt:   s.LastPlayAt,
+		Seed:         s.Seed,
+		Draws:        s.Draws,
+	}
+	if s.LastRound != nil {
+		lastRound := *s.LastRound
+		c.LastRound = &lastRound
+	}
+	if s.ForcedSimID != nil {
+		c.ForcedSimID = s.GetAllForcedSimIDs()
+	}
+	return c
+}
+
+// summary builds the session listing entry. The caller must hold the session lock.
+func (s *SessionData) summary() SessionSummary {
+	rtp := 0.0
+	if s.TotalWagered > 0 {
+		rtp = float64(s.TotalWon) / float64(s.TotalWagered)
+	}
+	hitRate := 0.0
+	if s.TotalBets > 0 {
+		hitRate = float64(s.TotalWins) / float64(s.TotalBets)
+	}
+
+	return SessionSummary{
+		SessionID:      s.SessionID,
+		Balance:        s.Balance,
+		Currency:       s.Currency,
+		Balances:       s.AllBalances(),
+		TotalBets:      s.TotalBets,
+		TotalWins:      s.TotalWins,
+		TotalWagered:   s.TotalWagered,
+		TotalWon:       s.TotalWon,
+		RTP:            rtp,
+		HitRate:        hitRate,
+		Profit:         s.TotalWagered - s.TotalWon,
+		HistorySize:    len(s.History),
+		CreatedAt:      s.CreatedAt.Format("2006-01-02 15:04:05"),
+		LastActivity:   s.LastActivity.Format("2006-01-02 15:04:05"),
+		ForcedOutcomes: s.GetAllForcedSimIDs(),
+		RTPBias:        s.RTPBias,
+		Jurisdiction:   s.Jurisdiction,
+		Seed:           s.Seed,
+		Draws:          s.Draws,
+	}
+}
+
+// NextRand returns the generator for the session's next draw along with the
+// seed and draw index that reproduce it. The caller must hold the session lock.
+func (s *SessionData) NextRand() (rng *rand.Rand, seed, draw int64) {
+	if s.Seed == 0 {
+		// Sessions restored from before seeds existed
+		s.Seed = common.NewSeed()
+	}
+	draw = s.Draws
+	s.Draws++
+	return common.NewRand(s.Seed, draw), s.Seed, draw
+}
+
+// SetSeed restarts the session's draws from a seed; 0 picks a random seed.
+// A draw index other than 0 skips ahead, to replay a single recorded round.
+func (s *SessionData) SetSeed(seed, draw int64) {
+	if seed == 0 {
+		seed = common.NewSeed()
+	}
+	s.Seed = seed
+	s.Draws = draw
+}
+
+// AllBalances returns the balance of every currency used, including the current one.
+func (s *SessionData) AllBalances() map[string]int64 {
+	balances := make(map[string]int64, len(s.Balances)+1)
+	for code, balance := range s.Balances {
+		balances[code] = balance
+	}
+	balances[s.Currency] = s.Balance
+	return balances
+}
+
+// BalanceIn returns the balance the session holds in code, or startingBalance
+// for a currency it has not used yet. The session is not changed.
+func (s *SessionData) BalanceIn(code string, startingBalance int64) int64 {
+	if code == s.Currency {
+		return s.Balance
+	}
+	if balance, ok := s.Balances[code]; ok {
+		return balance
+	}
+	return startingBalance
+}
+
+// SwitchCurrency makes code the current currency, keeping the balance of the
+// previous one. A currency used for the first time starts at startingBalance.
+// Fails while a round is active, since its payout is owed in the current currency.
+func (s *SessionData) SwitchCurrency(code string, startingBalance int64) error {
+	if code == s.Currency {
+		return nil
+	}
+	if s.ActiveRound() != nil {
+		return newRGSError(ErrCodeInvalidRequest, "cannot change currency while a round is active")
+	}
+
+	if s.Balances == nil {
+		s.Balances = make(map[string]int64)
+	}
+	balance := s.BalanceIn(code, startingBalance)
+	s.Balances[s.Currency] = s.Balance
+	delete(s.Balances, code)
+
+	s.Currency = code
+	s.Balance = balance
+	return nil
 }
 
 // NextBetID returns the simID as the bet ID
@@ -42,7 +211,8 @@ func (s *SessionData) NextBetID(simID int) int {
 	return simID
 }
 
-// AddRound adds a round to history
+// AddRound adds a round to history. Statistics count the payout as soon as
+// the round is played; the balance is only credited by SettleRound.
 func (s *SessionData) AddRound(round RoundInfo) {
 	s.History = append(s.History, round)
 	if len(s.History) > MaxHistorySize {
@@ -57,6 +227,47 @@ func (s *SessionData) AddRound(round RoundInfo) {
 	}
 }
 
+// ActiveRound returns a copy of the round awaiting end-round, or nil if none.
+func (s *SessionData) ActiveRound() *RoundInfo {
+	if s.LastRound == nil || !s.LastRound.Active {
+		return nil
+	}
+	round := *s.LastRound
+	return &round
+}
+
+// SetRoundEvent records the last event the game reported for the active round.
+// Returns false if no round is active.
+func (s *SessionData) SetRoundEvent(event string) bool {
+	if s.LastRound == nil || !s.LastRound.Active {
+		return false
+	}
+	s.LastRound.Event = event
+	s.syncLastRound()
+	return true
+}
+
+// SettleRound credits the active round's payout and closes it.
+// Returns the settled round, or nil if no round is active.
+func (s *SessionData) SettleRound() *RoundInfo {
+	if s.LastRound == nil || !s.LastRound.Active {
+		return nil
+	}
+	s.Balance += s.LastRound.Payout
+	s.LastRound.Active = false
+	s.syncLastRound()
+	round := *s.LastRound
+	return &round
+}
+
+// syncLastRound copies LastRound over its history entry, which is the most
+// recent one unless history was cleared while the round was in progress.
+func (s *SessionData) syncLastRound() {
+	if n := len(s.History); n > 0 && s.History[n-1].BetID == s.LastRound.BetID {
+		s.History[n-1] = *s.LastRound
+	}
+}
+
 // GetStats returns session statistics
 func (s *SessionData) GetStats() map[string]interface{} {
 	hitRate := 0.0
@@ -142,42 +353,152 @@ func (s *SessionData) GetAllForcedSimIDs() map[string]int {
 	return result
 }
 
-// SessionManager manages player sessions
+// SessionManager manages player sessions.
+//
+// Lock order is manager lock, then session lock. Code holding a session lock
+// must not call back into the manager.
 type SessionManager struct {
 	sessions map[string]*SessionData
 	mu       sync.RWMutex
 	counter  atomic.Int64
+
+	// Currency and balance of new sessions
+	defaultCurrency string
+	defaultBalance  int64
+
+	// Optional persistence; changes are flushed in the background
+	store     SessionStore
+	dirty     chan struct{}
+	stopFlush chan struct{}
+	flushDone chan struct{}
 }
 
-// NewSessionManager creates a new session manager
+// NewSessionManager creates a new in-memory session manager
 func NewSessionManager() *SessionManager {
 	return &SessionManager{
-		sessions: make(map[string]*SessionData),
+		sessions:        make(map[string]*SessionData),
+		defaultCurrency: DefaultCurrency,
+		defaultBalance:  DefaultBalance,
+	}
+}
+
+// SetDefaults sets the currency and starting balance of sessions created from now on.
+func (sm *SessionManager) SetDefaults(currency string, balance int64) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.defaultCurrency = currency
+	sm.defaultBalance = balance
+}
+
+// UseStore restores sessions from store and persists all later changes to it.
+// Restored sessions replace in-memory sessions with the same ID.
+// Must be called before the manager is used and at most once.
+func (sm *SessionManager) UseStore(store SessionStore) error {
+	sessions, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	sm.mu.Lock()
+	for _, session := range sessions {
+		if session == nil || session.SessionID == "" {
+			continue
+		}
+		if session.History == nil {
+			session.History = make([]RoundInfo, 0)
+		}
+		sm.sessions[session.SessionID] = session
+	}
+	sm.counter.Add(int64(len(sessions)))
+	sm.store = store
+	sm.dirty = make(chan struct{}, 1)
+	sm.stopFlush = make(chan struct{})
+	sm.flushDone = make(chan struct{})
+	sm.mu.Unlock()
+
+	go sm.flushLoop()
+
+	return nil
+}
+
+// MarkDirty schedules a flush to the store. Handlers that modify a session
+// in place call this (via Update) so the change survives a restart.
+func (sm *SessionManager) MarkDirty() {
+	if sm.dirty == nil {
+		return
+	}
+	select {
+	case sm.dirty <- struct{}{}:
+	default:
+		// A flush is already pending
 	}
 }
 
-// GetOrCreate gets existing session or creates a new one
+// flushLoop writes batched changes to the store until Close is called.
+func (sm *SessionManager) flushLoop() {
+	defer close(sm.flushDone)
+
+	for {
+		select {
+		case <-sm.dirty:
+			// Batch changes that arrive during the delay into a single write
+			select {
+			case <-time.After(SessionFlushDelay):
+			case <-sm.stopFlush:
+			}
+			if err := sm.Flush(); err != nil {
+				fmt.Printf("[LGS] Failed to persist sessions: %v\n", err)
+			}
+		case <-sm.stopFlush:
+			return
+		}
+	}
+}
+
+// Flush writes all sessions to the store immediately.
+func (sm *SessionManager) Flush() error {
+	if sm.store == nil {
+		return nil
+	}
+	return sm.store.Save(sm.Snapshot())
+}
+
+// Close stops background flushing and writes a final snapshot.
+func (sm *SessionManager) Close() error {
+	if sm.store == nil {
+		return nil
+	}
+	close(sm.stopFlush)
+	<-sm.flushDone
+	return sm.Flush()
+}
+
+// GetOrCreate gets existing session or creates a new one. It never takes the
+// session lock, so a session held by a long request does not block the others;
+// callers that use the session bump its activity time themselves.
 func (sm *SessionManager) GetOrCreate(sessionID string) *SessionData {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
 	if session, ok := sm.sessions[sessionID]; ok {
-		session.LastActivity = time.Now()
 		return session
 	}
 
 	session := &SessionData{
 		SessionID:    sessionID,
-		Balance:      DefaultBalance,
-		Currency:     "USD",
+		Balance:      sm.defaultBalance,
+		Currency:     sm.defaultCurrency,
 		Language:     "en",
 		History:      make([]RoundInfo, 0),
 		CreatedAt:    time.Now(),
 		LastActivity: time.Now(),
+		Seed:         common.NewSeed(),
 	}
 
 	sm.sessions[sessionID] = session
 	sm.counter.Add(1)
+	sm.MarkDirty()
 
 	return session
 }
@@ -190,13 +511,62 @@ func (sm *SessionManager) Get(sessionID string) *SessionData {
 	return sm.sessions[sessionID]
 }
 
-// Update updates a session
+// Update updates a session. The caller must not hold the session lock.
 func (sm *SessionManager) Update(session *SessionData) {
+	session.Lock()
+	session.LastActivity = time.Now()
+	session.Unlock()
+
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	session.LastActivity = time.Now()
 	sm.sessions[session.SessionID] = session
+	sm.MarkDirty()
+}
+
+// WithSession runs fn with exclusive access to the session, creating it if needed.
+// The session's activity time is bumped and a flush is scheduled even if fn fails.
+// fn must not call SessionManager methods.
+func (sm *SessionManager) WithSession(sessionID string, fn func(*SessionData) error) error {
+	session := sm.GetOrCreate(sessionID)
+
+	defer sm.MarkDirty()
+
+	session.Lock()
+	defer session.Unlock()
+	defer func() { session.LastActivity = time.Now() }()
+	return fn(session)
+}
+
+// WithExisting is like WithSession but does not create the session.
+// Returns false if the session does not exist.
+func (sm *SessionManager) WithExisting(sessionID string, fn func(*SessionData)) bool {
+	session := sm.Get(sessionID)
+	if session == nil {
+		return false
+	}
+
+	defer sm.MarkDirty()
+
+	session.Lock()
+	defer session.Unlock()
+	defer func() { session.LastActivity = time.Now() }()
+	fn(session)
+	return true
+}
+
+// View runs fn with the session locked for reading, without counting as activity.
+// Returns false if the session does not exist.
+func (sm *SessionManager) View(sessionID string, fn func(*SessionData)) bool {
+	session := sm.Get(sessionID)
+	if session == nil {
+		return false
+	}
+
+	session.Lock()
+	defer session.Unlock()
+	fn(session)
+	return true
 }
 
 // Delete removes a session
@@ -205,6 +575,7 @@ func (sm *SessionManager) Delete(sessionID string) {
 	defer sm.mu.Unlock()
 
 	delete(sm.sessions, sessionID)
+	sm.MarkDirty()
 }
 
 // Count returns the number of active sessions
@@ -215,7 +586,8 @@ func (sm *SessionManager) Count() int {
 	return len(sm.sessions)
 }
 
-// GetAll returns all active sessions
+// GetAll returns all active sessions. The returned sessions are live;
+// lock them before reading their fields, or use Snapshot.
 func (sm *SessionManager) GetAll() []*SessionData {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -227,40 +599,55 @@ func (sm *SessionManager) GetAll() []*SessionData {
 	return sessions
 }
 
+// Snapshot returns a consistent copy of every session.
+func (sm *SessionManager) Snapshot() []*SessionData {
+	sessions := sm.GetAll()
+	snapshot := make([]*SessionData, 0, len(sessions))
+	for _, session := range sessions {
+		session.Lock()
+		snapshot = append(snapshot, session.clone())
+		session.Unlock()
+	}
+	return snapshot
+}
+
 // TotalCreated returns total number of sessions created
 func (sm *SessionManager) TotalCreated() int64 {
 	return sm.counter.Load()
 }
 
-// ResetBalance resets session balance to default
-func (sm *SessionManager) ResetBalance(sessionID string) *SessionData {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
+// CleanupInactive removes sessions inactive for more than the given duration
+func (sm *SessionManager) CleanupInactive(maxAge time.Duration) int {
+	cutoff := time.Now().Add(-maxAge)
 
-	if session, ok := sm.sessions[sessionID]; ok {
-		session.Balance = DefaultBalance
-		session.LastActivity = time.Now()
-		return session
+	// Check activity without holding sm.mu, so a session busy with a long
+	// request does not block the others. A locked session is in use, so it
+	// is skipped rather than waited for.
+	var inactive []*SessionData
+	for _, session := range sm.GetAll() {
+		if !session.TryLock() {
+			continue
+		}
+		if session.LastActivity.Before(cutoff) {
+			inactive = append(inactive, session)
+		}
+		session.Unlock()
 	}
 
-	return nil
-}
-
-// CleanupInactive removes sessions inactive for more than the given duration
-func (sm *SessionManager) CleanupInactive(maxAge time.Duration) int {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	cutoff := time.Now().Add(-maxAge)
 	cleaned := 0
-
-	for id, session := range sm.sessions {
-		if session.LastActivity.Before(cutoff) {
-			delete(sm.sessions, id)
+	for _, session := range inactive {
+		if sm.sessions[session.SessionID] == session {
+			delete(sm.sessions, session.SessionID)
 			cleaned++
 		}
 	}
 
+	if cleaned > 0 {
+		sm.MarkDirty()
+	}
 	return cleaned
 }
 
@@ -0,0 +1,129 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SessionsFile is the default snapshot file name, stored in the library folder.
+const SessionsFile = ".lgs_sessions.json"
+
+// SessionFlushDelay is how long changes are batched before being written to the store.
+const SessionFlushDelay = 500 * time.Millisecond
+
+// sessionSnapshotVersion is bumped when the snapshot format changes incompatibly.
+const sessionSnapshotVersion = 1
+
+// SessionStore persists sessions across backend restarts.
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Load returns all persisted sessions. A store with no data returns no sessions and no error.
+	Load() ([]*SessionData, error)
+	// Save replaces the persisted state with the given sessions.
+	Save(sessions []*SessionData) error
+}
+
+// sessionSnapshot is the on-disk format of FileSessionStore.
+type sessionSnapshot struct {
+	Version  int            `json:"version"`
+	SavedAt  time.Time      `json:"savedAt"`
+	Sessions []*SessionData `json:"sessions"`
+}
+
+// FileSessionStore stores all sessions as a single JSON snapshot.
+// Each save writes a temp file and renames it over the snapshot,
+// so a crash mid-write never leaves a truncated file behind.
+type FileSessionStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSessionStore creates a store backed by the file at path.
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{path: path}
+}
+
+// Path returns the snapshot file path.
+func (fs *FileSessionStore) Path() string {
+	return fs.path
+}
+
+// Load reads the snapshot file. A missing file is not an error.
+func (fs *FileSessionStore) Load() ([]*SessionData, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	data, err := os.ReadFile(fs.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session snapshot: %w", err)
+	}
+
+	var snapshot sessionSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse session snapshot: %w", err)
+	}
+	if snapshot.Version != sessionSnapshotVersion {
+		return nil, fmt.Errorf("unsupported session snapshot version %d", snapshot.Version)
+	}
+
+	return snapshot.Sessions, nil
+}
+
+// Save atomically replaces the snapshot file.
+func (fs *FileSessionStore) Save(sessions []*SessionData) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	data, err := json.Marshal(sessionSnapshot{
+		Version:  sessionSnapshotVersion,
+		SavedAt:  time.Now(),
+		Sessions: sessions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode session snapshot: %w", err)
+	}
+
+	if err := writeFileAtomic(fs.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save session snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic replaces path with data through a temp file in the same
+// folder, so a crash mid-write leaves the previous file intact.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package lgs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSessionManager_PersistsAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SessionsFile)
+
+	sm := NewSessionManager()
+	if err := sm.UseStore(NewFileSessionStore(path)); err != nil {
+		t.Fatal(err)
+	}
+
+	sm.WithSession("qa-1", func(session *SessionData) error {
+		session.Balance = 12345
+		session.SetForcedSimID("base", 42)
+		session.RTPBias = 0.5
+		session.AddRound(RoundInfo{BetID: 7, Amount: 100, Payout: 250, Mode: "base", Active: true})
+		return nil
+	})
+
+	if err := sm.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a backend restart
+	restored := NewSessionManager()
+	if err := restored.UseStore(NewFileSessionStore(path)); err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+
+	got := restored.Get("qa-1")
+	if got == nil {
+		t.Fatal("session was not restored")
+	}
+	if got.Balance != 12345 {
+		t.Errorf("balance: got %d, want 12345", got.Balance)
+	}
+	if simID, ok := got.GetForcedSimID("BASE"); !ok || simID != 42 {
+		t.Errorf("forced simID: got %d (%v), want 42", simID, ok)
+	}
+	if got.RTPBias != 0.5 {
+		t.Errorf("RTP bias: got %f, want 0.5", got.RTPBias)
+	}
+	if len(got.History) != 1 || got.LastRound == nil || got.LastRound.BetID != 7 {
+		t.Errorf("history not restored: %+v", got.History)
+	}
+	if got.TotalWagered != 100 || got.TotalWon != 250 {
+		t.Errorf("stats: wagered %d won %d, want 100 and 250", got.TotalWagered, got.TotalWon)
+	}
+}
+
+func TestSessionManager_PanicReleasesSession(t *testing.T) {
+	sm := NewSessionManager()
+
+	func() {
+		defer func() { recover() }()
+		sm.WithSession("qa", func(*SessionData) error { panic("sampler panic") })
+	}()
+
+	// A busy session must not block the others either
+	busy := make(chan struct{})
+	release := make(chan struct{})
+	go sm.WithSession("busy", func(*SessionData) error {
+		close(busy)
+		<-release
+		return nil
+	})
+	<-busy
+	defer close(release)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sm.WithSession("qa", func(s *SessionData) error { s.Balance = 1; return nil })
+		sm.WithSession("other", func(*SessionData) error { return nil })
+		sm.CleanupInactive(time.Hour)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("session manager deadlocked")
+	}
+}
+
+func TestFileSessionStore_MissingAndCorrupt(t *testing.T) {
+	dir := t.TempDir()
+
+	sessions, err := NewFileSessionStore(filepath.Join(dir, "missing.json")).Load()
+	if err != nil || len(sessions) != 0 {
+		t.Errorf("missing file: got %d sessions, err %v", len(sessions), err)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sm := NewSessionManager()
+	if err := sm.UseStore(NewFileSessionStore(corrupt)); err == nil {
+		t.Error("expected error for corrupt snapshot")
+	}
+
+	// A failed restore must leave the file untouched for inspection
+	data, _ := os.ReadFile(corrupt)
+	if string(data) != "{not json" {
+		t.Errorf("corrupt snapshot was overwritten: %q", data)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 
 	"lutexplorer/internal/common"
+	"stakergs"
 )
 
 // APIMultiplier (100 = 1x in payouts, amounts in cents)
@@ -16,6 +17,7 @@ const APIMultiplier = 100
 type AuthRequest struct {
 	SessionID string `json:"sessionID"`
 	Language  string `json:"language"`
+	Currency  string `json:"currency,omitempty"` // Switches the session's currency if set
 }
 
 // AuthResponse for /wallet/authenticate
@@ -32,7 +34,8 @@ type BalanceInfo struct {
 	Currency string `json:"currency"`
 }
 
-// JurisdictionInfo represents jurisdiction settings
+// JurisdictionInfo represents jurisdiction settings.
+// MinimumRoundDuration is in milliseconds.
 type JurisdictionInfo struct {
 	SocialCasino         bool `json:"socialCasino"`
 	DisabledFullscreen   bool `json:"disabledFullscreen"`
@@ -48,28 +51,33 @@ type JurisdictionInfo struct {
 	MinimumRoundDuration int  `json:"minimumRoundDuration"`
 }
 
-// ConfigInfo represents game configuration
+// ConfigInfo represents game configuration.
+// It is also the format of the library's GameConfigFile.
 type ConfigInfo struct {
-	GameID          string                 `json:"gameID"`
-	MinBet          int64                  `json:"minBet"`
-	MaxBet          int64                  `json:"maxBet"`
-	StepBet         int64                  `json:"stepBet"`
-	DefaultBetLevel int64                  `json:"defaultBetLevel"`
-	BetLevels       []int64                `json:"betLevels"`
-	BetModes        map[string]interface{} `json:"betModes"`
-	Jurisdiction    JurisdictionInfo       `json:"jurisdiction"`
+	GameID          string                      `json:"gameID"`
+	Currency        string                      `json:"currency"`
+	MinBet          int64                       `json:"minBet"`
+	MaxBet          int64                       `json:"maxBet"`
+	StepBet         int64                       `json:"stepBet"`
+	DefaultBetLevel int64                       `json:"defaultBetLevel"`
+	BetLevels       []int64                     `json:"betLevels"`
+	BetModes        map[string]stakergs.BetMode `json:"betModes"`
+	BuyFeatureModes []string                    `json:"buyFeatureModes"` // Modes rejected when DisabledBuyFeature is set
+	Jurisdiction    JurisdictionInfo            `json:"jurisdiction"`
+	Currencies      map[string]Currency         `json:"currencies,omitempty"` // Added or overridden currencies, by code
 }
 
 // DefaultConfigInfo returns default game configuration.
 func DefaultConfigInfo() ConfigInfo {
 	return ConfigInfo{
 		GameID:          "",
+		Currency:        DefaultCurrency,
 		MinBet:          common.MinBet,
 		MaxBet:          common.MaxBet,
 		StepBet:         common.StepBet,
 		DefaultBetLevel: common.DefaultBetLevel,
 		BetLevels:       common.DefaultBetLevels(),
-		BetModes:        map[string]interface{}{},
+		BetModes:        map[string]stakergs.BetMode{},
 		Jurisdiction:    JurisdictionInfo{},
 	}
 }
@@ -98,6 +106,8 @@ type RoundInfo struct {
 	State            json.RawMessage `json:"state"`
 	Mode             string          `json:"mode"`
 	Event            interface{}     `json:"event"`
+	Seed             int64           `json:"seed"` // Session seed and draw index that reproduce the outcome; 0 for forced outcomes
+	Draw             int64           `json:"draw"`
 }
 
 // EndRoundRequest for /wallet/end-round
@@ -139,9 +149,10 @@ type HealthResponse struct {
 	EventsLoaded map[string]int `json:"eventsLoaded"`
 }
 
-// ErrorResponse for errors
+// ErrorResponse for errors. Code is set for RGS endpoints (see ErrCodeInvalidRequest).
 type ErrorResponse struct {
 	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
 	Success bool   `json:"success"`
 }
 
@@ -185,30 +196,37 @@ type BatchPlayResponse struct {
 	HitRate      float64          `json:"hitRate"`
 	RTP          float64          `json:"rtp"`
 	MaxWin       float64          `json:"maxWin"`
-	BigWins      int              `json:"bigWins"`  // >= 10x
-	MegaWins     int              `json:"megaWins"` // >= 50x
+	BigWins      int              `json:"bigWins"`  // >= 10x (common.BigWinMultiplier), whatever the win tiers
+	MegaWins     int              `json:"megaWins"` // >= 50x (common.MegaWinMultiplier), whatever the win tiers
+	WinTiers     map[string]int   `json:"winTiers"` // Wins per tier, in the highest tier reached
 	Balance      BalanceInfo      `json:"balance"`
 	Rounds       []BatchPlayRound `json:"rounds,omitempty"` // Only if spins <= 1000
+	Seed         int64            `json:"seed"`             // All spins of a batch come from one draw
+	Draw         int64            `json:"draw"`
 	DurationMs   int64            `json:"durationMs"`
 }
 
 // SessionSummary contains summary info for a single session
 type SessionSummary struct {
-	SessionID      string         `json:"sessionID"`
-	Balance        int64          `json:"balance"`
-	Currency       string         `json:"currency"`
-	TotalBets      int64          `json:"totalBets"`
-	TotalWins      int64          `json:"totalWins"`
-	TotalWagered   int64          `json:"totalWagered"`
-	TotalWon       int64          `json:"totalWon"`
-	RTP            float64        `json:"rtp"`
-	HitRate        float64        `json:"hitRate"`
-	Profit         int64          `json:"profit"` // totalWagered - totalWon (house profit)
-	HistorySize    int            `json:"historySize"`
-	CreatedAt      string         `json:"createdAt"`
-	LastActivity   string         `json:"lastActivity"`
-	ForcedOutcomes map[string]int `json:"forcedOutcomes"`
-	RTPBias        float64        `json:"rtpBias"`
+	SessionID      string           `json:"sessionID"`
+	Balance        int64            `json:"balance"`
+	Currency       string           `json:"currency"`
+	Balances       map[string]int64 `json:"balances"` // Balance per currency used, including Currency
+	TotalBets      int64            `json:"totalBets"`
+	TotalWins      int64            `json:"totalWins"`
+	TotalWagered   int64            `json:"totalWagered"`
+	TotalWon       int64            `json:"totalWon"`
+	RTP            float64          `json:"rtp"`
+	HitRate        float64          `json:"hitRate"`
+	Profit         int64            `json:"profit"` // totalWagered - totalWon (house profit)
+	HistorySize    int              `json:"historySize"`
+	CreatedAt      string           `json:"createdAt"`
+	LastActivity   string           `json:"lastActivity"`
+	ForcedOutcomes map[string]int   `json:"forcedOutcomes"`
+	RTPBias        float64          `json:"rtpBias"`
+	Jurisdiction   string           `json:"jurisdiction,omitempty"`
+	Seed           int64            `json:"seed"`
+	Draws          int64            `json:"draws"`
 }
 
 // SessionsResponse for GET /lgs/sessions
@@ -0,0 +1,191 @@
+package lut
+
+import (
+	"math"
+	"math/rand"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/sampling"
+	"stakergs"
+)
+
+// DefaultChiSquareAlpha is the significance level used when none is given.
+const DefaultChiSquareAlpha = 0.01
+
+// minExpectedCount is the smallest expected count a chi-square bin may have.
+// Rarer outcomes are pooled with their neighbours until they reach it.
+const minExpectedCount = 5
+
+// IndexSampler draws indices into a table's outcomes.
+type IndexSampler interface {
+	SampleIndex(rng *rand.Rand) int
+}
+
+// ChiSquareResult holds the outcome of a chi-square goodness-of-fit test.
+type ChiSquareResult struct {
+	Draws            int64   `json:"draws"`
+	Bins             int     `json:"bins"` // Bins after pooling rare outcomes
+	DegreesOfFreedom int     `json:"degrees_of_freedom"`
+	Statistic        float64 `json:"statistic"`
+	PValue           float64 `json:"p_value"`
+	Alpha            float64 `json:"alpha"`
+	Passed           bool    `json:"passed"` // PValue >= Alpha
+}
+
+// OutcomeProbabilities returns the theoretical probability of drawing each
+// outcome of a table with the given payout bias (0 for none).
+func OutcomeProbabilities(table *stakergs.LookupTable, bias float64) []float64 {
+	var weights []float64
+	if bias == 0 {
+		weights = make([]float64, len(table.Outcomes))
+		for i, o := range table.Outcomes {
+			weights[i] = float64(o.Weight)
+		}
+	} else {
+		weights = sampling.BiasedWeights(table.Outcomes, bias)
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+	return weights
+}
+
+// ChiSquareSampler draws from a sampler and tests the draws against the
+// theoretical probabilities. The same seed always gives the same result.
+func ChiSquareSampler(sampler IndexSampler, probabilities []float64, draws int64, seed int64, alpha float64) ChiSquareResult {
+	rng := common.NewRand(seed, 0)
+	observed := make([]int64, len(probabilities))
+	for i := int64(0); i < draws; i++ {
+		observed[sampler.SampleIndex(rng)]++
+	}
+	return ChiSquare(observed, probabilities, alpha)
+}
+
+// ChiSquare tests observed counts against expected probabilities summing to 1.
+// Adjacent bins are pooled until each expects at least five draws, so tables
+// with long tails of rare outcomes can be tested. A draw of an outcome with
+// zero probability fails the test outright.
+func ChiSquare(observed []int64, probabilities []float64, alpha float64) ChiSquareResult {
+	if alpha <= 0 {
+		alpha = DefaultChiSquareAlpha
+	}
+
+	var draws int64
+	for _, n := range observed {
+		draws += n
+	}
+	result := ChiSquareResult{Draws: draws, Alpha: alpha}
+
+	// Pool bins in table order into (observed, expected) pairs
+	type bin struct{ observed, expected float64 }
+	var bins []bin
+	var pool bin
+	for i, p := range probabilities {
+		if p == 0 {
+			if observed[i] > 0 {
+				result.Statistic = math.Inf(1)
+				return result
+			}
+			continue
+		}
+		pool.observed += float64(observed[i])
+		pool.expected += p * float64(draws)
+		if pool.expected >= minExpectedCount {
+			bins = append(bins, pool)
+			pool = bin{}
+		}
+	}
+	if pool.expected > 0 {
+		if len(bins) == 0 {
+			bins = append(bins, pool)
+		} else {
+			bins[len(bins)-1].observed += pool.observed
+			bins[len(bins)-1].expected += pool.expected
+		}
+	}
+
+	result.Bins = len(bins)
+	result.DegreesOfFreedom = len(bins) - 1
+	if result.DegreesOfFreedom < 1 {
+		// A single bin always matches: there is nothing to test
+		result.PValue = 1
+		result.Passed = true
+		return result
+	}
+
+	for _, b := range bins {
+		d := b.observed - b.expected
+		result.Statistic += d * d / b.expected
+	}
+	result.PValue = ChiSquarePValue(result.Statistic, result.DegreesOfFreedom)
+	result.Passed = result.PValue >= alpha
+	return result
+}
+
+// ChiSquarePValue returns the probability that a chi-square variable with df
+// degrees of freedom is at least stat.
+func ChiSquarePValue(stat float64, df int) float64 {
+	if stat <= 0 {
+		return 1
+	}
+	if math.IsInf(stat, 1) {
+		return 0
+	}
+	return upperGamma(float64(df)/2, stat/2)
+}
+
+// upperGamma returns the regularized upper incomplete gamma function Q(a, x),
+// by series below a+1 and by continued fraction above, where each converges fast.
+func upperGamma(a, x float64) float64 {
+	const (
+		eps     = 1e-15
+		maxIter = 1000
+		tiny    = 1e-300
+	)
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - lgamma)
+
+	if x < a+1 {
+		// P(a, x) = x^a e^-x / Γ(a) * Σ x^n / (a (a+1) ... (a+n))
+		term := 1 / a
+		sum := term
+		for n := 1; n < maxIter; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*eps {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	// Modified Lentz evaluation of the continued fraction for Q(a, x)
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIter; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return h * prefix
+}
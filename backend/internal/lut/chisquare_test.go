@@ -0,0 +1,109 @@
+package lut
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"lutexplorer/internal/sampling"
+	"stakergs"
+)
+
+func TestChiSquarePValue_KnownQuantiles(t *testing.T) {
+	tests := []struct {
+		stat float64
+		df   int
+		want float64
+	}{
+		{3.841459, 1, 0.05},
+		{6.634897, 1, 0.01},
+		{18.307038, 10, 0.05},
+		{10, 10, 0.440493},
+		{124.342113, 100, 0.05},
+	}
+	for _, tt := range tests {
+		if got := ChiSquarePValue(tt.stat, tt.df); math.Abs(got-tt.want) > 1e-5 {
+			t.Errorf("ChiSquarePValue(%g, %d) = %g, want %g", tt.stat, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestChiSquareSampler_AliasSamplerPasses(t *testing.T) {
+	table := &stakergs.LookupTable{Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 600, Payout: 0},
+		{SimID: 1, Weight: 250, Payout: 50},
+		{SimID: 2, Weight: 100, Payout: 200},
+		{SimID: 3, Weight: 45, Payout: 1000},
+		{SimID: 4, Weight: 5, Payout: 10000},
+	}}
+
+	for _, bias := range []float64{0, 2.5, -1} {
+		sampler := sampling.NewBiased(table, bias)
+		result := ChiSquareSampler(sampler, OutcomeProbabilities(table, bias), 200_000, 1, 0)
+		if !result.Passed {
+			t.Errorf("bias %g: p-value %g below %g (statistic %g)", bias, result.PValue, result.Alpha, result.Statistic)
+		}
+	}
+}
+
+// moduloSampler is the cumulative-weight sampler with a modulo draw that the
+// alias table replaced, kept to show the harness catches its bias.
+type moduloSampler struct {
+	cumulative []uint64
+}
+
+func (s moduloSampler) SampleIndex(rng *rand.Rand) int {
+	r := rng.Uint64() % s.cumulative[len(s.cumulative)-1]
+	return sort.Search(len(s.cumulative), func(i int) bool { return s.cumulative[i] > r })
+}
+
+func TestChiSquareSampler_DetectsModuloBias(t *testing.T) {
+	// A total of 3·2^62 makes a modulo draw land in the first quarter of the
+	// range twice as often, so the first outcome is drawn half the time
+	table := &stakergs.LookupTable{Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 1 << 62},
+		{SimID: 1, Weight: 1 << 62},
+		{SimID: 2, Weight: 1 << 62},
+	}}
+	probabilities := OutcomeProbabilities(table, 0)
+
+	biased := moduloSampler{cumulative: []uint64{1 << 62, 2 << 62, 3 << 62}}
+	if result := ChiSquareSampler(biased, probabilities, 10_000, 1, 0); result.Passed {
+		t.Errorf("modulo sampler passed with p-value %g", result.PValue)
+	}
+
+	if result := ChiSquareSampler(sampling.New(table), probabilities, 10_000, 1, 0); !result.Passed {
+		t.Errorf("alias sampler failed with p-value %g", result.PValue)
+	}
+}
+
+func TestChiSquare_PoolsRareOutcomes(t *testing.T) {
+	// 200 draws expect about ten of each of the first 20 outcomes and 0.02 of
+	// each of the rest, which are pooled into the last common bin
+	probabilities := make([]float64, 20)
+	observed := make([]int64, 20)
+	for i := range probabilities {
+		probabilities[i] = 0.0499
+		observed[i] = 10
+	}
+	for i := 0; i < 20; i++ {
+		probabilities = append(probabilities, 0.0001)
+		observed = append(observed, 0)
+	}
+	probabilities = append(probabilities, 0)
+	observed = append(observed, 0)
+
+	result := ChiSquare(observed, probabilities, 0)
+	if result.Bins != 20 || result.DegreesOfFreedom != 19 {
+		t.Errorf("got %d bins and %d degrees of freedom, want 20 and 19", result.Bins, result.DegreesOfFreedom)
+	}
+	if !result.Passed {
+		t.Errorf("expected a near-perfect fit to pass, p-value %g", result.PValue)
+	}
+
+	observed[len(observed)-1] = 1
+	if result := ChiSquare(observed, probabilities, 0); result.Passed || result.PValue != 0 {
+		t.Errorf("expected a draw of a zero-probability outcome to fail, got p-value %g", result.PValue)
+	}
+}
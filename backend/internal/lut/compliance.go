@@ -37,13 +37,15 @@ type ComplianceCheck struct {
 
 // ComplianceResult contains all compliance check results for a mode.
 type ComplianceResult struct {
-	Mode         string             `json:"mode"`
-	Passed       bool               `json:"passed"`
-	PassedCount  int                `json:"passed_count"`
-	FailedCount  int                `json:"failed_count"`
-	WarningCount int                `json:"warning_count"`
-	Checks       []ComplianceCheck  `json:"checks"`
-	Summary      ComplianceSummary  `json:"summary"`
+	Mode         string              `json:"mode"`
+	Profile      string              `json:"profile"`
+	Disabled     []ComplianceCheckID `json:"disabled_checks,omitempty"`
+	Passed       bool                `json:"passed"`
+	PassedCount  int                 `json:"passed_count"`
+	FailedCount  int                 `json:"failed_count"`
+	WarningCount int                 `json:"warning_count"`
+	Checks       []ComplianceCheck   `json:"checks"`
+	Summary      ComplianceSummary   `json:"summary"`
 }
 
 // ComplianceSummary contains summary statistics used for compliance checks.
@@ -62,6 +64,8 @@ type ComplianceSummary struct {
 // AllModesComplianceResult contains compliance results for all modes.
 type AllModesComplianceResult struct {
 	AllPassed   bool                        `json:"all_passed"`
+	Profile     string                      `json:"profile"`
+	Disabled    []ComplianceCheckID         `json:"disabled_checks,omitempty"`
 	ModeResults map[string]*ComplianceResult `json:"mode_results"`
 	GlobalChecks []ComplianceCheck           `json:"global_checks"`
 }
@@ -69,23 +73,42 @@ type AllModesComplianceResult struct {
 // ComplianceChecker performs compliance checks on LUT tables.
 type ComplianceChecker struct {
 	analyzer *Analyzer
+	profile  *ComplianceProfile
 }
 
-// NewComplianceChecker creates a new compliance checker.
+// NewComplianceChecker creates a new compliance checker using the default profile.
 func NewComplianceChecker() *ComplianceChecker {
+	return NewComplianceCheckerWithProfile(DefaultComplianceProfile())
+}
+
+// NewComplianceCheckerWithProfile creates a compliance checker that applies
+// the given profile's enabled checks, severities and thresholds.
+func NewComplianceCheckerWithProfile(profile *ComplianceProfile) *ComplianceChecker {
+	if profile == nil {
+		profile = DefaultComplianceProfile()
+	}
 	return &ComplianceChecker{
 		analyzer: NewAnalyzer(),
+		profile:  profile,
 	}
 }
 
+// Profile returns the profile applied by this checker.
+func (c *ComplianceChecker) Profile() *ComplianceProfile {
+	return c.profile
+}
+
 // CheckMode performs all compliance checks on a single mode.
 func (c *ComplianceChecker) CheckMode(lut *stakergs.LookupTable) *ComplianceResult {
 	stats := c.analyzer.Analyze(lut)
 	totalWeight := lut.TotalWeight()
 
 	result := &ComplianceResult{
-		Mode:   lut.Mode,
-		Checks: make([]ComplianceCheck, 0),
+		Mode:     lut.Mode,
+		Passed:   true,
+		Profile:  c.profile.Name,
+		Disabled: c.profile.DisabledChecks(),
+		Checks:   make([]ComplianceCheck, 0),
 		Summary: ComplianceSummary{
 			RTP:           stats.RTP,
 			HitRate:       stats.HitRate,
@@ -101,35 +124,49 @@ func (c *ComplianceChecker) CheckMode(lut *stakergs.LookupTable) *ComplianceResu
 	result.Summary.MaxPayoutHitRate = c.calculateMaxPayoutHitRate(lut, totalWeight)
 	result.Summary.MostFrequentProb, _ = c.calculateMostFrequentProbability(lut, totalWeight)
 
-	// Run all checks
-	result.Checks = append(result.Checks, c.checkRTPRange(stats))
-	result.Checks = append(result.Checks, c.checkMaxWinAchievable(lut, totalWeight, stats))
-	result.Checks = append(result.Checks, c.checkHitRateReasonable(lut, stats))
-	result.Checks = append(result.Checks, c.checkPayoutGaps(lut, stats))
-	result.Checks = append(result.Checks, c.checkUniquePayouts(lut))
-	result.Checks = append(result.Checks, c.checkSimulationDiversity(lut, totalWeight))
-	result.Checks = append(result.Checks, c.checkZeroPayoutRate(stats))
-	result.Checks = append(result.Checks, c.checkVolatility(stats))
-
-	// Calculate totals
-	for _, check := range result.Checks {
-		if check.Passed {
-			result.PassedCount++
-		} else if check.Severity == "warning" {
-			result.WarningCount++
-		} else {
-			result.FailedCount++
+	// Run all checks enabled by the profile
+	checks := []struct {
+		id  ComplianceCheckID
+		run func() ComplianceCheck
+	}{
+		{CheckRTPRange, func() ComplianceCheck { return c.checkRTPRange(stats) }},
+		{CheckMaxWinAchievable, func() ComplianceCheck { return c.checkMaxWinAchievable(lut, totalWeight, stats) }},
+		{CheckHitRateReasonable, func() ComplianceCheck { return c.checkHitRateReasonable(lut, stats) }},
+		{CheckPayoutGaps, func() ComplianceCheck { return c.checkPayoutGaps(lut, stats) }},
+		{CheckUniquePayouts, func() ComplianceCheck { return c.checkUniquePayouts(lut) }},
+		{CheckSimulationDiversity, func() ComplianceCheck { return c.checkSimulationDiversity(lut, totalWeight) }},
+		{CheckZeroPayoutRate, func() ComplianceCheck { return c.checkZeroPayoutRate(stats) }},
+		{CheckVolatility, func() ComplianceCheck { return c.checkVolatility(stats) }},
+	}
+	for _, check := range checks {
+		if c.profile.IsEnabled(check.id) {
+			c.addCheck(result, check.run())
 		}
 	}
 
-	result.Passed = result.FailedCount == 0
-
 	return result
 }
 
+// addCheck appends a check to the result and updates the counters.
+func (c *ComplianceChecker) addCheck(result *ComplianceResult, check ComplianceCheck) {
+	result.Checks = append(result.Checks, check)
+
+	if check.Passed {
+		result.PassedCount++
+	} else if check.Severity == "warning" {
+		result.WarningCount++
+	} else {
+		result.FailedCount++
+	}
+
+	result.Passed = result.FailedCount == 0
+}
+
 // CheckAllModes performs compliance checks on all modes and cross-mode checks.
 func (c *ComplianceChecker) CheckAllModes(tables map[string]*stakergs.LookupTable) *AllModesComplianceResult {
 	result := &AllModesComplianceResult{
+		Profile:      c.profile.Name,
+		Disabled:     c.profile.DisabledChecks(),
 		ModeResults:  make(map[string]*ComplianceResult),
 		GlobalChecks: make([]ComplianceCheck, 0),
 		AllPassed:    true,
@@ -143,19 +180,8 @@ func (c *ComplianceChecker) CheckAllModes(tables map[string]*stakergs.LookupTabl
 		modeResult := c.CheckMode(lut)
 
 		// Add per-mode RTP variation check if we have multiple modes
-		if len(tables) > 1 {
-			rtpCheck := c.checkModeRTPVariation(lut, baseRTP, baseModeName)
-			modeResult.Checks = append(modeResult.Checks, rtpCheck)
-
-			// Update counts
-			if rtpCheck.Passed {
-				modeResult.PassedCount++
-			} else if rtpCheck.Severity == "warning" {
-				modeResult.WarningCount++
-			} else {
-				modeResult.FailedCount++
-				modeResult.Passed = false
-			}
+		if len(tables) > 1 && c.profile.IsEnabled(CheckRTPVariation) {
+			c.addCheck(modeResult, c.checkModeRTPVariation(lut, baseRTP, baseModeName))
 		}
 
 		result.ModeResults[mode] = modeResult
@@ -165,7 +191,7 @@ func (c *ComplianceChecker) CheckAllModes(tables map[string]*stakergs.LookupTabl
 	}
 
 	// Global cross-mode RTP variation check (summary)
-	if len(tables) > 1 {
+	if len(tables) > 1 && c.profile.IsEnabled(CheckRTPVariation) {
 		rtpCheck := c.checkRTPVariationGlobal(tables, baseRTP, baseModeName)
 		result.GlobalChecks = append(result.GlobalChecks, rtpCheck)
 		if !rtpCheck.Passed && rtpCheck.Severity == "error" {
@@ -200,7 +226,7 @@ func (c *ComplianceChecker) findBaseRTP(tables map[string]*stakergs.LookupTable)
 
 // checkModeRTPVariation checks if a single mode's RTP is within allowed range of base RTP.
 func (c *ComplianceChecker) checkModeRTPVariation(lut *stakergs.LookupTable, baseRTP float64, baseModeName string) ComplianceCheck {
-	maxVariation := 0.005 // 0.5%
+	maxVariation := c.profile.Threshold(CheckRTPVariation, "max_deviation")
 	minAllowed := baseRTP - maxVariation
 	maxAllowed := baseRTP + maxVariation
 
@@ -215,10 +241,10 @@ func (c *ComplianceChecker) checkModeRTPVariation(lut *stakergs.LookupTable, bas
 	check := ComplianceCheck{
 		ID:          CheckRTPVariation,
 		Name:        "RTP vs Base Mode",
-		Description: fmt.Sprintf("RTP must be within ±0.5%% of %s (%.2f%%)", baseModeName, baseRTP*100),
+		Description: fmt.Sprintf("RTP must be within ±%.2f%% of %s (%.2f%%)", maxVariation*100, baseModeName, baseRTP*100),
 		Expected:    fmt.Sprintf("%.2f%% - %.2f%%", minAllowed*100, maxAllowed*100),
 		Value:       fmt.Sprintf("%.2f%% (deviation: %.2f%%)", modeRTP*100, deviation*100),
-		Severity:    "error",
+		Severity:    c.profile.Severity(CheckRTPVariation),
 		Details: map[string]interface{}{
 			"base_mode":   baseModeName,
 			"base_rtp":    baseRTP,
@@ -244,16 +270,16 @@ func (c *ComplianceChecker) checkModeRTPVariation(lut *stakergs.LookupTable, bas
 }
 
 func (c *ComplianceChecker) checkRTPRange(stats *Statistics) ComplianceCheck {
-	minRTP := 0.90
-	maxRTP := 0.98
+	minRTP := c.profile.Threshold(CheckRTPRange, "min")
+	maxRTP := c.profile.Threshold(CheckRTPRange, "max")
 
 	check := ComplianceCheck{
 		ID:          CheckRTPRange,
 		Name:        "RTP Range",
-		Description: "Return to Player must be between 90% and 98%",
+		Description: fmt.Sprintf("Return to Player must be between %.1f%% and %.1f%%", minRTP*100, maxRTP*100),
 		Expected:    fmt.Sprintf("%.1f%% - %.1f%%", minRTP*100, maxRTP*100),
 		Value:       fmt.Sprintf("%.2f%%", stats.RTP*100),
-		Severity:    "error",
+		Severity:    c.profile.Severity(CheckRTPRange),
 	}
 
 	if stats.RTP >= minRTP && stats.RTP <= maxRTP {
@@ -272,7 +298,7 @@ func (c *ComplianceChecker) checkRTPRange(stats *Statistics) ComplianceCheck {
 
 // checkRTPVariationGlobal creates a global summary of RTP variation across all modes.
 func (c *ComplianceChecker) checkRTPVariationGlobal(tables map[string]*stakergs.LookupTable, baseRTP float64, baseModeName string) ComplianceCheck {
-	maxVariation := 0.005 // 0.5%
+	maxVariation := c.profile.Threshold(CheckRTPVariation, "max_deviation")
 	minAllowed := baseRTP - maxVariation
 	maxAllowed := baseRTP + maxVariation
 
@@ -302,10 +328,10 @@ func (c *ComplianceChecker) checkRTPVariationGlobal(tables map[string]*stakergs.
 	check := ComplianceCheck{
 		ID:          CheckRTPVariation,
 		Name:        "RTP Variation Between Modes",
-		Description: fmt.Sprintf("All modes must be within ±0.5%% of %s (%.2f%%)", baseModeName, baseRTP*100),
+		Description: fmt.Sprintf("All modes must be within ±%.2f%% of %s (%.2f%%)", maxVariation*100, baseModeName, baseRTP*100),
 		Expected:    fmt.Sprintf("%.2f%% - %.2f%%", minAllowed*100, maxAllowed*100),
 		Value:       fmt.Sprintf("%d/%d modes passed", passedModes, totalModes),
-		Severity:    "error",
+		Severity:    c.profile.Severity(CheckRTPVariation),
 		Details: map[string]interface{}{
 			"base_mode":        baseModeName,
 			"base_rtp":         baseRTP,
@@ -333,7 +359,7 @@ func (c *ComplianceChecker) checkMaxWinAchievable(lut *stakergs.LookupTable, tot
 	// Max win should be achievable with hit-rate of at least 1 in 20,000,000 for base mode (cost=1)
 	// For bonus modes with higher cost, the threshold is adjusted: 20,000,000 / cost
 	// Example: bonus with cost=200x -> maxOdds = 20,000,000 / 200 = 100,000
-	baseMaxOdds := 20_000_000.0
+	baseMaxOdds := c.profile.Threshold(CheckMaxWinAchievable, "max_odds")
 	cost := lut.Cost
 	if cost <= 0 {
 		cost = 1.0
@@ -352,9 +378,9 @@ func (c *ComplianceChecker) checkMaxWinAchievable(lut *stakergs.LookupTable, tot
 
 	description := "Advertised max win must be realistically obtainable"
 	if cost > 1 {
-		description = fmt.Sprintf("Max win obtainable (adjusted for %.0fx cost: 20M/%.0f = %s)", cost, cost, formatLargeNumber(maxOdds))
+		description = fmt.Sprintf("Max win obtainable (adjusted for %.0fx cost: %s/%.0f = %s)", cost, formatLargeNumber(baseMaxOdds), cost, formatLargeNumber(maxOdds))
 	} else {
-		description = "Max win must be obtainable (hit-rate better than 1 in 20M for base mode)"
+		description = fmt.Sprintf("Max win must be obtainable (hit-rate better than 1 in %s for base mode)", formatLargeNumber(baseMaxOdds))
 	}
 
 	check := ComplianceCheck{
@@ -363,7 +389,7 @@ func (c *ComplianceChecker) checkMaxWinAchievable(lut *stakergs.LookupTable, tot
 		Description: description,
 		Expected:    fmt.Sprintf("Odds ≤ 1 in %s", formatLargeNumber(maxOdds)),
 		Value:       fmt.Sprintf("1 in %s", formatLargeNumber(actualOdds)),
-		Severity:    "error",
+		Severity:    c.profile.Severity(CheckMaxWinAchievable),
 		Details: map[string]interface{}{
 			"max_payout":        stats.MaxPayout,
 			"max_payout_weight": maxPayoutWeight,
@@ -379,8 +405,8 @@ func (c *ComplianceChecker) checkMaxWinAchievable(lut *stakergs.LookupTable, tot
 		check.Passed = true
 	} else {
 		check.Passed = false
-		check.Reason = fmt.Sprintf("Max win (%.2fx) is too rare. Odds 1 in %s exceed adjusted limit 1 in %s (base 20M / %.0fx cost)",
-			stats.MaxPayout, formatLargeNumber(actualOdds), formatLargeNumber(maxOdds), cost)
+		check.Reason = fmt.Sprintf("Max win (%.2fx) is too rare. Odds 1 in %s exceed adjusted limit 1 in %s (base %s / %.0fx cost)",
+			stats.MaxPayout, formatLargeNumber(actualOdds), formatLargeNumber(maxOdds), formatLargeNumber(baseMaxOdds), cost)
 	}
 
 	return check
@@ -394,12 +420,13 @@ func (c *ComplianceChecker) checkHitRateReasonable(lut *stakergs.LookupTable, st
 		cost = 1.0
 	}
 
-	// Skip check for bonus modes (cost > 2)
-	if cost > 2 {
+	// Skip check for bonus modes (cost > 2 by default)
+	maxCost := c.profile.Threshold(CheckHitRateReasonable, "max_cost")
+	if cost > maxCost {
 		return ComplianceCheck{
 			ID:          CheckHitRateReasonable,
 			Name:        "Hit Rate",
-			Description: fmt.Sprintf("Skipped for bonus mode (cost %.0fx > 2x)", cost),
+			Description: fmt.Sprintf("Skipped for bonus mode (cost %.0fx > %.0fx)", cost, maxCost),
 			Expected:    "N/A (bonus mode)",
 			Value:       fmt.Sprintf("%.2f%% (1 in %.2f)", stats.HitRate*100, 1.0/stats.HitRate),
 			Severity:    "info",
@@ -408,18 +435,18 @@ func (c *ComplianceChecker) checkHitRateReasonable(lut *stakergs.LookupTable, st
 	}
 
 	// For base modes: hit rate should be between 1 in 3 and 1 in 20
-	minHitRate := 0.05 // 1 in 20
-	maxHitRate := 0.33 // 1 in 3
+	minHitRate := c.profile.Threshold(CheckHitRateReasonable, "min")
+	maxHitRate := c.profile.Threshold(CheckHitRateReasonable, "max")
 
 	odds := 1.0 / stats.HitRate
 
 	check := ComplianceCheck{
 		ID:          CheckHitRateReasonable,
 		Name:        "Hit Rate",
-		Description: "Non-zero win hit rate should be reasonable (typically 1 in 3 to 1 in 20)",
+		Description: fmt.Sprintf("Non-zero win hit rate should be reasonable (1 in %.0f to 1 in %.0f)", 1/maxHitRate, 1/minHitRate),
 		Expected:    fmt.Sprintf("%.0f%% - %.0f%% (1 in %.0f - 1 in %.0f)", minHitRate*100, maxHitRate*100, 1/maxHitRate, 1/minHitRate),
 		Value:       fmt.Sprintf("%.2f%% (1 in %.2f)", stats.HitRate*100, odds),
-		Severity:    "warning",
+		Severity:    c.profile.Severity(CheckHitRateReasonable),
 	}
 
 	if stats.HitRate >= minHitRate && stats.HitRate <= maxHitRate {
@@ -492,7 +519,7 @@ func (c *ComplianceChecker) checkPayoutGaps(lut *stakergs.LookupTable, stats *St
 		Name:        "Payout Distribution Gaps",
 		Description: "Hit-rate table should be broadly populated without significant gaps",
 		Expected:    "No significant gaps in payout ranges",
-		Severity:    "warning",
+		Severity:    c.profile.Severity(CheckPayoutGaps),
 	}
 
 	if len(gaps) == 0 {
@@ -510,7 +537,7 @@ func (c *ComplianceChecker) checkPayoutGaps(lut *stakergs.LookupTable, stats *St
 
 func (c *ComplianceChecker) checkUniquePayouts(lut *stakergs.LookupTable) ComplianceCheck {
 	// For slot-type games, should have reasonable number of unique payout values
-	minUnique := 10
+	minUnique := int(c.profile.Threshold(CheckUniquePayouts, "min"))
 
 	uniquePayouts := c.countUniquePayouts(lut)
 
@@ -520,7 +547,7 @@ func (c *ComplianceChecker) checkUniquePayouts(lut *stakergs.LookupTable) Compli
 		Description: "Ensure there is a reasonable number of unique payout amounts for variety",
 		Expected:    fmt.Sprintf("≥ %d unique values", minUnique),
 		Value:       fmt.Sprintf("%d unique values", uniquePayouts),
-		Severity:    "warning",
+		Severity:    c.profile.Severity(CheckUniquePayouts),
 	}
 
 	if uniquePayouts >= minUnique {
@@ -536,7 +563,7 @@ func (c *ComplianceChecker) checkUniquePayouts(lut *stakergs.LookupTable) Compli
 func (c *ComplianceChecker) checkSimulationDiversity(lut *stakergs.LookupTable, totalWeight uint64) ComplianceCheck {
 	// No single result should be so frequent that it appears multiple times in a typical session
 	// With 100,000 simulations, a single result shouldn't exceed ~1% probability
-	maxSingleProb := 0.01 // 1%
+	maxSingleProb := c.profile.Threshold(CheckSimulationDiversity, "max_probability")
 
 	mostFreqProb, zeroProb := c.calculateMostFrequentProbability(lut, totalWeight)
 
@@ -546,7 +573,7 @@ func (c *ComplianceChecker) checkSimulationDiversity(lut *stakergs.LookupTable,
 		Description: "No single outcome should be so frequent it could appear multiple times in a session",
 		Expected:    fmt.Sprintf("Most frequent outcome < %.1f%%", maxSingleProb*100),
 		Value:       fmt.Sprintf("%.2f%%", mostFreqProb*100),
-		Severity:    "warning",
+		Severity:    c.profile.Severity(CheckSimulationDiversity),
 	}
 
 	if mostFreqProb <= maxSingleProb {
@@ -560,8 +587,8 @@ func (c *ComplianceChecker) checkSimulationDiversity(lut *stakergs.LookupTable,
 }
 
 func (c *ComplianceChecker) checkZeroPayoutRate(stats *Statistics) ComplianceCheck {
-	// Non-paying results shouldn't exceed 90%
-	maxZeroRate := 0.90
+	// Non-paying results shouldn't exceed 90% (default profile)
+	maxZeroRate := c.profile.Threshold(CheckZeroPayoutRate, "max")
 
 	check := ComplianceCheck{
 		ID:          CheckZeroPayoutRate,
@@ -569,7 +596,7 @@ func (c *ComplianceChecker) checkZeroPayoutRate(stats *Statistics) ComplianceChe
 		Description: "A reasonable portion of simulations should yield paying results",
 		Expected:    fmt.Sprintf("Non-paying ≤ %.0f%%", maxZeroRate*100),
 		Value:       fmt.Sprintf("%.2f%% non-paying", stats.ZeroPayoutRate*100),
-		Severity:    "error",
+		Severity:    c.profile.Severity(CheckZeroPayoutRate),
 	}
 
 	if stats.ZeroPayoutRate <= maxZeroRate {
@@ -586,7 +613,7 @@ func (c *ComplianceChecker) checkZeroPayoutRate(stats *Statistics) ComplianceChe
 func (c *ComplianceChecker) checkVolatility(stats *Statistics) ComplianceCheck {
 	// Volatility check - standard deviation should be within industry norms
 	// This is more informational
-	maxVolatility := 50.0 // Very high volatility threshold
+	maxVolatility := c.profile.Threshold(CheckVolatility, "max")
 
 	check := ComplianceCheck{
 		ID:          CheckVolatility,
@@ -594,7 +621,7 @@ func (c *ComplianceChecker) checkVolatility(stats *Statistics) ComplianceCheck {
 		Description: "Standard deviation should be within industry norms for reasonable gameplay",
 		Expected:    fmt.Sprintf("Volatility < %.0f", maxVolatility),
 		Value:       fmt.Sprintf("%.2f", stats.Volatility),
-		Severity:    "info",
+		Severity:    c.profile.Severity(CheckVolatility),
 	}
 
 	if stats.Volatility < maxVolatility {
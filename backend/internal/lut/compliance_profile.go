@@ -0,0 +1,265 @@
+package lut
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ComplianceProfilesFile is the file name, relative to the library folder,
+// that holds custom compliance profiles.
+const ComplianceProfilesFile = "compliance_profiles.json"
+
+// DefaultComplianceProfileName is the name of the built-in profile.
+const DefaultComplianceProfileName = "default"
+
+// ComplianceRule overrides how a single check is applied.
+// Nil or empty fields fall back to the default profile.
+type ComplianceRule struct {
+	Enabled    *bool              `json:"enabled,omitempty"`
+	Severity   string             `json:"severity,omitempty"` // "error", "warning", "info"
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+}
+
+// ComplianceProfile is a named set of rule overrides for a jurisdiction or operator.
+type ComplianceProfile struct {
+	Name        string                               `json:"name"`
+	Description string                               `json:"description,omitempty"`
+	Rules       map[ComplianceCheckID]ComplianceRule `json:"rules,omitempty"`
+}
+
+// ComplianceProfilesConfig is the on-disk format of ComplianceProfilesFile.
+type ComplianceProfilesConfig struct {
+	Profiles []*ComplianceProfile `json:"profiles"`
+}
+
+// defaultRules holds the built-in severity and thresholds for each check.
+// Every configurable threshold must be listed here; profiles may only
+// override keys that exist in this table.
+var defaultRules = map[ComplianceCheckID]ComplianceRule{
+	CheckRTPRange: {
+		Severity:   "error",
+		Thresholds: map[string]float64{"min": 0.90, "max": 0.98},
+	},
+	CheckRTPVariation: {
+		Severity:   "error",
+		Thresholds: map[string]float64{"max_deviation": 0.005},
+	},
+	CheckMaxWinAchievable: {
+		Severity:   "error",
+		Thresholds: map[string]float64{"max_odds": 20_000_000},
+	},
+	CheckHitRateReasonable: {
+		Severity:   "warning",
+		Thresholds: map[string]float64{"min": 0.05, "max": 0.33, "max_cost": 2},
+	},
+	CheckPayoutGaps: {
+		Severity: "warning",
+	},
+	CheckUniquePayouts: {
+		Severity:   "warning",
+		Thresholds: map[string]float64{"min": 10},
+	},
+	CheckSimulationDiversity: {
+		Severity:   "warning",
+		Thresholds: map[string]float64{"max_probability": 0.01},
+	},
+	CheckZeroPayoutRate: {
+		Severity:   "error",
+		Thresholds: map[string]float64{"max": 0.90},
+	},
+	CheckVolatility: {
+		Severity:   "info",
+		Thresholds: map[string]float64{"max": 50},
+	},
+}
+
+// DefaultComplianceProfile returns the built-in profile with all default rules spelled out.
+func DefaultComplianceProfile() *ComplianceProfile {
+	rules := make(map[ComplianceCheckID]ComplianceRule, len(defaultRules))
+	for id, rule := range defaultRules {
+		enabled := true
+		thresholds := make(map[string]float64, len(rule.Thresholds))
+		for k, v := range rule.Thresholds {
+			thresholds[k] = v
+		}
+		rules[id] = ComplianceRule{Enabled: &enabled, Severity: rule.Severity, Thresholds: thresholds}
+	}
+	return &ComplianceProfile{
+		Name:        DefaultComplianceProfileName,
+		Description: "Built-in thresholds",
+		Rules:       rules,
+	}
+}
+
+// Validate checks that the profile only references known checks, severities and
+// thresholds, and that its thresholds are usable: non-negative, with min at most max.
+func (p *ComplianceProfile) Validate() error {
+	if p.Name == "" {
+		return errors.New("profile name is required")
+	}
+	for id, rule := range p.Rules {
+		def, ok := defaultRules[id]
+		if !ok {
+			return fmt.Errorf("profile %q: unknown check %q", p.Name, id)
+		}
+		switch rule.Severity {
+		case "", "error", "warning", "info":
+		default:
+			return fmt.Errorf("profile %q: check %q: invalid severity %q", p.Name, id, rule.Severity)
+		}
+		for key := range rule.Thresholds {
+			if _, ok := def.Thresholds[key]; !ok {
+				return fmt.Errorf("profile %q: check %q: unknown threshold %q", p.Name, id, key)
+			}
+		}
+		if err := p.validateThresholds(id); err != nil {
+			return fmt.Errorf("profile %q: check %q: %w", p.Name, id, err)
+		}
+	}
+	return nil
+}
+
+// validateThresholds checks the effective thresholds of a check, so an
+// override is also checked against the default it is paired with.
+func (p *ComplianceProfile) validateThresholds(id ComplianceCheckID) error {
+	for key := range defaultRules[id].Thresholds {
+		if v := p.Threshold(id, key); math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+			return fmt.Errorf("threshold %q must be a non-negative number, got %v", key, v)
+		}
+	}
+
+	switch id {
+	case CheckRTPRange:
+		if minRTP, maxRTP := p.Threshold(id, "min"), p.Threshold(id, "max"); minRTP > maxRTP {
+			return fmt.Errorf("min %v is greater than max %v", minRTP, maxRTP)
+		}
+	case CheckHitRateReasonable:
+		// The check describes both bounds as "1 in N", so neither may be zero
+		minHitRate, maxHitRate := p.Threshold(id, "min"), p.Threshold(id, "max")
+		if minHitRate <= 0 || maxHitRate > 1 {
+			return fmt.Errorf("min %v and max %v must be within (0, 1]", minHitRate, maxHitRate)
+		}
+		if minHitRate > maxHitRate {
+			return fmt.Errorf("min %v is greater than max %v", minHitRate, maxHitRate)
+		}
+		if p.Threshold(id, "max_cost") <= 0 {
+			return errors.New("threshold \"max_cost\" must be positive")
+		}
+	case CheckMaxWinAchievable:
+		if p.Threshold(id, "max_odds") <= 0 {
+			return errors.New("threshold \"max_odds\" must be positive")
+		}
+	case CheckSimulationDiversity:
+		if v := p.Threshold(id, "max_probability"); v > 1 {
+			return fmt.Errorf("threshold \"max_probability\" must be at most 1, got %v", v)
+		}
+	case CheckZeroPayoutRate:
+		if v := p.Threshold(id, "max"); v > 1 {
+			return fmt.Errorf("threshold \"max\" must be at most 1, got %v", v)
+		}
+	}
+	return nil
+}
+
+// IsEnabled reports whether a check should run under this profile.
+func (p *ComplianceProfile) IsEnabled(id ComplianceCheckID) bool {
+	if rule, ok := p.Rules[id]; ok && rule.Enabled != nil {
+		return *rule.Enabled
+	}
+	return true
+}
+
+// Severity returns the severity for a check, falling back to the default.
+func (p *ComplianceProfile) Severity(id ComplianceCheckID) string {
+	if rule, ok := p.Rules[id]; ok && rule.Severity != "" {
+		return rule.Severity
+	}
+	return defaultRules[id].Severity
+}
+
+// Threshold returns a threshold for a check, falling back to the default.
+func (p *ComplianceProfile) Threshold(id ComplianceCheckID, key string) float64 {
+	if rule, ok := p.Rules[id]; ok {
+		if v, ok := rule.Thresholds[key]; ok {
+			return v
+		}
+	}
+	return defaultRules[id].Thresholds[key]
+}
+
+// DisabledChecks returns the IDs of checks disabled by this profile, sorted.
+func (p *ComplianceProfile) DisabledChecks() []ComplianceCheckID {
+	var disabled []ComplianceCheckID
+	for id := range p.Rules {
+		if !p.IsEnabled(id) {
+			disabled = append(disabled, id)
+		}
+	}
+	sort.Slice(disabled, func(i, j int) bool { return disabled[i] < disabled[j] })
+	return disabled
+}
+
+// LoadComplianceProfiles reads ComplianceProfilesFile from dir and returns all
+// profiles keyed by name, including the built-in default. A missing file is not an error.
+// A file profile named "default" replaces the built-in one.
+func LoadComplianceProfiles(dir string) (map[string]*ComplianceProfile, error) {
+	profiles := map[string]*ComplianceProfile{
+		DefaultComplianceProfileName: DefaultComplianceProfile(),
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ComplianceProfilesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("failed to read compliance profiles: %w", err)
+	}
+
+	var config ComplianceProfilesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse compliance profiles: %w", err)
+	}
+
+	for _, p := range config.Profiles {
+		if p == nil {
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		profiles[p.Name] = p
+	}
+
+	return profiles, nil
+}
+
+// ComplianceProfiles loads the compliance profiles defined for this library.
+// The file is re-read on every call so edits apply without a restart.
+func (l *Loader) ComplianceProfiles() (map[string]*ComplianceProfile, error) {
+	dir := l.libraryDir
+	if dir == "" {
+		dir = l.baseDir
+	}
+	return LoadComplianceProfiles(dir)
+}
+
+// ComplianceProfile returns the named profile, or the default profile if name is empty.
+func (l *Loader) ComplianceProfile(name string) (*ComplianceProfile, error) {
+	profiles, err := l.ComplianceProfiles()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = DefaultComplianceProfileName
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("compliance profile %q not found", name)
+	}
+	return profile, nil
+}
@@ -0,0 +1,92 @@
+package lut
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProfiles writes a compliance profiles file holding the given JSON profiles.
+func writeProfiles(t *testing.T, profiles string) string {
+	t.Helper()
+	dir := t.TempDir()
+	data := `{"profiles":[` + profiles + `]}`
+	if err := os.WriteFile(filepath.Join(dir, ComplianceProfilesFile), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadComplianceProfiles(t *testing.T) {
+	profiles, err := LoadComplianceProfiles(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 1 || profiles[DefaultComplianceProfileName] == nil {
+		t.Fatalf("without a file got %d profiles, want only the default", len(profiles))
+	}
+
+	dir := writeProfiles(t, `
+		{"name":"strict","rules":{"rtp_range":{"severity":"warning","thresholds":{"min":0.92}},"volatility":{"enabled":false}}},
+		{"name":"default","description":"Operator defaults"}`)
+	profiles, err = LoadComplianceProfiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("got %d profiles, want 2", len(profiles))
+	}
+	if profiles[DefaultComplianceProfileName].Description != "Operator defaults" {
+		t.Error("file profile named default did not replace the built-in one")
+	}
+
+	strict := profiles["strict"]
+	if got := strict.Threshold(CheckRTPRange, "min"); got != 0.92 {
+		t.Errorf("overridden min = %v, want 0.92", got)
+	}
+	if got := strict.Threshold(CheckRTPRange, "max"); got != 0.98 {
+		t.Errorf("default max = %v, want 0.98", got)
+	}
+	if strict.Severity(CheckRTPRange) != "warning" || strict.Severity(CheckZeroPayoutRate) != "error" {
+		t.Error("severity override or fallback is wrong")
+	}
+	if disabled := strict.DisabledChecks(); len(disabled) != 1 || disabled[0] != CheckVolatility {
+		t.Errorf("disabled checks = %v, want [volatility]", disabled)
+	}
+}
+
+func TestLoadComplianceProfiles_RejectsInvalidProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    string
+	}{
+		{"missing name", `{"rules":{}}`, "name is required"},
+		{"unknown check", `{"name":"p","rules":{"nope":{}}}`, "unknown check"},
+		{"unknown severity", `{"name":"p","rules":{"rtp_range":{"severity":"fatal"}}}`, "invalid severity"},
+		{"unknown threshold", `{"name":"p","rules":{"rtp_range":{"thresholds":{"avg":0.9}}}}`, "unknown threshold"},
+		{"negative threshold", `{"name":"p","rules":{"volatility":{"thresholds":{"max":-1}}}}`, "non-negative"},
+		{"min above max", `{"name":"p","rules":{"rtp_range":{"thresholds":{"min":0.97,"max":0.95}}}}`, "greater than max"},
+		{"min above default max", `{"name":"p","rules":{"rtp_range":{"thresholds":{"min":0.99}}}}`, "greater than max"},
+		{"hit rate min above max", `{"name":"p","rules":{"hit_rate_reasonable":{"thresholds":{"min":0.5}}}}`, "greater than max"},
+		{"zero hit rate min", `{"name":"p","rules":{"hit_rate_reasonable":{"thresholds":{"min":0}}}}`, "within (0, 1]"},
+		{"hit rate above one", `{"name":"p","rules":{"hit_rate_reasonable":{"thresholds":{"max":1.5}}}}`, "within (0, 1]"},
+		{"zero max odds", `{"name":"p","rules":{"max_win_achievable":{"thresholds":{"max_odds":0}}}}`, "must be positive"},
+		{"probability above one", `{"name":"p","rules":{"zero_payout_rate":{"thresholds":{"max":90}}}}`, "at most 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadComplianceProfiles(writeProfiles(t, tt.profile))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultComplianceProfile_Validates(t *testing.T) {
+	if err := DefaultComplianceProfile().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
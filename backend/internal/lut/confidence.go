@@ -0,0 +1,183 @@
+package lut
+
+import (
+	"math"
+
+	"stakergs"
+)
+
+// Two-sided standard normal quantiles.
+const (
+	z95 = 1.959963984540054
+	z99 = 2.5758293035489004
+)
+
+// DefaultRTPPrecisions are the RTP half-widths SpinsForPrecision is reported
+// for when the config asks for none: ±1, ±0.5 and ±0.1 percentage points.
+var DefaultRTPPrecisions = []float64{0.01, 0.005, 0.001}
+
+// ConfidenceInterval is a two-sided interval at a confidence level.
+type ConfidenceInterval struct {
+	Level float64 `json:"level"` // e.g. 0.95
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+}
+
+// Contains reports whether v lies in the interval.
+func (ci ConfidenceInterval) Contains(v float64) bool {
+	return v >= ci.Low && v <= ci.High
+}
+
+// Estimate is a simulated value with its standard error and confidence intervals.
+type Estimate struct {
+	Value    float64            `json:"value"`
+	StdError float64            `json:"std_error"`
+	CI95     ConfidenceInterval `json:"ci_95"`
+	CI99     ConfidenceInterval `json:"ci_99"`
+}
+
+// SpinsForPrecision is the number of spins after which the simulated RTP is
+// within ±Precision of the theoretical RTP at the given confidence.
+type SpinsForPrecision struct {
+	Precision  float64 `json:"precision"`
+	Confidence float64 `json:"confidence"`
+	Spins      int64   `json:"spins"`
+}
+
+// SimulationDiagnostics compares a simulation against the table's theory.
+type SimulationDiagnostics struct {
+	RTP     Estimate `json:"rtp"`
+	HitRate Estimate `json:"hit_rate"`
+
+	TheoreticalRTP     float64 `json:"theoretical_rtp"`
+	TheoreticalHitRate float64 `json:"theoretical_hit_rate"`
+	SpinStdDev         float64 `json:"spin_std_dev"` // Std dev of one spin's return, in bets
+
+	// 99% ranges the simulated values fall in for this many spins if the
+	// sampler matches the table; values outside are flagged
+	ExpectedRTPRange     ConfidenceInterval `json:"expected_rtp_range"`
+	ExpectedHitRateRange ConfidenceInterval `json:"expected_hit_rate_range"`
+	RTPOutOfRange        bool               `json:"rtp_out_of_range"`
+	HitRateOutOfRange    bool               `json:"hit_rate_out_of_range"`
+
+	SpinsForPrecision []SpinsForPrecision `json:"spins_for_precision"`
+}
+
+// Moments are the theoretical per-spin moments of a table. They match the
+// RTP, hit rate and variance of Analyze but are not rounded, which matters
+// for the narrow intervals of long simulations.
+type Moments struct {
+	MeanPayout float64 // Expected payout as a multiplier
+	Variance   float64 // Payout variance in multiplier units
+	HitRate    float64
+}
+
+// Moments returns the exact per-spin moments of a table.
+func (a *Analyzer) Moments(lut *stakergs.LookupTable) Moments {
+	if len(lut.Outcomes) == 0 || lut.TotalWeight() == 0 {
+		return Moments{}
+	}
+	exact := lut.ExactStats()
+	mean, _ := exact.MeanPayout().Float64()
+	variance, _ := exact.Variance().Float64()
+	hitRate, _ := exact.HitRate().Float64()
+	return Moments{MeanPayout: mean, Variance: variance, HitRate: hitRate}
+}
+
+// normalInterval returns value ± z * stdError.
+func normalInterval(value, stdError, level, z float64) ConfidenceInterval {
+	return ConfidenceInterval{Level: level, Low: value - z*stdError, High: value + z*stdError}
+}
+
+// meanEstimate estimates a mean from n samples with the given sum and sum of
+// squares, using the sample variance.
+func meanEstimate(sum, sumSq float64, n int64) Estimate {
+	if n == 0 {
+		return Estimate{}
+	}
+	mean := sum / float64(n)
+	var stdError float64
+	if n > 1 {
+		variance := math.Max(0, (sumSq-sum*mean)/float64(n-1))
+		stdError = math.Sqrt(variance / float64(n))
+	}
+	return Estimate{
+		Value:    mean,
+		StdError: stdError,
+		CI95:     normalInterval(mean, stdError, 0.95, z95),
+		CI99:     normalInterval(mean, stdError, 0.99, z99),
+	}
+}
+
+// proportionEstimate estimates a proportion from successes out of n trials.
+// Its intervals are Wilson score intervals, which stay inside [0, 1] and
+// remain sound for rare events where the normal approximation breaks down.
+func proportionEstimate(successes, n int64) Estimate {
+	if n == 0 {
+		return Estimate{}
+	}
+	p := float64(successes) / float64(n)
+	return Estimate{
+		Value:    p,
+		StdError: math.Sqrt(p * (1 - p) / float64(n)),
+		CI95:     wilsonInterval(p, n, 0.95, z95),
+		CI99:     wilsonInterval(p, n, 0.99, z99),
+	}
+}
+
+// wilsonInterval returns the Wilson score interval of a proportion p
+// observed over n trials.
+func wilsonInterval(p float64, n int64, level, z float64) ConfidenceInterval {
+	nf := float64(n)
+	z2 := z * z
+	center := (p + z2/(2*nf)) / (1 + z2/nf)
+	half := z / (1 + z2/nf) * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf))
+	return ConfidenceInterval{Level: level, Low: math.Max(0, center-half), High: math.Min(1, center+half)}
+}
+
+// SpinsForRTPPrecision returns the spins needed for the simulated RTP to be
+// within ±precision of the theoretical RTP at 95% confidence, given the
+// standard deviation of one spin's return in bets.
+func SpinsForRTPPrecision(spinStdDev, precision float64) int64 {
+	if precision <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(math.Pow(z95*spinStdDev/precision, 2)))
+}
+
+// newDiagnostics builds the diagnostics of a simulation of spins spins at the
+// given bet from its totals.
+func newDiagnostics(moments Moments, config SimulationConfig, spins int64, won, wonSq float64, hits int64) *SimulationDiagnostics {
+	bet := config.Bet
+	d := &SimulationDiagnostics{
+		RTP:                meanEstimate(won/bet, wonSq/(bet*bet), spins),
+		HitRate:            proportionEstimate(hits, spins),
+		TheoreticalRTP:     moments.MeanPayout / bet,
+		TheoreticalHitRate: moments.HitRate,
+		SpinStdDev:         math.Sqrt(moments.Variance) / bet,
+	}
+
+	if spins > 0 {
+		n := float64(spins)
+		d.ExpectedRTPRange = normalInterval(d.TheoreticalRTP, d.SpinStdDev/math.Sqrt(n), 0.99, z99)
+		p := d.TheoreticalHitRate
+		d.ExpectedHitRateRange = normalInterval(p, math.Sqrt(p*(1-p)/n), 0.99, z99)
+		d.ExpectedHitRateRange.Low = math.Max(0, d.ExpectedHitRateRange.Low)
+		d.ExpectedHitRateRange.High = math.Min(1, d.ExpectedHitRateRange.High)
+		d.RTPOutOfRange = !d.ExpectedRTPRange.Contains(d.RTP.Value)
+		d.HitRateOutOfRange = !d.ExpectedHitRateRange.Contains(d.HitRate.Value)
+	}
+
+	precisions := DefaultRTPPrecisions
+	if config.RTPPrecision > 0 {
+		precisions = []float64{config.RTPPrecision}
+	}
+	for _, precision := range precisions {
+		d.SpinsForPrecision = append(d.SpinsForPrecision, SpinsForPrecision{
+			Precision:  precision,
+			Confidence: 0.95,
+			Spins:      SpinsForRTPPrecision(d.SpinStdDev, precision),
+		})
+	}
+	return d
+}
@@ -0,0 +1,77 @@
+package lut
+
+import (
+	"math"
+	"testing"
+
+	"lutexplorer/internal/sampling"
+	"stakergs"
+)
+
+func TestWilsonInterval_KnownValues(t *testing.T) {
+	ci := wilsonInterval(0.5, 100, 0.95, z95)
+	if math.Abs(ci.Low-0.4038) > 1e-4 || math.Abs(ci.High-0.5962) > 1e-4 {
+		t.Errorf("Wilson 95%% for 50/100 = [%.4f, %.4f], want [0.4038, 0.5962]", ci.Low, ci.High)
+	}
+
+	// No successes still gives a non-empty interval above zero
+	ci = wilsonInterval(0, 1000, 0.95, z95)
+	if ci.Low > 1e-12 || ci.High < 0.003 || ci.High > 0.004 {
+		t.Errorf("Wilson 95%% for 0/1000 = [%g, %g], want [0, ~0.0038]", ci.Low, ci.High)
+	}
+}
+
+func TestSpinsForRTPPrecision(t *testing.T) {
+	if got := SpinsForRTPPrecision(1, 0.01); got != 38415 {
+		t.Errorf("SpinsForRTPPrecision(1, 0.01) = %d, want 38415", got)
+	}
+	// Ten times the precision costs a hundred times the spins
+	if got := SpinsForRTPPrecision(10, 0.01); got != 3841459 {
+		t.Errorf("SpinsForRTPPrecision(10, 0.01) = %d, want 3841459", got)
+	}
+}
+
+func TestRunSimulation_Diagnostics(t *testing.T) {
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 600, Payout: 0},
+		{SimID: 1, Weight: 250, Payout: 50},
+		{SimID: 2, Weight: 100, Payout: 200},
+		{SimID: 3, Weight: 45, Payout: 1000},
+		{SimID: 4, Weight: 5, Payout: 10000},
+	}}
+	simulator := NewSimulator(sampling.NewCache())
+	result := simulator.RunSimulation(table, SimulationConfig{
+		Spins: 2000, Trials: 50, Bet: 1, TargetRTP: 0.97, TestSpins: []int{100}, Seed: 3, RTPPrecision: 0.02,
+	})
+
+	d := result.Diagnostics
+	if d == nil {
+		t.Fatal("expected diagnostics")
+	}
+	// RTP = (250*0.5 + 100*2 + 45*10 + 5*100) / 1000
+	if math.Abs(d.TheoreticalRTP-1.275) > 1e-12 || math.Abs(d.TheoreticalHitRate-0.4) > 1e-12 {
+		t.Errorf("theoretical RTP %g and hit rate %g, want 1.275 and 0.4", d.TheoreticalRTP, d.TheoreticalHitRate)
+	}
+	if d.RTPOutOfRange || d.HitRateOutOfRange {
+		t.Errorf("a correct sampler was flagged: RTP %g in %+v, hit rate %g in %+v",
+			d.RTP.Value, d.ExpectedRTPRange, d.HitRate.Value, d.ExpectedHitRateRange)
+	}
+	if !d.RTP.CI99.Contains(d.RTP.Value) || d.RTP.CI95.High-d.RTP.CI95.Low >= d.RTP.CI99.High-d.RTP.CI99.Low {
+		t.Errorf("expected the 95%% interval inside the 99%% one: %+v, %+v", d.RTP.CI95, d.RTP.CI99)
+	}
+	if len(d.SpinsForPrecision) != 1 || d.SpinsForPrecision[0].Precision != 0.02 {
+		t.Errorf("unexpected spins for precision %+v", d.SpinsForPrecision)
+	}
+	if ci := result.RTPatSpins[0].CI95; !ci.Contains(result.RTPatSpins[0].SuccessRate) {
+		t.Errorf("success rate %g outside its interval %+v", result.RTPatSpins[0].SuccessRate, ci)
+	}
+
+	// A run paying a tenth more than the table allows is flagged
+	moments := NewAnalyzer().Moments(table)
+	spins := int64(100_000)
+	won := 1.1 * moments.MeanPayout * float64(spins)
+	wonSq := (moments.Variance + 1.21*moments.MeanPayout*moments.MeanPayout) * float64(spins)
+	if off := newDiagnostics(moments, SimulationConfig{Bet: 1}, spins, won, wonSq, 40_000); !off.RTPOutOfRange || off.HitRateOutOfRange {
+		t.Errorf("expected only the RTP to be flagged, got RTP %v, hit rate %v", off.RTPOutOfRange, off.HitRateOutOfRange)
+	}
+}
@@ -0,0 +1,387 @@
+package lut
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"stakergs"
+)
+
+// DefaultDiffWeightChanges is the default number of per-sim_id changes reported per mode.
+const DefaultDiffWeightChanges = 100
+
+// DiffOptions controls how much detail a diff reports.
+type DiffOptions struct {
+	Modes            []string `json:"modes,omitempty"`    // Modes to compare in DiffLibraries (empty = all)
+	MaxWeightChanges int      `json:"max_weight_changes"` // Largest changes to list per mode (0 = default)
+}
+
+// includes reports whether mode is selected by the options.
+func (o DiffOptions) includes(mode string) bool {
+	if len(o.Modes) == 0 {
+		return true
+	}
+	for _, m := range o.Modes {
+		if strings.EqualFold(m, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// MetricDelta holds a metric for both versions and the change between them.
+type MetricDelta struct {
+	Base   float64 `json:"base"`
+	Target float64 `json:"target"`
+	Delta  float64 `json:"delta"` // Target - Base
+}
+
+// BucketDelta holds the probability of a payout range in both versions.
+type BucketDelta struct {
+	RangeStart        float64 `json:"range_start"`
+	RangeEnd          float64 `json:"range_end"`
+	BaseProbability   float64 `json:"base_probability"`
+	TargetProbability float64 `json:"target_probability"`
+	Delta             float64 `json:"delta"`
+}
+
+// WeightChange describes a sim_id whose weight or payout differs between versions.
+// Missing sim_ids have zero weight on that side.
+type WeightChange struct {
+	SimID             int     `json:"sim_id"`
+	BaseWeight        uint64  `json:"base_weight"`
+	TargetWeight      uint64  `json:"target_weight"`
+	BasePayout        float64 `json:"base_payout"`
+	TargetPayout      float64 `json:"target_payout"`
+	BaseProbability   float64 `json:"base_probability"`
+	TargetProbability float64 `json:"target_probability"`
+	ProbabilityDelta  float64 `json:"probability_delta"`
+}
+
+// ModeDiff contains the differences between two versions of a mode's table.
+type ModeDiff struct {
+	Mode           string         `json:"mode"`
+	Changed        bool           `json:"changed"`
+	CostChanged    bool           `json:"cost_changed"`
+	RTP            MetricDelta    `json:"rtp"`
+	HitRate        MetricDelta    `json:"hit_rate"`
+	Volatility     MetricDelta    `json:"volatility"`
+	MaxPayout      MetricDelta    `json:"max_payout"`
+	MaxWinOdds     MetricDelta    `json:"max_win_odds"` // 1 in N for the max payout
+	Buckets        []BucketDelta  `json:"buckets"`
+	WeightChanges  []WeightChange `json:"weight_changes"`
+	ChangedSimIDs  int            `json:"changed_sim_ids"` // Total sim_ids with a different weight or payout
+	AddedSimIDs    int            `json:"added_sim_ids"`
+	RemovedSimIDs  int            `json:"removed_sim_ids"`
+	ChangesTrimmed bool           `json:"changes_trimmed"` // WeightChanges lists only the largest changes
+}
+
+// LibraryDiff contains the differences between two libraries.
+type LibraryDiff struct {
+	Base         string      `json:"base"`
+	Target       string      `json:"target"`
+	Modes        []*ModeDiff `json:"modes"`
+	AddedModes   []string    `json:"added_modes,omitempty"`
+	RemovedModes []string    `json:"removed_modes,omitempty"`
+}
+
+// DiffTables compares two versions of a lookup table.
+func DiffTables(base, target *stakergs.LookupTable, opts DiffOptions) *ModeDiff {
+	limit := opts.MaxWeightChanges
+	if limit <= 0 {
+		limit = DefaultDiffWeightChanges
+	}
+
+	analyzer := NewAnalyzer()
+	baseStats := analyzer.Analyze(base)
+	targetStats := analyzer.Analyze(target)
+
+	diff := &ModeDiff{
+		Mode:        target.Mode,
+		CostChanged: base.Cost != target.Cost,
+		RTP:         newMetricDelta(baseStats.RTP, targetStats.RTP),
+		HitRate:     newMetricDelta(baseStats.HitRate, targetStats.HitRate),
+		Volatility:  newMetricDelta(baseStats.Volatility, targetStats.Volatility),
+		MaxPayout:   newMetricDelta(baseStats.MaxPayout, targetStats.MaxPayout),
+		MaxWinOdds:  newMetricDelta(maxWinOdds(base), maxWinOdds(target)),
+	}
+
+	diff.Buckets = diffBuckets(base, target)
+	diff.WeightChanges = diffWeights(base, target, diff)
+
+	if len(diff.WeightChanges) > limit {
+		diff.WeightChanges = diff.WeightChanges[:limit]
+		diff.ChangesTrimmed = true
+	}
+	diff.Changed = diff.ChangedSimIDs > 0 || diff.CostChanged
+
+	return diff
+}
+
+// DiffLibraries compares every mode of two loaded libraries.
+// Modes are matched by name, case-insensitively.
+func DiffLibraries(base, target *Loader, opts DiffOptions) *LibraryDiff {
+	result := &LibraryDiff{
+		Base:   base.LibraryDir(),
+		Target: target.LibraryDir(),
+		Modes:  make([]*ModeDiff, 0),
+	}
+
+	for _, mode := range target.ListModes() {
+		if !opts.includes(mode) {
+			continue
+		}
+		targetTable, err := target.GetMode(mode)
+		if err != nil {
+			continue
+		}
+		baseTable, err := base.GetMode(mode)
+		if err != nil {
+			result.AddedModes = append(result.AddedModes, mode)
+			continue
+		}
+		result.Modes = append(result.Modes, DiffTables(baseTable, targetTable, opts))
+	}
+
+	for _, mode := range base.ListModes() {
+		if !opts.includes(mode) {
+			continue
+		}
+		if _, err := target.GetMode(mode); err != nil {
+			result.RemovedModes = append(result.RemovedModes, mode)
+		}
+	}
+
+	return result
+}
+
+// DiffBackup compares a backup CSV (as written by SaveWeightsWithBackup) against
+// the currently loaded table for mode. The backup is the base version.
+func (l *Loader) DiffBackup(mode, backup string, opts DiffOptions) (*ModeDiff, error) {
+	table, err := l.GetMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	config, err := l.GetModeConfig(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	backupPath, err := l.resolveBackup(config, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := ReadLUTCSV(backupPath, *config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backup: %w", err)
+	}
+
+	return DiffTables(base, table, opts), nil
+}
+
+// Backups returns the backup file names for a mode, newest first.
+func (l *Loader) Backups(mode string) ([]string, error) {
+	config, err := l.GetModeConfig(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := filepath.Join(l.baseDir, config.Weights) + ".*.bak"
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, filepath.Base(m))
+	}
+	// Timestamps are YYYYMMDD_HHMMSS, so lexical order is chronological
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, nil
+}
+
+// resolveBackup maps a backup name to a path inside the publish folder.
+// "latest" or an empty name selects the newest backup.
+func (l *Loader) resolveBackup(config *stakergs.ModeConfig, backup string) (string, error) {
+	if backup == "" || backup == "latest" {
+		backups, err := l.Backups(config.Name)
+		if err != nil {
+			return "", err
+		}
+		if len(backups) == 0 {
+			return "", fmt.Errorf("no backups found for mode %q", config.Name)
+		}
+		backup = backups[0]
+	}
+
+	// Only accept backups of this mode's CSV, never arbitrary paths
+	name := filepath.Base(backup)
+	prefix := filepath.Base(config.Weights) + "."
+	if name != backup || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".bak") {
+		return "", fmt.Errorf("invalid backup %q for mode %q", backup, config.Name)
+	}
+
+	path := filepath.Join(l.baseDir, filepath.Dir(config.Weights), name)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("backup %q not found", backup)
+	}
+	return path, nil
+}
+
+// ResolveLibrary maps a library name to a folder next to this loader's
+// library, so libraries kept side by side (e.g. game_v1 and game_v2) can be
+// compared. Only bare folder names are accepted, never arbitrary paths.
+func (l *Loader) ResolveLibrary(name string) (string, error) {
+	if l.libraryDir == "" {
+		return "", fmt.Errorf("library %q cannot be resolved without a library folder", name)
+	}
+	if name == "" || name == "." || name == ".." || name != filepath.Base(name) {
+		return "", fmt.Errorf("invalid library %q", name)
+	}
+
+	root, err := filepath.Abs(filepath.Dir(l.libraryDir))
+	if err != nil {
+		return "", fmt.Errorf("invalid library folder: %w", err)
+	}
+	path := filepath.Join(root, name)
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("library %q not found", name)
+	}
+	return path, nil
+}
+
+func newMetricDelta(base, target float64) MetricDelta {
+	return MetricDelta{Base: base, Target: target, Delta: round4(target - base)}
+}
+
+// maxWinOdds returns N where the max payout hits 1 in N, or 0 for an empty table.
+func maxWinOdds(lut *stakergs.LookupTable) float64 {
+	maxPayout := lut.MaxPayout()
+	var maxWeight uint64
+	for _, o := range lut.Outcomes {
+		if o.Payout == maxPayout {
+			maxWeight += o.Weight
+		}
+	}
+	if maxWeight == 0 {
+		return 0
+	}
+	return round2(float64(lut.TotalWeight()) / float64(maxWeight))
+}
+
+// diffBuckets compares bucket probabilities using a shared set of ranges
+// sized for the larger max payout of the two tables.
+func diffBuckets(base, target *stakergs.LookupTable) []BucketDelta {
+	maxPayout := math.Max(float64(base.MaxPayout()), float64(target.MaxPayout())) / 100.0
+	if maxPayout == 0 {
+		return nil
+	}
+
+	baseBuckets := payoutBucketRanges(maxPayout)
+	targetBuckets := payoutBucketRanges(maxPayout)
+	for _, o := range base.Outcomes {
+		if i := findPayoutBucket(baseBuckets, float64(o.Payout)/100.0); i >= 0 {
+			baseBuckets[i].Weight += o.Weight
+		}
+	}
+	for _, o := range target.Outcomes {
+		if i := findPayoutBucket(targetBuckets, float64(o.Payout)/100.0); i >= 0 {
+			targetBuckets[i].Weight += o.Weight
+		}
+	}
+
+	baseTotal := float64(base.TotalWeight())
+	targetTotal := float64(target.TotalWeight())
+
+	deltas := make([]BucketDelta, 0, len(baseBuckets))
+	for i := range baseBuckets {
+		if baseBuckets[i].Weight == 0 && targetBuckets[i].Weight == 0 {
+			continue
+		}
+		d := BucketDelta{
+			RangeStart: baseBuckets[i].RangeStart,
+			RangeEnd:   baseBuckets[i].RangeEnd,
+		}
+		if baseTotal > 0 {
+			d.BaseProbability = float64(baseBuckets[i].Weight) / baseTotal
+		}
+		if targetTotal > 0 {
+			d.TargetProbability = float64(targetBuckets[i].Weight) / targetTotal
+		}
+		d.Delta = d.TargetProbability - d.BaseProbability
+		deltas = append(deltas, d)
+	}
+	return deltas
+}
+
+// diffWeights matches outcomes by sim_id and returns every changed sim_id,
+// largest probability change first. Counters on diff are updated.
+func diffWeights(base, target *stakergs.LookupTable, diff *ModeDiff) []WeightChange {
+	baseTotal := float64(base.TotalWeight())
+	targetTotal := float64(target.TotalWeight())
+
+	baseBySimID := make(map[int]stakergs.Outcome, len(base.Outcomes))
+	for _, o := range base.Outcomes {
+		baseBySimID[o.SimID] = o
+	}
+
+	var changes []WeightChange
+	seen := make(map[int]struct{}, len(target.Outcomes))
+
+	for _, t := range target.Outcomes {
+		seen[t.SimID] = struct{}{}
+		b, ok := baseBySimID[t.SimID]
+		if ok && b.Weight == t.Weight && b.Payout == t.Payout {
+			continue
+		}
+		if !ok {
+			diff.AddedSimIDs++
+		}
+		changes = append(changes, newWeightChange(t.SimID, b, t, baseTotal, targetTotal))
+	}
+
+	for _, b := range base.Outcomes {
+		if _, ok := seen[b.SimID]; ok {
+			continue
+		}
+		diff.RemovedSimIDs++
+		changes = append(changes, newWeightChange(b.SimID, b, stakergs.Outcome{}, baseTotal, targetTotal))
+	}
+
+	diff.ChangedSimIDs = len(changes)
+
+	sort.Slice(changes, func(i, j int) bool {
+		di := math.Abs(changes[i].ProbabilityDelta)
+		dj := math.Abs(changes[j].ProbabilityDelta)
+		if di != dj {
+			return di > dj
+		}
+		return changes[i].SimID < changes[j].SimID
+	})
+
+	return changes
+}
+
+func newWeightChange(simID int, base, target stakergs.Outcome, baseTotal, targetTotal float64) WeightChange {
+	c := WeightChange{
+		SimID:        simID,
+		BaseWeight:   base.Weight,
+		TargetWeight: target.Weight,
+		BasePayout:   float64(base.Payout) / 100.0,
+		TargetPayout: float64(target.Payout) / 100.0,
+	}
+	if baseTotal > 0 {
+		c.BaseProbability = float64(base.Weight) / baseTotal
+	}
+	if targetTotal > 0 {
+		c.TargetProbability = float64(target.Weight) / targetTotal
+	}
+	c.ProbabilityDelta = c.TargetProbability - c.BaseProbability
+	return c
+}
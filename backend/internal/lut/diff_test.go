@@ -0,0 +1,38 @@
+package lut
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveLibrary(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"game_v1", "game_v2"} {
+		if err := os.Mkdir(filepath.Join(root, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	loader := NewLoaderFromLibrary(filepath.Join(root, "game_v2"))
+
+	path, err := loader.ResolveLibrary("game_v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(root, "game_v1") {
+		t.Errorf("resolved to %q", path)
+	}
+
+	for _, name := range []string{"", ".", "..", "missing", "notes.txt", "../game_v1", "game_v1/publish_files", root} {
+		if _, err := loader.ResolveLibrary(name); err == nil {
+			t.Errorf("expected an error for %q", name)
+		}
+	}
+
+	if _, err := NewLoader(filepath.Join(root, "game_v2", "index.json")).ResolveLibrary("game_v1"); err == nil {
+		t.Error("expected an error for a loader without a library folder")
+	}
+}
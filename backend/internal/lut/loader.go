@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"lutexplorer/internal/sampling"
 	"stakergs"
 )
 
@@ -24,19 +25,23 @@ type Loader struct {
 	eventsLoader      *EventsLoader
 	simulator         *Simulator
 	distributionCache *DistributionCache
+	samplers          *sampling.Cache
+	winTiers          *WinTiers
 }
 
 // NewLoader creates a new LUT loader for the given index file path.
 func NewLoader(indexPath string) *Loader {
 	baseDir := filepath.Dir(indexPath)
+	samplers := sampling.NewCache()
 	return &Loader{
 		indexPath:         indexPath,
 		baseDir:           baseDir,
 		tables:            make(map[string]*stakergs.LookupTable),
 		analyzer:          NewAnalyzer(),
 		eventsLoader:      NewEventsLoader(baseDir),
-		simulator:         NewSimulator(),
 		distributionCache: NewDistributionCache(),
+		samplers:          samplers,
+		simulator:         NewSimulator(samplers),
 	}
 }
 
@@ -45,6 +50,7 @@ func NewLoader(indexPath string) *Loader {
 func NewLoaderFromLibrary(libraryPath string) *Loader {
 	publishFilesDir := filepath.Join(libraryPath, "publish_files")
 	indexPath := filepath.Join(publishFilesDir, "index.json")
+	samplers := sampling.NewCache()
 	return &Loader{
 		indexPath:         indexPath,
 		baseDir:           publishFilesDir,
@@ -52,8 +58,9 @@ func NewLoaderFromLibrary(libraryPath string) *Loader {
 		tables:            make(map[string]*stakergs.LookupTable),
 		analyzer:          NewAnalyzer(),
 		eventsLoader:      NewEventsLoader(publishFilesDir),
-		simulator:         NewSimulator(),
 		distributionCache: NewDistributionCache(),
+		samplers:          samplers,
+		simulator:         NewSimulator(samplers),
 	}
 }
 
@@ -88,6 +95,19 @@ func (l *Loader) Load() error {
 
 	l.index = &index
 
+	// Win tiers live in the library folder, next to the other game config
+	tiersDir := l.libraryDir
+	if tiersDir == "" {
+		tiersDir = l.baseDir
+	}
+	tiers, err := LoadWinTiers(tiersDir)
+	if err != nil {
+		return err
+	}
+	l.winTiers = tiers
+	l.analyzer = l.analyzer.WithWinTiers(tiers)
+	l.simulator = l.simulator.WithWinTiers(tiers)
+
 	// Load all LUT CSV files
 	for _, mode := range index.Modes {
 		table, err := l.loadCSV(mode)
@@ -100,11 +120,14 @@ func (l *Loader) Load() error {
 	return nil
 }
 
-// loadCSV reads a LUT CSV file and returns a LookupTable.
-// CSV format: sim_id,weight,payout (no header)
+// loadCSV reads the LUT CSV file referenced by a mode config.
 func (l *Loader) loadCSV(mode stakergs.ModeConfig) (*stakergs.LookupTable, error) {
-	csvPath := filepath.Join(l.baseDir, mode.Weights)
+	return ReadLUTCSV(filepath.Join(l.baseDir, mode.Weights), mode)
+}
 
+// ReadLUTCSV reads a LUT CSV file and returns a LookupTable with the mode's name and cost.
+// CSV format: sim_id,weight,payout (no header)
+func ReadLUTCSV(csvPath string, mode stakergs.ModeConfig) (*stakergs.LookupTable, error) {
 	file, err := os.Open(csvPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open CSV: %w", err)
@@ -165,12 +188,30 @@ func (l *Loader) loadCSV(mode stakergs.ModeConfig) (*stakergs.LookupTable, error
 		}
 	}
 
-	return &stakergs.LookupTable{
+	table := &stakergs.LookupTable{
 		Outcomes:    outcomes,
 		Mode:        mode.Name,
 		Cost:        mode.Cost,
 		SimIDOffset: simIDOffset,
-	}, nil
+	}
+	if err := checkTotalWeight(table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// checkTotalWeight rejects tables whose total weight wraps or is zero, since
+// every probability downstream is weight / TotalWeight() and the samplers
+// need an outcome to draw.
+func checkTotalWeight(table *stakergs.LookupTable) error {
+	total, err := table.CheckedTotalWeight()
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return stakergs.ErrZeroTotalWeight
+	}
+	return nil
 }
 
 // GetIndex returns the loaded game index.
@@ -223,6 +264,12 @@ func (l *Loader) DistributionCache() *DistributionCache {
 	return l.distributionCache
 }
 
+// Sampler returns the cached sampler for a mode's table with the given payout
+// bias (0 for none). Samplers are rebuilt when the mode's weights change.
+func (l *Loader) Sampler(table *stakergs.LookupTable, bias float64) *sampling.Sampler {
+	return l.samplers.Get(table, bias)
+}
+
 // ModeSummary contains basic info about a mode.
 type ModeSummary struct {
 	Mode      string  `json:"mode"`
@@ -327,8 +374,9 @@ func (l *Loader) Reload() error {
 	// Clear events cache first
 	l.eventsLoader.ClearAll()
 
-	// Clear distribution cache
+	// Clear distribution and sampler caches
 	l.distributionCache.InvalidateAll()
+	l.samplers.InvalidateAll()
 
 	// Clear tables
 	l.tables = make(map[string]*stakergs.LookupTable)
@@ -338,7 +386,7 @@ func (l *Loader) Reload() error {
 }
 
 // ReloadModeTable reloads just the lookup table for a specific mode from disk.
-// This updates the in-memory table and invalidates the distribution cache for that mode.
+// This updates the in-memory table and invalidates the distribution and sampler caches for that mode.
 func (l *Loader) ReloadModeTable(modeName string) error {
 	config, err := l.GetModeConfig(modeName)
 	if err != nil {
@@ -352,6 +400,7 @@ func (l *Loader) ReloadModeTable(modeName string) error {
 
 	l.tables[modeName] = table
 	l.distributionCache.Invalidate(modeName)
+	l.samplers.Invalidate(modeName)
 
 	return nil
 }
@@ -385,6 +434,15 @@ func (l *Loader) SaveWeights(mode string, weights []uint64) error {
 		return fmt.Errorf("weight count mismatch: got %d, expected %d", len(weights), len(table.Outcomes))
 	}
 
+	// Refuse to write a table that loadCSV would reject
+	updated := &stakergs.LookupTable{Outcomes: make([]stakergs.Outcome, len(weights))}
+	for i, w := range weights {
+		updated.Outcomes[i] = stakergs.Outcome{Weight: w}
+	}
+	if err := checkTotalWeight(updated); err != nil {
+		return err
+	}
+
 	// Get the mode config to find the CSV path
 	config, err := l.GetModeConfig(mode)
 	if err != nil {
@@ -434,8 +492,9 @@ func (l *Loader) SaveWeights(mode string, weights []uint64) error {
 		table.Outcomes[i].Weight = weights[i]
 	}
 
-	// Invalidate distribution cache for this mode
+	// Invalidate distribution and sampler caches for this mode
 	l.distributionCache.Invalidate(mode)
+	l.samplers.Invalidate(mode)
 
 	return nil
 }
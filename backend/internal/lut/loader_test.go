@@ -0,0 +1,66 @@
+package lut
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"lutexplorer/internal/testutil"
+	"stakergs"
+)
+
+func TestReadLUTCSV_RejectsUnusableTotalWeight(t *testing.T) {
+	maxWeight := strconv.FormatUint(math.MaxUint64, 10)
+	tests := []struct {
+		name string
+		csv  string
+		want error
+	}{
+		{"overflow", "0," + maxWeight + ",0\n1,1,200\n", stakergs.ErrWeightOverflow},
+		{"zero", "0,0,0\n1,0,200\n", stakergs.ErrZeroTotalWeight},
+		{"empty", "\n", stakergs.ErrZeroTotalWeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lut.csv")
+			if err := os.WriteFile(path, []byte(tt.csv), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadLUTCSV(path, stakergs.ModeConfig{Name: "base"}); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// The largest total that fits is accepted
+	path := filepath.Join(t.TempDir(), "lut.csv")
+	if err := os.WriteFile(path, []byte("0,"+strconv.FormatUint(math.MaxUint64-1, 10)+",0\n1,1,200\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadLUTCSV(path, stakergs.ModeConfig{Name: "base"}); err != nil {
+		t.Errorf("total of MaxUint64: %v", err)
+	}
+}
+
+func TestSaveWeights_RejectsUnusableTotalWeight(t *testing.T) {
+	loader := NewLoaderFromLibrary(testutil.WriteLibrary(t, testutil.Mode{Name: "base", CSV: "0,10,0\n1,5,200\n"}))
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loader.SaveWeights("base", []uint64{math.MaxUint64, 1}); !errors.Is(err, stakergs.ErrWeightOverflow) {
+		t.Errorf("overflowing weights: got %v, want ErrWeightOverflow", err)
+	}
+	if err := loader.SaveWeights("base", []uint64{0, 0}); !errors.Is(err, stakergs.ErrZeroTotalWeight) {
+		t.Errorf("zero weights: got %v, want ErrZeroTotalWeight", err)
+	}
+
+	// Rejected weights leave the table untouched
+	table, _ := loader.GetMode("base")
+	if table.Outcomes[0].Weight != 10 || table.Outcomes[1].Weight != 5 {
+		t.Errorf("weights changed to %d and %d", table.Outcomes[0].Weight, table.Outcomes[1].Weight)
+	}
+}
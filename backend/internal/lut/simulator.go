@@ -1,36 +1,55 @@
 package lut
 
 import (
-	"math"
+	"context"
 	"math/rand"
+	"runtime"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/sampling"
 	"stakergs"
 )
 
 // Simulator handles weighted random spin simulations.
+//
+// Every run is driven by a seed, taken from the config or picked at random and
+// echoed back in the result, so any run can be reproduced exactly.
 type Simulator struct {
-	rng *rand.Rand
-	mu  sync.Mutex
+	samplers *sampling.Cache
+	winTiers *WinTiers
 }
 
-// NewSimulator creates a new simulator with a seeded RNG.
-func NewSimulator() *Simulator {
-	return &Simulator{
-		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
-	}
+// NewSimulator creates a new simulator drawing from the given sampler cache,
+// classifying wins with the default win tiers.
+func NewSimulator(samplers *sampling.Cache) *Simulator {
+	return &Simulator{samplers: samplers, winTiers: DefaultWinTiers()}
+}
+
+// WithWinTiers returns a Simulator that classifies wins with tiers.
+func (s *Simulator) WithWinTiers(tiers *WinTiers) *Simulator {
+	return &Simulator{samplers: s.samplers, winTiers: tiers}
 }
 
 // SimulationConfig holds parameters for a simulation run.
 type SimulationConfig struct {
-	Spins       int       `json:"spins"`        // Number of spins per trial
-	Trials      int       `json:"trials"`       // Number of trials to run
-	Bet         float64   `json:"bet"`          // Bet amount per spin
-	TargetRTP   float64   `json:"target_rtp"`   // Target RTP threshold (e.g., 0.97)
-	TestSpins   []int     `json:"test_spins"`   // Spin counts to test RTP at
-	TestWeights []float64 `json:"test_weights"` // Weights for each test spin (for scoring)
+	Spins       int       `json:"spins"`             // Number of spins per trial
+	Trials      int       `json:"trials"`            // Number of trials to run
+	Bet         float64   `json:"bet"`               // Bet amount per spin
+	TargetRTP   float64   `json:"target_rtp"`        // Target RTP threshold (e.g., 0.97)
+	TestSpins   []int     `json:"test_spins"`        // Spin counts to test RTP at
+	TestWeights []float64 `json:"test_weights"`      // Weights for each test spin (for scoring)
+	Seed        int64     `json:"seed"`              // RNG seed; 0 picks a random seed, which the result reports
+	Workers     int       `json:"workers,omitempty"` // Goroutines running trials; 0 uses runtime.NumCPU()
+
+	// RTP half-width to report the spins needed for; 0 reports DefaultRTPPrecisions
+	RTPPrecision float64 `json:"rtp_precision,omitempty"`
+
+	// Report every trial's summary; by default only runs of up to 100 trials do
+	AllTrialSummaries bool `json:"all_trial_summaries,omitempty"`
 }
 
 // SimulationResult holds the results of a simulation run.
@@ -43,14 +62,18 @@ type SimulationResult struct {
 	ActualRTP      float64         `json:"actual_rtp"`
 	HitCount       int             `json:"hit_count"`
 	HitRate        float64         `json:"hit_rate"`
-	BigWins        int             `json:"big_wins"`    // wins >= 10x
-	MegaWins       int             `json:"mega_wins"`   // wins >= 50x
+	BigWins        int             `json:"big_wins"`    // wins >= 10x (common.BigWinMultiplier), whatever the win tiers
+	MegaWins       int             `json:"mega_wins"`   // wins >= 50x (common.MegaWinMultiplier), whatever the win tiers
+	WinTiers       []WinTierCount  `json:"win_tiers"`   // wins per game win tier
 	MaxWin         float64         `json:"max_win"`
 	SpinResults    []SpinResult    `json:"spin_results,omitempty"`
 	TrialSummaries []TrialSummary  `json:"trial_summaries,omitempty"`
 	RTPatSpins     []RTPAtSpin     `json:"rtp_at_spins,omitempty"`
 	FinalScore     float64         `json:"final_score,omitempty"`
 	DurationMs     int64           `json:"duration_ms"`
+
+	// Standard errors, confidence intervals and a check against the table's theory
+	Diagnostics *SimulationDiagnostics `json:"diagnostics,omitempty"`
 }
 
 // SpinResult holds the result of a single spin.
@@ -74,207 +97,76 @@ type TrialSummary struct {
 
 // RTPAtSpin holds RTP success rate at a specific spin count.
 type RTPAtSpin struct {
-	SpinCount   int     `json:"spin_count"`
-	SuccessRate float64 `json:"success_rate"`
-	Weight      float64 `json:"weight,omitempty"`
-}
-
-// WeightedSampler provides efficient weighted random sampling.
-type WeightedSampler struct {
-	outcomes    []stakergs.Outcome
-	cumWeights  []uint64
-	totalWeight uint64
+	SpinCount   int                `json:"spin_count"`
+	SuccessRate float64            `json:"success_rate"`
+	CI95        ConfidenceInterval `json:"ci_95"` // Wilson interval of the success rate over trials
+	Weight      float64            `json:"weight,omitempty"`
 }
 
-// NewWeightedSampler creates a sampler from a lookup table.
-func NewWeightedSampler(lut *stakergs.LookupTable) *WeightedSampler {
-	ws := &WeightedSampler{
-		outcomes:   lut.Outcomes,
-		cumWeights: make([]uint64, len(lut.Outcomes)),
-	}
-
-	var cumulative uint64
-	for i, o := range lut.Outcomes {
-		cumulative += o.Weight
-		ws.cumWeights[i] = cumulative
-	}
-	ws.totalWeight = cumulative
-
-	return ws
-}
-
-// Sample returns a random outcome based on weights.
-func (ws *WeightedSampler) Sample(rng *rand.Rand) stakergs.Outcome {
-	// Generate random value in [0, totalWeight)
-	r := rng.Uint64() % ws.totalWeight
-
-	// Binary search for the outcome
-	idx := sort.Search(len(ws.cumWeights), func(i int) bool {
-		return ws.cumWeights[i] > r
-	})
-
-	return ws.outcomes[idx]
-}
-
-// SampleWithNewRNG returns a random outcome using a fresh RNG.
-func (ws *WeightedSampler) SampleWithNewRNG() stakergs.Outcome {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	r := rng.Uint64() % ws.totalWeight
-
-	// Binary search for the outcome
-	idx := sort.Search(len(ws.cumWeights), func(i int) bool {
-		return ws.cumWeights[i] > r
-	})
-
-	return ws.outcomes[idx]
-}
-
-// BiasedWeightedSampler provides weighted sampling with a payout bias.
-// Bias > 0 increases probability of higher payouts.
-type BiasedWeightedSampler struct {
-	outcomes    []stakergs.Outcome
-	cumWeights  []float64
-	totalWeight float64
-}
-
-// NewBiasedWeightedSampler creates a sampler with payout-based bias.
-// bias = 0: normal sampling
-// bias > 0: favors higher payouts AND reduces zero payouts (losses)
-// bias < 0: favors lower payouts AND increases zero payouts (losses)
-//
-// Formula:
-//   - For payout = 0: weight * 0.5^bias (positive bias reduces losses)
-//   - For payout > 0: weight * (1 + payout/100)^bias
-//
-// Examples with bias = +5:
-//   - 0x payout: 0.5^5 = 0.03 (97% fewer losses!)
-//   - 1x payout: 2^5 = 32x more likely
-//   - 10x payout: 11^5 = 161051x more likely
-func NewBiasedWeightedSampler(lut *stakergs.LookupTable, bias float64) *BiasedWeightedSampler {
-	bws := &BiasedWeightedSampler{
-		outcomes:   lut.Outcomes,
-		cumWeights: make([]float64, len(lut.Outcomes)),
-	}
-
-	var cumulative float64
-	for i, o := range lut.Outcomes {
-		var biasedWeight float64
-		if o.Payout == 0 {
-			// For zero payouts (losses): use 0.5 as base
-			// Positive bias reduces losses, negative bias increases them
-			biasedWeight = float64(o.Weight) * math.Pow(0.5, bias)
-		} else {
-			// For winning payouts: use (1 + payout/100) as base
-			payoutMultiplier := 1.0 + float64(o.Payout)/100.0
-			biasedWeight = float64(o.Weight) * math.Pow(payoutMultiplier, bias)
-		}
-		cumulative += biasedWeight
-		bws.cumWeights[i] = cumulative
-	}
-	bws.totalWeight = cumulative
-
-	return bws
+// RunSimulation executes a full simulation with multiple trials.
+func (s *Simulator) RunSimulation(lut *stakergs.LookupTable, config SimulationConfig) *SimulationResult {
+	result, _ := s.RunSimulationContext(context.Background(), lut, config, nil)
+	return result
 }
 
-// SampleWithNewRNG returns a random outcome using a fresh RNG.
-func (bws *BiasedWeightedSampler) SampleWithNewRNG() stakergs.Outcome {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	r := rng.Float64() * bws.totalWeight
+// RunSimulationContext executes a full simulation, calling progress (if not
+// nil) after each trial. It stops between trials once ctx is cancelled and
+// returns ctx.Err().
+func (s *Simulator) RunSimulationContext(ctx context.Context, lut *stakergs.LookupTable, config SimulationConfig, progress func(trialsDone, trials int)) (*SimulationResult, error) {
+	start := time.Now()
 
-	// Binary search for the outcome
-	idx := sort.Search(len(bws.cumWeights), func(i int) bool {
-		return bws.cumWeights[i] > r
-	})
+	sampler := s.samplers.Get(lut, 0)
 
-	if idx >= len(bws.outcomes) {
-		idx = len(bws.outcomes) - 1
+	if config.Seed == 0 {
+		config.Seed = common.NewSeed()
 	}
 
-	return bws.outcomes[idx]
-}
-
-// RunSimulation executes a full simulation with multiple trials.
-func (s *Simulator) RunSimulation(lut *stakergs.LookupTable, config SimulationConfig) *SimulationResult {
-	start := time.Now()
-
-	sampler := NewWeightedSampler(lut)
-
 	result := &SimulationResult{
 		Mode:   lut.Mode,
 		Config: config,
 	}
 
-	// Initialize test spin success counters
-	testSpinSuccess := make([]int, len(config.TestSpins))
+	trials, err := s.runTrials(ctx, sampler, s.newWinCounter(lut), config, progress)
+	if err != nil {
+		return nil, err
+	}
 
+	// Aggregate in trial order so float sums do not depend on scheduling
+	testSpinSuccess := make([]int, len(config.TestSpins))
 	var totalWon float64
+	var totalWonSq float64
 	var totalHits int
 	var totalBigWins int
 	var totalMegaWins int
+	tierCounts := make([]int, len(s.winTiers.Tiers))
 	var maxWin float64
 
 	trialSummaries := make([]TrialSummary, config.Trials)
-
-	// Run all trials
-	for trial := 0; trial < config.Trials; trial++ {
-		s.mu.Lock()
-		trialRNG := rand.New(rand.NewSource(s.rng.Int63()))
-		s.mu.Unlock()
-
-		var trialWon float64
-		var trialHits int
-		var trialMaxWin float64
-		spinPayouts := make([]float64, config.Spins)
-
-		// Run spins for this trial
-		for spin := 0; spin < config.Spins; spin++ {
-			outcome := sampler.Sample(trialRNG)
-			payout := float64(outcome.Payout) / 100.0
-
-			spinPayouts[spin] = payout
-			trialWon += payout
-
-			if payout > 0 {
-				trialHits++
-				totalHits++
-			}
-			if payout >= 10.0 {
-				totalBigWins++
-			}
-			if payout >= 50.0 {
-				totalMegaWins++
-			}
-			if payout > trialMaxWin {
-				trialMaxWin = payout
-			}
-			if payout > maxWin {
-				maxWin = payout
-			}
+	for trial, t := range trials {
+		totalWon += t.won
+		totalWonSq += t.wonSq
+		totalHits += t.hits
+		totalBigWins += t.bigWins
+		totalMegaWins += t.megaWins
+		for i, n := range t.tierCounts {
+			tierCounts[i] += n
 		}
-
-		totalWon += trialWon
-		trialRTP := trialWon / (float64(config.Spins) * config.Bet)
-
-		// Check RTP at test spin points
-		for i, testSpin := range config.TestSpins {
-			if testSpin <= config.Spins {
-				var sumToSpin float64
-				for j := 0; j < testSpin; j++ {
-					sumToSpin += spinPayouts[j]
-				}
-				rtpAtSpin := sumToSpin / (float64(testSpin) * config.Bet)
-				if rtpAtSpin >= config.TargetRTP {
-					testSpinSuccess[i]++
-				}
+		if t.maxWin > maxWin {
+			maxWin = t.maxWin
+		}
+		for i, passed := range t.passedAt {
+			if passed {
+				testSpinSuccess[i]++
 			}
 		}
 
+		trialRTP := t.won / (float64(config.Spins) * config.Bet)
 		trialSummaries[trial] = TrialSummary{
 			Trial:     trial + 1,
-			TotalWon:  round2(trialWon),
+			TotalWon:  round2(t.won),
 			RTP:       round4(trialRTP),
-			HitCount:  trialHits,
-			MaxWin:    round2(trialMaxWin),
+			HitCount:  t.hits,
+			MaxWin:    round2(t.maxWin),
 			PassedRTP: trialRTP >= config.TargetRTP,
 		}
 	}
@@ -290,10 +182,11 @@ func (s *Simulator) RunSimulation(lut *stakergs.LookupTable, config SimulationCo
 	result.HitRate = round4(float64(totalHits) / float64(totalSpins))
 	result.BigWins = totalBigWins
 	result.MegaWins = totalMegaWins
+	result.WinTiers = s.winTiers.winTierCounts(tierCounts, totalSpins)
 	result.MaxWin = round2(maxWin)
 
-	// Only include trial summaries if trials <= 100
-	if config.Trials <= 100 {
+	// Only include trial summaries if trials <= 100, unless asked for all
+	if config.Trials <= 100 || config.AllTrialSummaries {
 		result.TrialSummaries = trialSummaries
 	}
 
@@ -311,31 +204,167 @@ func (s *Simulator) RunSimulation(lut *stakergs.LookupTable, config SimulationCo
 			SuccessRate: round4(successRate),
 			Weight:      weight,
 		}
+		if config.Trials > 0 {
+			rtpAtSpins[i].CI95 = wilsonInterval(successRate, int64(config.Trials), 0.95, z95)
+		}
 		finalScore += successRate * weight
 	}
 	result.RTPatSpins = rtpAtSpins
 	result.FinalScore = round4(finalScore)
 
+	result.Diagnostics = newDiagnostics(NewAnalyzer().Moments(lut), config, int64(totalSpins), totalWon, totalWonSq, int64(totalHits))
+
 	result.DurationMs = time.Since(start).Milliseconds()
 
-	return result
+	return result, nil
+}
+
+// trialResult holds the totals of one trial.
+type trialResult struct {
+	won        float64
+	wonSq      float64 // Sum of squared payouts, for the standard error
+	hits       int
+	bigWins    int
+	megaWins   int
+	tierCounts []int // Wins per win tier
+	maxWin     float64
+	passedAt   []bool // Per TestSpins entry: RTP after that many spins met the target
+}
+
+// winCounter counts wins by the simulator's win tiers for one mode.
+type winCounter struct {
+	tiers  *WinTiers
+	maxWin float64 // The mode's max payout, for a max-win tier
+}
+
+func (s *Simulator) newWinCounter(lut *stakergs.LookupTable) winCounter {
+	return winCounter{tiers: s.winTiers, maxWin: float64(lut.MaxPayout()) / 100.0}
+}
+
+// count records a payout in the tier counts and returns whether it was a big
+// and a mega win. Big and mega wins keep their fixed 10x and 50x thresholds
+// so they mean the same for every game.
+func (c winCounter) count(payout float64, counts []int) (big, mega bool) {
+	if tier := c.tiers.Classify(payout, c.maxWin); tier >= 0 {
+		counts[tier]++
+	}
+	return payout >= common.BigWinMultiplier, payout >= common.MegaWinMultiplier
+}
+
+// checkpoint is a TestSpins entry, visited in spin order.
+type checkpoint struct {
+	spin  int
+	index int
+}
+
+// runTrials runs all trials over config.Workers goroutines (runtime.NumCPU()
+// when zero). Each trial draws from its own stream of the run's seed, so the
+// results are the same for any number of workers.
+func (s *Simulator) runTrials(ctx context.Context, sampler *sampling.Sampler, wins winCounter, config SimulationConfig, progress func(trialsDone, trials int)) ([]trialResult, error) {
+	// Test spins beyond the trial length, or not positive, never pass
+	var checkpoints []checkpoint
+	for i, testSpin := range config.TestSpins {
+		if testSpin > 0 && testSpin <= config.Spins {
+			checkpoints = append(checkpoints, checkpoint{spin: testSpin, index: i})
+		}
+	}
+	sort.SliceStable(checkpoints, func(a, b int) bool { return checkpoints[a].spin < checkpoints[b].spin })
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, config.Trials)
+
+	results := make([]trialResult, config.Trials)
+	var next atomic.Int64
+	var progressMu sync.Mutex
+	done := 0
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				trial := int(next.Add(1) - 1)
+				if trial >= config.Trials || ctx.Err() != nil {
+					return
+				}
+				results[trial] = runTrial(sampler, wins, config, common.NewRand(config.Seed, int64(trial)), checkpoints)
+
+				if progress != nil {
+					progressMu.Lock()
+					done++
+					progress(done, config.Trials)
+					progressMu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// runTrial plays one trial. The running payout sum is checked at each
+// checkpoint as it passes, so test spins need no per-spin payout slice.
+func runTrial(sampler *sampling.Sampler, wins winCounter, config SimulationConfig, rng *rand.Rand, checkpoints []checkpoint) trialResult {
+	t := trialResult{passedAt: make([]bool, len(config.TestSpins)), tierCounts: make([]int, len(wins.tiers.Tiers))}
+	next := 0
+
+	for spin := 0; spin < config.Spins; spin++ {
+		outcome := sampler.Sample(rng)
+		payout := float64(outcome.Payout) / 100.0
+
+		t.won += payout
+		t.wonSq += payout * payout
+
+		if payout > 0 {
+			t.hits++
+		}
+		if big, mega := wins.count(payout, t.tierCounts); big {
+			t.bigWins++
+			if mega {
+				t.megaWins++
+			}
+		}
+		if payout > t.maxWin {
+			t.maxWin = payout
+		}
+
+		for next < len(checkpoints) && checkpoints[next].spin == spin+1 {
+			rtpAtSpin := t.won / (float64(spin+1) * config.Bet)
+			t.passedAt[checkpoints[next].index] = rtpAtSpin >= config.TargetRTP
+			next++
+		}
+	}
+
+	return t
 }
 
 // RunQuickSimulation runs a simple simulation and returns spin-by-spin results.
-func (s *Simulator) RunQuickSimulation(lut *stakergs.LookupTable, spins int, bet float64) *SimulationResult {
+// A zero seed picks a random one.
+func (s *Simulator) RunQuickSimulation(lut *stakergs.LookupTable, spins int, bet float64, seed int64) *SimulationResult {
 	start := time.Now()
 
-	sampler := NewWeightedSampler(lut)
+	sampler := s.samplers.Get(lut, 0)
 
-	s.mu.Lock()
-	rng := rand.New(rand.NewSource(s.rng.Int63()))
-	s.mu.Unlock()
+	if seed == 0 {
+		seed = common.NewSeed()
+	}
+	rng := common.NewRand(seed, 0)
 
 	spinResults := make([]SpinResult, spins)
 	var totalWon float64
 	var hitCount int
 	var bigWins int
 	var megaWins int
+	wins := s.newWinCounter(lut)
+	tierCounts := make([]int, len(s.winTiers.Tiers))
 	var maxWin float64
 
 	for i := 0; i < spins; i++ {
@@ -347,11 +376,11 @@ func (s *Simulator) RunQuickSimulation(lut *stakergs.LookupTable, spins int, bet
 		if payout > 0 {
 			hitCount++
 		}
-		if payout >= 10.0 {
+		if big, mega := wins.count(payout, tierCounts); big {
 			bigWins++
-		}
-		if payout >= 50.0 {
-			megaWins++
+			if mega {
+				megaWins++
+			}
 		}
 		if payout > maxWin {
 			maxWin = payout
@@ -379,12 +408,14 @@ func (s *Simulator) RunQuickSimulation(lut *stakergs.LookupTable, spins int, bet
 		HitRate:      round4(float64(hitCount) / float64(spins)),
 		BigWins:      bigWins,
 		MegaWins:     megaWins,
+		WinTiers:     s.winTiers.winTierCounts(tierCounts, spins),
 		MaxWin:       round2(maxWin),
 		SpinResults:  spinResults,
 		DurationMs:   time.Since(start).Milliseconds(),
 		Config: SimulationConfig{
 			Spins: spins,
 			Bet:   bet,
+			Seed:  seed,
 		},
 	}
 }
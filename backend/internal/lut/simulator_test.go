@@ -0,0 +1,120 @@
+package lut
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"lutexplorer/internal/sampling"
+	"stakergs"
+)
+
+func TestRunSimulationContext_StopsOnCancel(t *testing.T) {
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 3, Payout: 0},
+		{SimID: 1, Weight: 1, Payout: 300},
+	}}
+	simulator := NewSimulator(sampling.NewCache())
+	config := SimulationConfig{Spins: 10, Trials: 50, Bet: 1, TargetRTP: 0.97, Seed: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var reported int
+	_, err := simulator.RunSimulationContext(ctx, table, config, func(trialsDone, trials int) {
+		reported = trialsDone
+		if trialsDone == 5 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if reported != 5 {
+		t.Errorf("ran %d trials after cancelling at 5", reported)
+	}
+
+	result, err := simulator.RunSimulationContext(context.Background(), table, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := simulator.RunSimulation(table, config)
+	if result.TotalWon != again.TotalWon || result.HitCount != again.HitCount {
+		t.Errorf("same seed gave %v won / %d hits and %v / %d", result.TotalWon, result.HitCount, again.TotalWon, again.HitCount)
+	}
+}
+
+func TestRunSimulation_SameResultForAnyWorkerCount(t *testing.T) {
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 600, Payout: 0},
+		{SimID: 1, Weight: 250, Payout: 50},
+		{SimID: 2, Weight: 100, Payout: 200},
+		{SimID: 3, Weight: 45, Payout: 1000},
+		{SimID: 4, Weight: 5, Payout: 10000},
+	}}
+	simulator := NewSimulator(sampling.NewCache())
+	config := SimulationConfig{
+		Spins:     500,
+		Trials:    64,
+		Bet:       1,
+		TargetRTP: 0.9,
+		TestSpins: []int{400, 50, 0, 600, 50},
+		Seed:      99,
+	}
+
+	normalize := func(r *SimulationResult) *SimulationResult {
+		r.DurationMs = 0
+		r.Config.Workers = 0
+		return r
+	}
+
+	config.Workers = 1
+	want := normalize(simulator.RunSimulation(table, config))
+	for _, workers := range []int{3, 16, 0} {
+		config.Workers = workers
+		if got := normalize(simulator.RunSimulation(table, config)); !reflect.DeepEqual(got, want) {
+			t.Errorf("workers=%d: result differs from a single worker", workers)
+		}
+	}
+
+	// Test spins are reported in request order; duplicates agree and
+	// points outside the trial never pass
+	rates := want.RTPatSpins
+	if rates[1].SuccessRate != rates[4].SuccessRate {
+		t.Errorf("duplicate test spins gave %v and %v", rates[1].SuccessRate, rates[4].SuccessRate)
+	}
+	if rates[2].SuccessRate != 0 || rates[3].SuccessRate != 0 {
+		t.Errorf("expected test spins 0 and 600 to never pass, got %v and %v", rates[2].SuccessRate, rates[3].SuccessRate)
+	}
+	if rates[0].SpinCount != 400 || rates[0].SuccessRate == 0 {
+		t.Errorf("unexpected rate at 400 spins: %+v", rates[0])
+	}
+}
+
+func BenchmarkRunSimulation(b *testing.B) {
+	table := &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 600, Payout: 0},
+		{SimID: 1, Weight: 250, Payout: 50},
+		{SimID: 2, Weight: 100, Payout: 200},
+		{SimID: 3, Weight: 45, Payout: 1000},
+		{SimID: 4, Weight: 5, Payout: 10000},
+	}}
+	simulator := NewSimulator(sampling.NewCache())
+
+	for _, workers := range []int{1, 0} {
+		config := SimulationConfig{
+			Spins:     10_000,
+			Trials:    200,
+			Bet:       1,
+			TargetRTP: 0.97,
+			TestSpins: []int{100, 500, 1000, 5000, 10_000},
+			Seed:      1,
+			Workers:   workers,
+		}
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				simulator.RunSimulation(table, config)
+			}
+		})
+	}
+}
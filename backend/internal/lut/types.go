@@ -42,6 +42,11 @@ type Statistics struct {
 	// Cost-adjusted metrics (for bonus modes with cost > 1)
 	BreakevenRate    float64 `json:"breakeven_rate"`    // P(payout >= cost)
 	CostAdjVolatility float64 `json:"cost_adj_volatility"` // StdDev / Cost
+	// Exact RTP from integer arithmetic, unaffected by float rounding
+	ExactRTP        string `json:"exact_rtp"`         // Reduced fraction "num/den"
+	ExactRTPDecimal string `json:"exact_rtp_decimal"` // Decimal with 12 places
+	// Frequency and RTP contribution of each of the game's win tiers
+	WinTiers []WinTierStats `json:"win_tiers"`
 }
 
 // PayoutBucket represents a range of payouts for histogram visualization.
@@ -72,11 +77,18 @@ type PayoutInfo struct {
 }
 
 // Analyzer provides analysis utilities for LookupTables.
-type Analyzer struct{}
+type Analyzer struct {
+	winTiers *WinTiers
+}
 
-// NewAnalyzer creates a new Analyzer.
+// NewAnalyzer creates a new Analyzer using the default win tiers.
 func NewAnalyzer() *Analyzer {
-	return &Analyzer{}
+	return &Analyzer{winTiers: DefaultWinTiers()}
+}
+
+// WithWinTiers returns an Analyzer that classifies wins with tiers.
+func (a *Analyzer) WithWinTiers(tiers *WinTiers) *Analyzer {
+	return &Analyzer{winTiers: tiers}
 }
 
 // MinNonZeroPayout returns the minimum non-zero payout multiplier.
@@ -106,34 +118,32 @@ func (a *Analyzer) Analyze(lut *stakergs.LookupTable) *Statistics {
 		cost = 1.0
 	}
 
+	// Exact aggregates: RTP, hit rate, mean and variance are computed
+	// with integer arithmetic and only rounded when converted to float64
+	exact := lut.ExactStats()
+	exactRTP := exact.RTP()
+	rtp, _ := exactRTP.Float64()
+	hitRate, _ := exact.HitRate().Float64()
+
 	stats := &Statistics{
-		Mode:          lut.Mode,
-		Cost:          cost,
-		TotalOutcomes: len(lut.Outcomes),
-		TotalWeight:   totalWeight,
-		RTP:           round4(lut.RTP()),
-		HitRate:       round4(lut.HitRate()),
-		MaxPayout:     round2(float64(lut.MaxPayout()) / 100.0),
-		MinPayout:     round2(a.MinNonZeroPayout(lut)),
-	}
-
-	// Calculate mean payout (weighted)
-	var weightedSum float64
-	for _, o := range lut.Outcomes {
-		payout := float64(o.Payout) / 100.0
-		prob := float64(o.Weight) / float64(totalWeight)
-		weightedSum += payout * prob
-	}
+		Mode:            lut.Mode,
+		Cost:            cost,
+		TotalOutcomes:   len(lut.Outcomes),
+		TotalWeight:     totalWeight,
+		RTP:             round4(rtp),
+		HitRate:         round4(hitRate),
+		MaxPayout:       round2(float64(lut.MaxPayout()) / 100.0),
+		MinPayout:       round2(a.MinNonZeroPayout(lut)),
+		ExactRTP:        exactRTP.String(),
+		ExactRTPDecimal: exactRTP.FloatString(12),
+	}
+
+	// Mean payout (weighted)
+	weightedSum, _ := exact.MeanPayout().Float64()
 	stats.MeanPayout = round4(weightedSum)
 
-	// Calculate variance and std dev
-	var variance float64
-	for _, o := range lut.Outcomes {
-		payout := float64(o.Payout) / 100.0
-		prob := float64(o.Weight) / float64(totalWeight)
-		diff := payout - weightedSum // use unrounded for calculation
-		variance += diff * diff * prob
-	}
+	// Variance and std dev
+	variance, _ := exact.Variance().Float64()
 	stats.Variance = round4(variance)
 	stats.StdDev = round4(math.Sqrt(variance))
 	if weightedSum > 0 {
@@ -183,6 +193,9 @@ func (a *Analyzer) Analyze(lut *stakergs.LookupTable) *Statistics {
 	// Top payouts
 	stats.TopPayouts = a.getTopPayouts(lut, totalWeight, 10)
 
+	// Win tiers
+	stats.WinTiers = a.winTiers.winTierStats(lut, totalWeight, cost)
+
 	return stats
 }
 
@@ -212,8 +225,20 @@ type payoutData struct {
 	simIDs []int
 }
 
-// BuildDistribution builds the payout distribution from a LUT.
+// BuildDistribution builds the payout distribution from a LUT, listing the
+// first 10 sim_ids of each payout.
 func (a *Analyzer) BuildDistribution(lut *stakergs.LookupTable, totalWeight uint64) []DistributionItem {
+	return a.buildDistribution(lut, totalWeight, 10)
+}
+
+// BuildFullDistribution is BuildDistribution listing every sim_id of each payout.
+func (a *Analyzer) BuildFullDistribution(lut *stakergs.LookupTable, totalWeight uint64) []DistributionItem {
+	return a.buildDistribution(lut, totalWeight, 0)
+}
+
+// buildDistribution groups outcomes by payout, keeping up to maxSimIDs sim_ids
+// per payout (0 = all).
+func (a *Analyzer) buildDistribution(lut *stakergs.LookupTable, totalWeight uint64, maxSimIDs int) []DistributionItem {
 	// Group by payout value
 	payoutMap := make(map[uint]*payoutData)
 	for _, o := range lut.Outcomes {
@@ -229,10 +254,10 @@ func (a *Analyzer) BuildDistribution(lut *stakergs.LookupTable, totalWeight uint
 	for payout, data := range payoutMap {
 		odds := float64(totalWeight) / float64(data.weight)
 
-		// Keep only first 10 sim_ids for the response
+		// Keep only the first sim_ids for the response
 		simIDs := data.simIDs
-		if len(simIDs) > 10 {
-			simIDs = simIDs[:10]
+		if maxSimIDs > 0 && len(simIDs) > maxSimIDs {
+			simIDs = simIDs[:maxSimIDs]
 		}
 
 		items = append(items, DistributionItem{
@@ -306,6 +331,43 @@ func (a *Analyzer) BuildPayoutBuckets(lut *stakergs.LookupTable, totalWeight uin
 		return nil
 	}
 
+	buckets := payoutBucketRanges(maxPayout)
+
+	// Populate buckets with outcomes
+	for _, o := range lut.Outcomes {
+		if i := findPayoutBucket(buckets, float64(o.Payout)/100.0); i >= 0 {
+			buckets[i].Count++
+			buckets[i].Weight += o.Weight
+		}
+	}
+
+	// Calculate probabilities and filter empty buckets
+	result := make([]PayoutBucket, 0)
+	for _, b := range buckets {
+		if b.Weight > 0 {
+			b.Probability = float64(b.Weight) / float64(totalWeight)
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
+
+// PayoutBucketSimIDs returns the sim_ids of the outcomes counted in each of
+// buckets, as returned by BuildPayoutBuckets, in table order.
+func (a *Analyzer) PayoutBucketSimIDs(lut *stakergs.LookupTable, buckets []PayoutBucket) [][]int {
+	simIDs := make([][]int, len(buckets))
+	for _, o := range lut.Outcomes {
+		if i := findPayoutBucket(buckets, float64(o.Payout)/100.0); i >= 0 {
+			simIDs[i] = append(simIDs[i], o.SimID)
+		}
+	}
+	return simIDs
+}
+
+// payoutBucketRanges returns empty buckets covering [0, maxPayout]:
+// an exact-zero bucket followed by 1-2-5 logarithmic ranges.
+func payoutBucketRanges(maxPayout float64) []PayoutBucket {
 	// Generate dynamic bucket boundaries
 	boundaries := generateBucketBoundaries(maxPayout)
 
@@ -324,9 +386,10 @@ func (a *Analyzer) BuildPayoutBuckets(lut *stakergs.LookupTable, totalWeight uin
 	}
 
 	// Last bucket extends to max payout + epsilon
+	// (also when max payout sits exactly on the last boundary, since ranges are [start, end))
 	if len(buckets) > 0 {
 		lastBoundary := boundaries[len(boundaries)-1]
-		if lastBoundary < maxPayout {
+		if lastBoundary <= maxPayout {
 			buckets = append(buckets, PayoutBucket{
 				RangeStart: lastBoundary,
 				RangeEnd:   maxPayout + 1,
@@ -334,36 +397,23 @@ func (a *Analyzer) BuildPayoutBuckets(lut *stakergs.LookupTable, totalWeight uin
 		}
 	}
 
-	// Populate buckets with outcomes
-	for _, o := range lut.Outcomes {
-		payout := float64(o.Payout) / 100.0
-		for i := range buckets {
-			inBucket := false
-			if buckets[i].RangeStart == 0 && buckets[i].RangeEnd == 0 {
-				// Zero bucket: exact match
-				inBucket = payout == 0
-			} else {
-				// Regular bucket: [start, end)
-				inBucket = payout >= buckets[i].RangeStart && payout < buckets[i].RangeEnd
-			}
-			if inBucket {
-				buckets[i].Count++
-				buckets[i].Weight += o.Weight
-				break
-			}
-		}
-	}
+	return buckets
+}
 
-	// Calculate probabilities and filter empty buckets
-	result := make([]PayoutBucket, 0)
-	for _, b := range buckets {
-		if b.Weight > 0 {
-			b.Probability = float64(b.Weight) / float64(totalWeight)
-			result = append(result, b)
+// findPayoutBucket returns the index of the bucket containing payout, or -1.
+func findPayoutBucket(buckets []PayoutBucket, payout float64) int {
+	for i := range buckets {
+		if buckets[i].RangeStart == 0 && buckets[i].RangeEnd == 0 {
+			// Zero bucket: exact match
+			if payout == 0 {
+				return i
+			}
+		} else if payout >= buckets[i].RangeStart && payout < buckets[i].RangeEnd {
+			// Regular bucket: [start, end)
+			return i
 		}
 	}
-
-	return result
+	return -1
 }
 
 // BucketDistributionRequest represents a request for distribution items in a bucket range.
@@ -0,0 +1,190 @@
+package lut
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"lutexplorer/internal/common"
+	"stakergs"
+)
+
+// WinTiersFile is the file name, relative to the library folder, that holds
+// the game's win tiers.
+const WinTiersFile = "win_tiers.json"
+
+// WinTier is a named level of win the game celebrates.
+type WinTier struct {
+	Name   string  `json:"name"`
+	Min    float64 `json:"min,omitempty"`     // Smallest win in the tier, in base bets
+	MaxWin bool    `json:"max_win,omitempty"` // The tier is wins paying the mode's maximum
+}
+
+// WinTiers classifies wins by size, lowest tier first.
+type WinTiers struct {
+	Tiers []WinTier `json:"tiers"`
+}
+
+// DefaultWinTiers returns the tiers used when the library defines none: big
+// wins from common.BigWinMultiplier and mega wins from common.MegaWinMultiplier.
+func DefaultWinTiers() *WinTiers {
+	return &WinTiers{Tiers: []WinTier{
+		{Name: "big", Min: common.BigWinMultiplier},
+		{Name: "mega", Min: common.MegaWinMultiplier},
+	}}
+}
+
+// Validate checks that tiers are named uniquely and rise in size. Only the
+// last tier may be the max-win tier.
+func (t *WinTiers) Validate() error {
+	if len(t.Tiers) == 0 {
+		return fmt.Errorf("win tiers: at least one tier required")
+	}
+	names := make(map[string]bool, len(t.Tiers))
+	var prev float64
+	for i, tier := range t.Tiers {
+		if tier.Name == "" {
+			return fmt.Errorf("win tier %d: name required", i+1)
+		}
+		if names[tier.Name] {
+			return fmt.Errorf("duplicate win tier name: %s", tier.Name)
+		}
+		names[tier.Name] = true
+
+		if tier.MaxWin {
+			if i == 0 || i != len(t.Tiers)-1 {
+				return fmt.Errorf("win tier %s: the max-win tier must be the last of two or more", tier.Name)
+			}
+			continue
+		}
+		if tier.Min <= prev {
+			return fmt.Errorf("win tier %s: min must be positive and above the previous tier: %v", tier.Name, tier.Min)
+		}
+		prev = tier.Min
+	}
+	return nil
+}
+
+// BigWin returns the smallest big win, in base bets.
+func (t *WinTiers) BigWin() float64 {
+	return t.Tiers[0].Min
+}
+
+// Classify returns the index of the highest tier a win of multiple base bets
+// reaches, or -1 for none. maxWin is the mode's maximum payout in base bets.
+// A max-win tier also needs the previous tier to be reached, so a mode whose
+// max payout is below the other tiers has no max-win wins.
+func (t *WinTiers) Classify(multiple, maxWin float64) int {
+	for i := len(t.Tiers) - 1; i >= 0; i-- {
+		tier := t.Tiers[i]
+		if tier.MaxWin {
+			if maxWin > 0 && multiple >= maxWin && multiple >= t.Tiers[i-1].Min {
+				return i
+			}
+			continue
+		}
+		if multiple >= tier.Min {
+			return i
+		}
+	}
+	return -1
+}
+
+// LoadWinTiers reads WinTiersFile from dir. A missing file gives the default tiers.
+func LoadWinTiers(dir string) (*WinTiers, error) {
+	data, err := os.ReadFile(filepath.Join(dir, WinTiersFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultWinTiers(), nil
+		}
+		return nil, fmt.Errorf("failed to read win tiers: %w", err)
+	}
+
+	var tiers WinTiers
+	if err := json.Unmarshal(data, &tiers); err != nil {
+		return nil, fmt.Errorf("failed to parse win tiers: %w", err)
+	}
+	if err := tiers.Validate(); err != nil {
+		return nil, err
+	}
+	return &tiers, nil
+}
+
+// WinTiers returns the library's win tiers, read when the library is loaded.
+func (l *Loader) WinTiers() *WinTiers {
+	if l.winTiers == nil {
+		return DefaultWinTiers()
+	}
+	return l.winTiers
+}
+
+// WinTierCount is how many spins of a simulation reached one tier, counting
+// each win in the highest tier it reaches.
+type WinTierCount struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Rate  float64 `json:"rate"` // Count per spin
+}
+
+// winTierCounts pairs per-tier counts with their tier names.
+func (t *WinTiers) winTierCounts(counts []int, spins int) []WinTierCount {
+	result := make([]WinTierCount, len(t.Tiers))
+	for i, tier := range t.Tiers {
+		result[i] = WinTierCount{Name: tier.Name, Count: counts[i]}
+		if spins > 0 {
+			result[i].Rate = round4(float64(counts[i]) / float64(spins))
+		}
+	}
+	return result
+}
+
+// WinTierStats is how often a mode's outcomes land in one tier and what they
+// return, counting each outcome in the highest tier it reaches.
+type WinTierStats struct {
+	Name            string  `json:"name"`
+	Min             float64 `json:"min"` // Smallest win in base bets; the mode's max payout for the max-win tier
+	MaxWin          bool    `json:"max_win,omitempty"`
+	Outcomes        int     `json:"outcomes"`
+	Weight          uint64  `json:"weight"`
+	Probability     float64 `json:"probability"`
+	Odds            string  `json:"odds"` // formatted "1 in 1.5M"
+	RTPContribution float64 `json:"rtp_contribution"`
+}
+
+// winTierStats calculates per-tier frequency and RTP contribution for a mode.
+// RTP contributions are normalized by cost, as the mode's RTP is.
+func (t *WinTiers) winTierStats(lut *stakergs.LookupTable, totalWeight uint64, cost float64) []WinTierStats {
+	maxWin := float64(lut.MaxPayout()) / 100.0
+	stats := make([]WinTierStats, len(t.Tiers))
+	returned := make([]float64, len(t.Tiers))
+	for i, tier := range t.Tiers {
+		stats[i] = WinTierStats{Name: tier.Name, Min: tier.Min, MaxWin: tier.MaxWin}
+		if tier.MaxWin {
+			stats[i].Min = round2(maxWin)
+		}
+	}
+
+	for _, o := range lut.Outcomes {
+		payout := float64(o.Payout) / 100.0
+		i := t.Classify(payout, maxWin)
+		if i < 0 || o.Weight == 0 {
+			continue
+		}
+		stats[i].Outcomes++
+		stats[i].Weight += o.Weight
+		returned[i] += float64(o.Weight) * payout
+	}
+
+	for i := range stats {
+		if stats[i].Weight == 0 {
+			stats[i].Odds = "never"
+			continue
+		}
+		probability := float64(stats[i].Weight) / float64(totalWeight)
+		stats[i].Probability = probability
+		stats[i].Odds = formatOdds(1 / probability)
+		stats[i].RTPContribution = round4(returned[i] / float64(totalWeight) / cost)
+	}
+	return stats
+}
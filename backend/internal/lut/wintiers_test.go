@@ -0,0 +1,134 @@
+package lut
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lutexplorer/internal/sampling"
+	"stakergs"
+)
+
+func tierTable() *stakergs.LookupTable {
+	return &stakergs.LookupTable{Mode: "base", Cost: 1, Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 600, Payout: 0},
+		{SimID: 1, Weight: 250, Payout: 500},
+		{SimID: 2, Weight: 100, Payout: 2000},
+		{SimID: 3, Weight: 45, Payout: 4000},
+		{SimID: 4, Weight: 5, Payout: 10000},
+	}}
+}
+
+func gameTiers() *WinTiers {
+	return &WinTiers{Tiers: []WinTier{
+		{Name: "big", Min: 15},
+		{Name: "huge", Min: 30},
+		{Name: "max", MaxWin: true},
+	}}
+}
+
+func TestWinTiers_Classify(t *testing.T) {
+	tiers := gameTiers()
+	if err := tiers.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		multiple float64
+		want     int
+	}{{5, -1}, {15, 0}, {29.99, 0}, {40, 1}, {100, 2}} {
+		if got := tiers.Classify(c.multiple, 100); got != c.want {
+			t.Errorf("Classify(%v) = %d, want %d", c.multiple, got, c.want)
+		}
+	}
+
+	// A mode whose max payout is below the first tier has no tiered wins
+	for _, multiple := range []float64{1, 5} {
+		if got := tiers.Classify(multiple, 5); got != -1 {
+			t.Errorf("Classify(%v) with a 5x max win = %d, want -1", multiple, got)
+		}
+	}
+	// Between tiers, the max win lands in the tier it reaches
+	if got := tiers.Classify(20, 20); got != 0 {
+		t.Errorf("Classify(20) with a 20x max win = %d, want 0", got)
+	}
+
+	for name, bad := range map[string]*WinTiers{
+		"empty":         {},
+		"unnamed":       {Tiers: []WinTier{{Min: 10}}},
+		"not rising":    {Tiers: []WinTier{{Name: "a", Min: 20}, {Name: "b", Min: 10}}},
+		"max-win first": {Tiers: []WinTier{{Name: "max", MaxWin: true}, {Name: "big", Min: 10}}},
+		"duplicate":     {Tiers: []WinTier{{Name: "a", Min: 10}, {Name: "a", Min: 20}}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+func TestAnalyze_WinTierStats(t *testing.T) {
+	stats := NewAnalyzer().WithWinTiers(gameTiers()).Analyze(tierTable())
+	if len(stats.WinTiers) != 3 {
+		t.Fatalf("win tiers %+v", stats.WinTiers)
+	}
+	big, huge, top := stats.WinTiers[0], stats.WinTiers[1], stats.WinTiers[2]
+	if big.Weight != 100 || huge.Weight != 45 || top.Weight != 5 || top.Min != 100 {
+		t.Errorf("unexpected tiers %+v", stats.WinTiers)
+	}
+	if big.RTPContribution != 2 || huge.RTPContribution != 1.8 || top.RTPContribution != 0.5 || big.Odds != "1 in 10" {
+		t.Errorf("unexpected contributions %+v", stats.WinTiers)
+	}
+
+	// The default tiers reproduce the fixed 10x and 50x thresholds
+	defaults := NewAnalyzer().Analyze(tierTable()).WinTiers
+	if defaults[0].Name != "big" || defaults[0].Weight != 145 || defaults[1].Weight != 5 {
+		t.Errorf("unexpected default tiers %+v", defaults)
+	}
+}
+
+func TestSimulator_CountsWinTiers(t *testing.T) {
+	simulator := NewSimulator(sampling.NewCache()).WithWinTiers(gameTiers())
+	config := SimulationConfig{Spins: 1000, Trials: 10, Bet: 1, TargetRTP: 0.9, Seed: 4}
+	result := simulator.RunSimulation(tierTable(), config)
+
+	var tiered int
+	for _, c := range result.WinTiers {
+		tiered += c.Count
+	}
+	// Every tiered payout is 20x or more, and only the 100x max win reaches 50x
+	if tiered != result.BigWins || result.WinTiers[2].Count != result.MegaWins {
+		t.Errorf("tiers %+v, big %d, mega %d", result.WinTiers, result.BigWins, result.MegaWins)
+	}
+	if rate := result.WinTiers[0].Rate; math.Abs(rate-0.1) > 0.015 {
+		t.Errorf("big tier rate %v, want about 0.1", rate)
+	}
+
+	quick := simulator.RunQuickSimulation(tierTable(), 1000, 1, 4)
+	if len(quick.WinTiers) != 3 {
+		t.Errorf("quick simulation tiers %+v", quick.WinTiers)
+	}
+}
+
+func TestLoadWinTiers(t *testing.T) {
+	dir := t.TempDir()
+	tiers, err := LoadWinTiers(dir)
+	if err != nil || tiers.BigWin() != 10 || len(tiers.Tiers) != 2 {
+		t.Fatalf("missing file gave %+v, %v; want the defaults", tiers, err)
+	}
+
+	data := `{"tiers": [{"name": "big", "min": 15}, {"name": "max", "max_win": true}]}`
+	if err := os.WriteFile(filepath.Join(dir, WinTiersFile), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tiers, err = LoadWinTiers(dir)
+	if err != nil || tiers.BigWin() != 15 || !tiers.Tiers[1].MaxWin {
+		t.Fatalf("loaded %+v, %v", tiers, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, WinTiersFile), []byte(`{"tiers": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWinTiers(dir); err == nil {
+		t.Error("expected an error for a file without tiers")
+	}
+}
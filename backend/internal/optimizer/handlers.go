@@ -1,6 +1,7 @@
 package optimizer
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,24 +13,28 @@ import (
 	"time"
 
 	"lutexplorer/internal/common"
+	"lutexplorer/internal/jobs"
 	"lutexplorer/internal/lut"
 	"lutexplorer/internal/ws"
 
 	"github.com/gorilla/websocket"
+	"stakergs"
 )
 
 // Handlers provides HTTP handlers for the optimizer API
 type Handlers struct {
 	loader   *lut.Loader
 	wsHub    *ws.Hub
+	jobs     *jobs.Manager
 	analyzer *ModeAnalyzer
 }
 
 // NewHandlers creates new optimizer HTTP handlers
-func NewHandlers(loader *lut.Loader, wsHub *ws.Hub) *Handlers {
+func NewHandlers(loader *lut.Loader, wsHub *ws.Hub, jobManager *jobs.Manager) *Handlers {
 	return &Handlers{
 		loader:   loader,
 		wsHub:    wsHub,
+		jobs:     jobManager,
 		analyzer: NewModeAnalyzer(loader),
 	}
 }
@@ -309,22 +314,64 @@ type BucketOptimizeRequest struct {
 // HandleBucketOptimize runs bucket-based optimization on a mode
 // POST /api/optimizer/{mode}/bucket-optimize
 func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request) {
+	mode, table, req, config, ok := h.decodeBucketOptimize(w, r, "bucket-optimize")
+	if !ok {
+		return
+	}
+
+	response, err := h.runBucketOptimize(context.Background(), mode, table, req, config, nil)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, response)
+}
+
+// HandleBucketOptimizeJob starts a bucket optimization as a background job.
+// Progress is broadcast as job_progress messages; the response of
+// bucket-optimize is fetched from /api/jobs once the job completes. Weights
+// of a cancelled job are never saved.
+// POST /api/optimizer/{mode}/optimize-job
+func (h *Handlers) HandleBucketOptimizeJob(w http.ResponseWriter, r *http.Request) {
+	mode, table, req, config, ok := h.decodeBucketOptimize(w, r, "optimize-job")
+	if !ok {
+		return
+	}
+
+	info := h.jobs.Start(jobs.KindOptimizer, mode, func(ctx context.Context, report func(jobs.Progress)) (interface{}, error) {
+		return h.runBucketOptimize(ctx, mode, table, req, config, func(p BruteForceProgress) {
+			report(jobs.Progress{
+				Done:   int64(p.Iteration),
+				Total:  int64(p.MaxIter),
+				Detail: p,
+			})
+		})
+	})
+	jobs.WriteStarted(w, info)
+}
+
+// decodeBucketOptimize looks up the mode of an optimizer action and reads a
+// BucketOptimizeRequest, applying defaults, suggesting buckets if none are
+// given and validating the resulting config. It writes the error response
+// and returns false on failure.
+func (h *Handlers) decodeBucketOptimize(w http.ResponseWriter, r *http.Request, action string) (string, *stakergs.LookupTable, BucketOptimizeRequest, *BucketOptimizerConfig, bool) {
+	var req BucketOptimizeRequest
 	if r.Method != http.MethodPost {
 		common.WriteError(w, http.StatusMethodNotAllowed, "POST required")
-		return
+		return "", nil, req, nil, false
 	}
 
-	mode := extractMode(r.URL.Path, "bucket-optimize")
+	mode := extractMode(r.URL.Path, action)
 	if mode == "" {
 		common.WriteError(w, http.StatusBadRequest, "mode required")
-		return
+		return "", nil, req, nil, false
 	}
 
 	// Parse request
-	var req BucketOptimizeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %s", err.Error()))
-		return
+		return "", nil, req, nil, false
 	}
 
 	// Apply defaults
@@ -339,7 +386,7 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 	if len(req.Buckets) > 0 {
 		if err := ValidateBuckets(req.Buckets); err != nil {
 			common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid buckets: %s", err.Error()))
-			return
+			return "", nil, req, nil, false
 		}
 	}
 
@@ -347,7 +394,7 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 	table, err := h.loader.GetMode(mode)
 	if err != nil {
 		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("mode not found: %s", mode))
-		return
+		return "", nil, req, nil, false
 	}
 
 	// If no buckets provided, suggest them based on table
@@ -356,8 +403,19 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		buckets = SuggestBuckets(table, req.TargetRTP)
 	}
 
-	// Create optimizer config
-	config := &BucketOptimizerConfig{
+	config := newBucketConfig(req, buckets)
+	if req.EnableBruteForce {
+		if err := ValidateBruteForceConfig(config); err != nil {
+			common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid brute force config: %s", err.Error()))
+			return "", nil, req, nil, false
+		}
+	}
+	return mode, table, req, config, true
+}
+
+// newBucketConfig creates the optimizer config for a bucket optimize request.
+func newBucketConfig(req BucketOptimizeRequest, buckets []BucketConfig) *BucketOptimizerConfig {
+	return &BucketOptimizerConfig{
 		TargetRTP:           req.TargetRTP,
 		RTPTolerance:        req.RTPTolerance,
 		Buckets:             buckets,
@@ -370,33 +428,58 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		VoidedBucketIndices: req.VoidedBucketIndices,
 		EnableAutoVoiding:   req.EnableAutoVoiding,
 	}
+}
+
+// runBucketOptimize runs a validated bucket optimization, saves the weights
+// if requested and builds the bucket-optimize response. Brute force searches
+// stop once ctx is cancelled and report to progress if it is not nil.
+func (h *Handlers) runBucketOptimize(ctx context.Context, mode string, table *stakergs.LookupTable, req BucketOptimizeRequest, config *BucketOptimizerConfig, progress func(BruteForceProgress)) (map[string]interface{}, error) {
+	buckets := config.Buckets
 
 	var result *BucketOptimizerResult
 	var bruteForceResult *BruteForceResult
+	var err error
 
 	// Run optimization - use brute force if enabled
 	if req.EnableBruteForce {
-		// Validate brute force config
-		if err := ValidateBruteForceConfig(config); err != nil {
-			common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid brute force config: %s", err.Error()))
-			return
+		stopChan := make(chan struct{})
+		stopOnCancel := context.AfterFunc(ctx, func() { close(stopChan) })
+		defer stopOnCancel()
+
+		var progressChan chan BruteForceProgress
+		forwarded := make(chan struct{})
+		if progress != nil {
+			progressChan = make(chan BruteForceProgress, 100)
+			go func() {
+				defer close(forwarded)
+				for p := range progressChan {
+					progress(p)
+				}
+			}()
+		} else {
+			close(forwarded)
 		}
 
-		bruteForceOpt := NewBruteForceOptimizer(config, nil) // No progress channel for HTTP
+		bruteForceOpt := NewBruteForceOptimizerWithStop(config, progressChan, stopChan)
 		bruteForceResult, err = bruteForceOpt.OptimizeTable(table)
+		if progressChan != nil {
+			close(progressChan)
+		}
+		<-forwarded
 		if err != nil {
-			common.WriteError(w, http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
 		result = bruteForceResult.BucketOptimizerResult
 	} else {
 		optimizer := NewBucketOptimizer(config)
 		result, err = optimizer.OptimizeTable(table)
 		if err != nil {
-			common.WriteError(w, http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Save if requested
 	var saveInfo map[string]interface{}
@@ -404,8 +487,7 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		if req.CreateBackup {
 			backupPath, err := h.loader.SaveWeightsWithBackup(mode, result.NewWeights)
 			if err != nil {
-				common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("save failed: %s", err.Error()))
-				return
+				return nil, fmt.Errorf("save failed: %s", err.Error())
 			}
 			saveInfo = map[string]interface{}{
 				"saved":       true,
@@ -413,8 +495,7 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 			}
 		} else {
 			if err := h.loader.SaveWeights(mode, result.NewWeights); err != nil {
-				common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("save failed: %s", err.Error()))
-				return
+				return nil, fmt.Errorf("save failed: %s", err.Error())
 			}
 			saveInfo = map[string]interface{}{"saved": true}
 		}
@@ -480,7 +561,7 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		response["save_result"] = saveInfo
 	}
 
-	common.WriteSuccess(w, response)
+	return response, nil
 }
 
 // HandleBucketPresets returns available bucket presets
@@ -1160,6 +1241,8 @@ func (h *Handlers) RegisterRoutes(mux *http.ServeMux) {
 			h.HandleBucketOptimize(w, r)
 		case strings.HasSuffix(path, "/optimize-stream"):
 			h.HandleBruteForceOptimizeWS(w, r)
+		case strings.HasSuffix(path, "/optimize-job"):
+			h.HandleBucketOptimizeJob(w, r)
 		case strings.HasSuffix(path, "/suggest-buckets"):
 			h.HandleSuggestBuckets(w, r)
 		case path == "/api/optimizer/bucket-presets":
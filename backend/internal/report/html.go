@@ -0,0 +1,156 @@
+package report
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlFuncs = template.FuncMap{
+	"percent":     percent,
+	"interval":    interval,
+	"multiple":    multiple,
+	"probability": probability,
+	"passFail":    passFail,
+}
+
+// htmlTemplate renders a report as one HTML file with inline styles and no
+// external resources, so it can be archived and printed as it is.
+var htmlTemplate = template.Must(template.New("report").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 13px; color: #1a1a1a; margin: 32px; }
+h1 { font-size: 22px; margin-bottom: 4px; }
+h2 { font-size: 18px; border-bottom: 2px solid #333; padding-bottom: 4px; margin-top: 32px; }
+h3 { font-size: 14px; margin: 20px 0 6px; }
+table { border-collapse: collapse; margin-bottom: 8px; }
+th, td { border: 1px solid #bbb; padding: 3px 8px; text-align: right; }
+th { background: #eee; }
+td.text, th.text { text-align: left; }
+.meta td { border: none; text-align: left; padding: 1px 12px 1px 0; }
+.pass { color: #176b2c; font-weight: bold; }
+.fail { color: #b3261e; font-weight: bold; }
+.note { color: #555; font-style: italic; }
+section.mode { page-break-before: always; }
+@media print {
+  body { margin: 0; font-size: 10px; }
+  h2 { margin-top: 0; }
+  tr { page-break-inside: avoid; }
+}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="meta">
+<tr><td>Library</td><td>{{.Library}}</td></tr>
+<tr><td>Generated</td><td>{{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+<tr><td>Compliance profile</td><td>{{.Profile}}</td></tr>
+<tr><td>Compliance</td><td class="{{if .AllPassed}}pass{{else}}fail{{end}}">{{passFail .AllPassed}}</td></tr>
+<tr><td>Simulation</td><td>{{.Options.Trials}} trials x {{.Options.Spins}} spins per mode, seed {{.Options.Seed}}</td></tr>
+</table>
+
+<h2>Modes</h2>
+<table>
+<tr><th class="text">Mode</th><th>Cost</th><th>RTP</th><th>Hit rate</th><th>Max payout</th><th>Volatility</th><th>Simulated RTP</th><th class="text">Compliance</th></tr>
+{{range .Modes}}<tr><td class="text">{{.Mode}}</td><td>{{.Statistics.Cost}}</td><td>{{percent .Statistics.RTP 4}}</td><td>{{percent .Statistics.HitRate 2}}</td><td>{{multiple .Statistics.MaxPayout}}</td><td>{{.Statistics.Volatility}}</td><td>{{percent .Simulation.ActualRTP 4}}</td>{{with .Compliance}}<td class="text {{if .Passed}}pass{{else}}fail{{end}}">{{passFail .Passed}}</td>{{else}}<td></td>{{end}}</tr>
+{{end}}</table>
+{{if .GlobalChecks}}
+<h3>Global checks</h3>
+{{template "checks" .GlobalChecks}}
+{{end}}
+<h3>Win tiers</h3>
+<table>
+<tr><th class="text">Tier</th><th>From</th></tr>
+{{range .WinTiers.Tiers}}<tr><td class="text">{{.Name}}</td><td>{{if .MaxWin}}max win{{else}}{{multiple .Min}}{{end}}</td></tr>
+{{end}}</table>
+
+{{range .Modes}}{{template "mode" .}}{{end}}
+</body>
+</html>
+{{define "checks"}}<table>
+<tr><th class="text">Result</th><th class="text">Severity</th><th class="text">Check</th><th class="text">Value</th><th class="text">Expected</th><th class="text">Reason</th></tr>
+{{range .}}<tr><td class="text {{if .Passed}}pass{{else}}fail{{end}}">{{passFail .Passed}}</td><td class="text">{{.Severity}}</td><td class="text">{{.Name}}</td><td class="text">{{.Value}}</td><td class="text">{{.Expected}}</td><td class="text">{{.Reason}}</td></tr>
+{{end}}</table>
+{{end}}
+{{define "mode"}}<section class="mode">
+<h2>Mode: {{.Mode}}</h2>
+{{with .Statistics}}
+<h3>Statistics</h3>
+<table>
+<tr><td class="text">Cost</td><td>{{.Cost}}</td></tr>
+<tr><td class="text">Outcomes</td><td>{{.TotalOutcomes}}</td></tr>
+<tr><td class="text">Total weight</td><td>{{.TotalWeight}}</td></tr>
+<tr><td class="text">RTP</td><td>{{percent .RTP 4}}</td></tr>
+<tr><td class="text">Exact RTP</td><td>{{.ExactRTP}} = {{.ExactRTPDecimal}}</td></tr>
+<tr><td class="text">Hit rate</td><td>{{percent .HitRate 2}}</td></tr>
+<tr><td class="text">Zero payout rate</td><td>{{percent .ZeroPayoutRate 2}}</td></tr>
+<tr><td class="text">Breakeven rate</td><td>{{percent .BreakevenRate 2}}</td></tr>
+<tr><td class="text">Min / max payout</td><td>{{multiple .MinPayout}} / {{multiple .MaxPayout}}</td></tr>
+<tr><td class="text">Mean / median payout</td><td>{{multiple .MeanPayout}} / {{multiple .MedianPayout}}</td></tr>
+<tr><td class="text">Variance</td><td>{{.Variance}}</td></tr>
+<tr><td class="text">Std dev</td><td>{{.StdDev}}</td></tr>
+<tr><td class="text">Volatility (std dev / mean)</td><td>{{.Volatility}}</td></tr>
+<tr><td class="text">Cost-adjusted volatility</td><td>{{.CostAdjVolatility}}</td></tr>
+<tr><td class="text">Mean / median ratio</td><td>{{.MeanMedian}}</td></tr>
+</table>
+{{if .WinTiers}}
+<h3>Win tiers</h3>
+<table>
+<tr><th class="text">Tier</th><th>From</th><th>Outcomes</th><th>Probability</th><th>Odds</th><th>RTP contribution</th></tr>
+{{range .WinTiers}}<tr><td class="text">{{.Name}}</td><td>{{multiple .Min}}</td><td>{{.Outcomes}}</td><td>{{percent .Probability 4}}</td><td>{{.Odds}}</td><td>{{percent .RTPContribution 2}}</td></tr>
+{{end}}</table>
+{{end}}
+<h3>Payout ranges</h3>
+<table>
+<tr><th>From</th><th>To</th><th>Outcomes</th><th>Weight</th><th>Probability</th></tr>
+{{range .PayoutBuckets}}<tr><td>{{multiple .RangeStart}}</td><td>{{multiple .RangeEnd}}</td><td>{{.Count}}</td><td>{{.Weight}}</td><td>{{percent .Probability 4}}</td></tr>
+{{end}}</table>
+{{$total := .TotalWeight}}
+<h3>Payout distribution</h3>
+<table>
+<tr><th>Payout</th><th>Outcomes</th><th>Weight</th><th>Probability</th><th>Odds</th></tr>
+{{range .Distribution}}<tr><td>{{multiple .Payout}}</td><td>{{.Count}}</td><td>{{.Weight}}</td><td>{{percent (probability .Weight $total) 6}}</td><td>{{.Odds}}</td></tr>
+{{end}}</table>
+{{end}}{{if .OmittedRows}}<p class="note">{{.OmittedRows}} more payouts not shown.</p>
+{{end}}{{with .Statistics}}
+<h3>Top payouts</h3>
+<table>
+<tr><th>Sim ID</th><th>Payout</th><th>Outcomes</th><th>Weight</th><th>Odds</th></tr>
+{{range .TopPayouts}}<tr><td>{{.SimID}}</td><td>{{multiple .Payout}}</td><td>{{.Count}}</td><td>{{.Weight}}</td><td>{{.Odds}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Compliance}}
+<h3>Compliance: <span class="{{if .Passed}}pass{{else}}fail{{end}}">{{passFail .Passed}}</span></h3>
+<p>{{.PassedCount}} passed, {{.FailedCount}} failed, {{.WarningCount}} warning(s).</p>
+{{template "checks" .Checks}}
+{{end}}{{with .Simulation}}
+<h3>Simulation</h3>
+<p>{{.Config.Trials}} trials x {{.Config.Spins}} spins at a bet of {{.Config.Bet}}, seed {{.Config.Seed}}.</p>
+{{with .Diagnostics}}<table>
+<tr><th class="text">Metric</th><th>Simulated</th><th>Std error</th><th>95% CI</th><th>99% CI</th><th>Theoretical</th><th>Expected 99% range</th><th class="text">In range</th></tr>
+<tr><td class="text">RTP</td><td>{{percent .RTP.Value 4}}</td><td>{{percent .RTP.StdError 4}}</td><td>{{interval .RTP.CI95 4}}</td><td>{{interval .RTP.CI99 4}}</td><td>{{percent .TheoreticalRTP 4}}</td><td>{{interval .ExpectedRTPRange 4}}</td><td class="text {{if .RTPOutOfRange}}fail{{else}}pass{{end}}">{{if .RTPOutOfRange}}NO{{else}}YES{{end}}</td></tr>
+<tr><td class="text">Hit rate</td><td>{{percent .HitRate.Value 4}}</td><td>{{percent .HitRate.StdError 4}}</td><td>{{interval .HitRate.CI95 4}}</td><td>{{interval .HitRate.CI99 4}}</td><td>{{percent .TheoreticalHitRate 4}}</td><td>{{interval .ExpectedHitRateRange 4}}</td><td class="text {{if .HitRateOutOfRange}}fail{{else}}pass{{end}}">{{if .HitRateOutOfRange}}NO{{else}}YES{{end}}</td></tr>
+</table>
+{{end}}<table>
+<tr><td class="text">Max win</td><td>{{multiple .MaxWin}}</td></tr>
+{{range .WinTiers}}<tr><td class="text">{{.Name}} wins</td><td>{{.Count}} ({{percent .Rate 2}} of spins)</td></tr>
+{{end}}</table>
+{{if .RTPatSpins}}
+<table>
+<tr><th>Spins</th><th>Trials with RTP &ge; {{percent .Config.TargetRTP 2}}</th><th>95% CI</th></tr>
+{{range .RTPatSpins}}<tr><td>{{.SpinCount}}</td><td>{{percent .SuccessRate 2}}</td><td>{{interval .CI95 2}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Diagnostics}}{{if .SpinsForPrecision}}
+<table>
+<tr><th>RTP precision</th><th>Confidence</th><th>Spins needed</th></tr>
+{{range .SpinsForPrecision}}<tr><td>&plusmn; {{percent .Precision 2}}</td><td>{{percent .Confidence 0}}</td><td>{{.Spins}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}{{end}}
+</section>
+{{end}}`))
+
+// WriteHTML renders the report as a self-contained HTML document.
+func (r *Report) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, r)
+}
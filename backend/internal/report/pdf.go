@@ -0,0 +1,371 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"lutexplorer/internal/lut"
+)
+
+// PDF page layout, in points. Text is set in Courier so tables line up
+// without embedding fonts or measuring glyphs.
+const (
+	pdfPageWidth  = 595.0 // A4
+	pdfPageHeight = 842.0
+	pdfMargin     = 40.0
+	pdfBodySize   = 8.0
+	pdfLineWidth  = 106 // Courier characters per line at pdfBodySize
+)
+
+// pdfLine is one line of report text.
+type pdfLine struct {
+	text      string
+	size      float64
+	bold      bool
+	pageBreak bool // Start a new page before the line
+}
+
+// WritePDF renders the report as a printable PDF document.
+func (r *Report) WritePDF(w io.Writer) error {
+	_, err := w.Write(renderPDF(r.textLines()))
+	return err
+}
+
+// textLines lays the report out as lines of monospaced text.
+func (r *Report) textLines() []pdfLine {
+	var lines []pdfLine
+	title := func(s string) { lines = append(lines, pdfLine{text: s, size: 16, bold: true}) }
+	heading := func(s string) { lines = append(lines, pdfLine{}, pdfLine{text: s, size: 10, bold: true}) }
+	text := func(format string, args ...interface{}) {
+		for _, l := range wrap(fmt.Sprintf(format, args...), pdfLineWidth) {
+			lines = append(lines, pdfLine{text: l, size: pdfBodySize})
+		}
+	}
+	table := func(header []string, rows [][]string) {
+		for _, l := range textTable(header, rows, 1) {
+			text("%s", l)
+		}
+	}
+	// checks lists compliance checks with the reason for a failure below it
+	checks := func(list []lut.ComplianceCheck) {
+		rows := make([][]string, len(list))
+		for i, c := range list {
+			rows[i] = []string{passFail(c.Passed), c.Severity, c.Name, c.Value, c.Expected}
+		}
+		aligned := textTable([]string{"Result", "Severity", "Check", "Value", "Expected"}, rows, 5)
+		text("%s", aligned[0])
+		text("%s", aligned[1])
+		for i, c := range list {
+			text("%s", aligned[i+2])
+			if !c.Passed && c.Reason != "" {
+				text("        %s", c.Reason)
+			}
+		}
+	}
+
+	title(r.Title)
+	text("")
+	text("Library:            %s", r.Library)
+	text("Generated:          %s", r.GeneratedAt.Format("2006-01-02 15:04:05 MST"))
+	text("Compliance profile: %s", r.Profile)
+	text("Compliance:         %s", passFail(r.AllPassed))
+	text("Simulation:         %d trials x %d spins per mode, seed %d", r.Options.Trials, r.Options.Spins, r.Options.Seed)
+
+	heading("Modes")
+	var rows [][]string
+	for _, m := range r.Modes {
+		compliance := ""
+		if m.Compliance != nil {
+			compliance = passFail(m.Compliance.Passed)
+		}
+		s := m.Statistics
+		rows = append(rows, []string{m.Mode, fmt.Sprint(s.Cost), percent(s.RTP, 4), percent(s.HitRate, 2),
+			multiple(s.MaxPayout), fmt.Sprint(s.Volatility), percent(m.Simulation.ActualRTP, 4), compliance})
+	}
+	table([]string{"Mode", "Cost", "RTP", "Hit rate", "Max payout", "Volatility", "Simulated RTP", "Compliance"}, rows)
+
+	if len(r.GlobalChecks) > 0 {
+		heading("Global checks")
+		checks(r.GlobalChecks)
+	}
+
+	heading("Win tiers")
+	rows = nil
+	for _, t := range r.WinTiers.Tiers {
+		from := multiple(t.Min)
+		if t.MaxWin {
+			from = "max win"
+		}
+		rows = append(rows, []string{t.Name, from})
+	}
+	table([]string{"Tier", "From"}, rows)
+
+	for _, m := range r.Modes {
+		lines = append(lines, pdfLine{text: "Mode: " + m.Mode, size: 14, bold: true, pageBreak: true})
+		s := m.Statistics
+
+		heading("Statistics")
+		table([]string{"Metric", "Value"}, [][]string{
+			{"Cost", fmt.Sprint(s.Cost)},
+			{"Outcomes", fmt.Sprint(s.TotalOutcomes)},
+			{"Total weight", fmt.Sprint(s.TotalWeight)},
+			{"RTP", percent(s.RTP, 4)},
+			{"Exact RTP", s.ExactRTP + " = " + s.ExactRTPDecimal},
+			{"Hit rate", percent(s.HitRate, 2)},
+			{"Zero payout rate", percent(s.ZeroPayoutRate, 2)},
+			{"Breakeven rate", percent(s.BreakevenRate, 2)},
+			{"Min / max payout", multiple(s.MinPayout) + " / " + multiple(s.MaxPayout)},
+			{"Mean / median payout", multiple(s.MeanPayout) + " / " + multiple(s.MedianPayout)},
+			{"Variance", fmt.Sprint(s.Variance)},
+			{"Std dev", fmt.Sprint(s.StdDev)},
+			{"Volatility (std dev / mean)", fmt.Sprint(s.Volatility)},
+			{"Cost-adjusted volatility", fmt.Sprint(s.CostAdjVolatility)},
+			{"Mean / median ratio", fmt.Sprint(s.MeanMedian)},
+		})
+
+		if len(s.WinTiers) > 0 {
+			heading("Win tiers")
+			rows = nil
+			for _, t := range s.WinTiers {
+				rows = append(rows, []string{t.Name, multiple(t.Min), fmt.Sprint(t.Outcomes),
+					percent(t.Probability, 4), t.Odds, percent(t.RTPContribution, 2)})
+			}
+			table([]string{"Tier", "From", "Outcomes", "Probability", "Odds", "RTP contribution"}, rows)
+		}
+
+		heading("Payout ranges")
+		rows = nil
+		for _, b := range s.PayoutBuckets {
+			rows = append(rows, []string{multiple(b.RangeStart), multiple(b.RangeEnd), fmt.Sprint(b.Count),
+				fmt.Sprint(b.Weight), percent(b.Probability, 4)})
+		}
+		table([]string{"From", "To", "Outcomes", "Weight", "Probability"}, rows)
+
+		heading("Payout distribution")
+		rows = nil
+		for _, d := range s.Distribution {
+			rows = append(rows, []string{multiple(d.Payout), fmt.Sprint(d.Count), fmt.Sprint(d.Weight),
+				percent(probability(d.Weight, s.TotalWeight), 6), d.Odds})
+		}
+		table([]string{"Payout", "Outcomes", "Weight", "Probability", "Odds"}, rows)
+		if m.OmittedRows > 0 {
+			text("%d more payouts not shown.", m.OmittedRows)
+		}
+
+		heading("Top payouts")
+		rows = nil
+		for _, p := range s.TopPayouts {
+			rows = append(rows, []string{fmt.Sprint(p.SimID), multiple(p.Payout), fmt.Sprint(p.Count),
+				fmt.Sprint(p.Weight), p.Odds})
+		}
+		table([]string{"Sim ID", "Payout", "Outcomes", "Weight", "Odds"}, rows)
+
+		if c := m.Compliance; c != nil {
+			heading("Compliance: " + passFail(c.Passed))
+			text("%d passed, %d failed, %d warning(s).", c.PassedCount, c.FailedCount, c.WarningCount)
+			checks(c.Checks)
+		}
+
+		sim := m.Simulation
+		heading("Simulation")
+		text("%d trials x %d spins at a bet of %v, seed %d.", sim.Config.Trials, sim.Config.Spins, sim.Config.Bet, sim.Config.Seed)
+		if d := sim.Diagnostics; d != nil {
+			text("")
+			table([]string{"Metric", "Simulated", "Std error", "95% CI", "99% CI"}, [][]string{
+				{"RTP", percent(d.RTP.Value, 4), percent(d.RTP.StdError, 4), interval(d.RTP.CI95, 4), interval(d.RTP.CI99, 4)},
+				{"Hit rate", percent(d.HitRate.Value, 4), percent(d.HitRate.StdError, 4), interval(d.HitRate.CI95, 4), interval(d.HitRate.CI99, 4)},
+			})
+			text("")
+			table([]string{"Metric", "Theoretical", "Expected 99% range", "In range"}, [][]string{
+				{"RTP", percent(d.TheoreticalRTP, 4), interval(d.ExpectedRTPRange, 4), yesNo(!d.RTPOutOfRange)},
+				{"Hit rate", percent(d.TheoreticalHitRate, 4), interval(d.ExpectedHitRateRange, 4), yesNo(!d.HitRateOutOfRange)},
+			})
+		}
+		text("")
+		rows = [][]string{{"Max win", multiple(sim.MaxWin)}}
+		for _, t := range sim.WinTiers {
+			rows = append(rows, []string{t.Name + " wins", fmt.Sprintf("%d (%s of spins)", t.Count, percent(t.Rate, 2))})
+		}
+		table([]string{"Metric", "Value"}, rows)
+		if len(sim.RTPatSpins) > 0 {
+			text("")
+			rows = nil
+			for _, p := range sim.RTPatSpins {
+				rows = append(rows, []string{fmt.Sprint(p.SpinCount), percent(p.SuccessRate, 2), interval(p.CI95, 2)})
+			}
+			table([]string{"Spins", "Trials with RTP >= " + percent(sim.Config.TargetRTP, 2), "95% CI"}, rows)
+		}
+		if d := sim.Diagnostics; d != nil && len(d.SpinsForPrecision) > 0 {
+			text("")
+			rows = nil
+			for _, p := range d.SpinsForPrecision {
+				rows = append(rows, []string{"+/- " + percent(p.Precision, 2), percent(p.Confidence, 0), fmt.Sprint(p.Spins)})
+			}
+			table([]string{"RTP precision", "Confidence", "Spins needed"}, rows)
+		}
+	}
+	return lines
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "YES"
+	}
+	return "NO"
+}
+
+// textTable aligns rows under a header, the first left columns to the left
+// and the others to the right.
+func textTable(header []string, rows [][]string, left int) []string {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	format := func(row []string) string {
+		var b strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			if i < left {
+				fmt.Fprintf(&b, "%-*s", widths[i], cell)
+			} else {
+				fmt.Fprintf(&b, "%*s", widths[i], cell)
+			}
+		}
+		return strings.TrimRight(b.String(), " ")
+	}
+
+	lines := []string{format(header)}
+	rule := make([]string, len(header))
+	for i, w := range widths {
+		rule[i] = strings.Repeat("-", w)
+	}
+	lines = append(lines, format(rule))
+	for _, row := range rows {
+		lines = append(lines, format(row))
+	}
+	return lines
+}
+
+// wrap breaks s into lines of at most width characters, at a space where
+// one is near the end, indenting continuation lines.
+func wrap(s string, width int) []string {
+	var lines []string
+	runes := []rune(s)
+	for len(runes) > width {
+		cut := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		runes = append([]rune("    "), []rune(strings.TrimLeft(string(runes[cut:]), " "))...)
+	}
+	return append(lines, string(runes))
+}
+
+// renderPDF lays lines out on A4 pages and returns the PDF file. Fonts are
+// the standard Courier faces every PDF reader provides.
+func renderPDF(lines []pdfLine) []byte {
+	var pages []string
+	var page strings.Builder
+	y := pdfPageHeight - pdfMargin
+	newPage := func() {
+		if page.Len() > 0 {
+			pages = append(pages, page.String())
+		}
+		page.Reset()
+		y = pdfPageHeight - pdfMargin
+	}
+	for _, l := range lines {
+		size := l.size
+		if size == 0 {
+			size = pdfBodySize
+		}
+		lead := size * 1.3
+		if (l.pageBreak && page.Len() > 0) || y-lead < pdfMargin {
+			newPage()
+		}
+		y -= lead
+		if l.text == "" {
+			continue
+		}
+		font := "F1"
+		if l.bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&page, "BT /%s %g Tf %g %.2f Td (%s) Tj ET\n", font, size, pdfMargin, y, pdfEscape(l.text))
+	}
+	pages = append(pages, page.String())
+
+	// Objects: 1 catalog, 2 page tree, 3-4 fonts, then a page and its
+	// content stream for each page
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, content := range pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfEscape escapes a PDF string literal. Characters outside printable
+// ASCII are replaced, as the fonts are not embedded.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '±':
+			b.WriteString("+/-")
+		case r == '≥':
+			b.WriteString(">=")
+		case r == '≤':
+			b.WriteString("<=")
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
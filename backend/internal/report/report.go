@@ -0,0 +1,219 @@
+// Package report builds math certification reports for a library: the
+// statistics, compliance results, payout distribution, top payouts and
+// simulation confidence intervals of every mode, rendered as a
+// self-contained HTML document or a printable PDF.
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/lut"
+	"stakergs"
+)
+
+// Report formats.
+const (
+	FormatHTML = "html"
+	FormatPDF  = "pdf"
+)
+
+// Report defaults.
+const (
+	DefaultTitle     = "Math Certification Report"
+	DefaultTargetRTP = 0.97
+)
+
+// MaxSyncSpins bounds the spins simulated per mode (spins × trials) for a
+// report rendered within a request. Larger reports run as background jobs.
+const MaxSyncSpins = 10_000_000
+
+// DefaultTestSpins are the spin counts the RTP success rate is reported at.
+var DefaultTestSpins = []int{100, 500, 1000}
+
+// Options configures a report. Zero values use the defaults.
+type Options struct {
+	Title            string  `json:"title"`
+	Profile          string  `json:"profile"`           // Compliance profile (default: the library's default)
+	Spins            int     `json:"spins"`             // Spins per simulation trial
+	Trials           int     `json:"trials"`            // Simulation trials per mode
+	Seed             int64   `json:"seed"`              // Simulation seed; 0 picks one, which the report shows
+	TargetRTP        float64 `json:"target_rtp"`        // RTP threshold for the success rates
+	TestSpins        []int   `json:"test_spins"`        // Spin counts to report the success rate at
+	DistributionRows int     `json:"distribution_rows"` // Rows of each payout distribution to include (0 = all)
+}
+
+// Validate checks the options and applies defaults.
+func (o *Options) Validate() error {
+	if o.Title == "" {
+		o.Title = DefaultTitle
+	}
+	if o.Spins == 0 {
+		o.Spins = common.DefaultSpins
+	}
+	if o.Spins < 0 || o.Spins > common.MaxSpins {
+		return fmt.Errorf("spins must be between 1 and %d", common.MaxSpins)
+	}
+	if o.Trials == 0 {
+		o.Trials = common.DefaultTrials
+	}
+	if o.Trials < 0 || o.Trials > common.MaxTrials {
+		return fmt.Errorf("trials must be between 1 and %d", common.MaxTrials)
+	}
+	if o.TargetRTP <= 0 {
+		o.TargetRTP = DefaultTargetRTP
+	}
+	if len(o.TestSpins) == 0 {
+		o.TestSpins = DefaultTestSpins
+	}
+	if o.DistributionRows < 0 {
+		return fmt.Errorf("distribution rows cannot be negative: %d", o.DistributionRows)
+	}
+	if o.Seed == 0 {
+		o.Seed = common.NewSeed()
+	}
+	return nil
+}
+
+// Report is everything a certification report shows.
+type Report struct {
+	Title        string                `json:"title"`
+	Library      string                `json:"library"`
+	GeneratedAt  time.Time             `json:"generated_at"`
+	Options      Options               `json:"options"`
+	Profile      string                `json:"profile"`
+	AllPassed    bool                  `json:"all_passed"`
+	WinTiers     *lut.WinTiers         `json:"win_tiers"`
+	Modes        []ModeReport          `json:"modes"`
+	GlobalChecks []lut.ComplianceCheck `json:"global_checks"`
+}
+
+// ModeReport is the part of a report about one mode.
+type ModeReport struct {
+	Mode       string                `json:"mode"`
+	Statistics *lut.Statistics       `json:"statistics"`
+	Compliance *lut.ComplianceResult `json:"compliance"`
+	Simulation *lut.SimulationResult `json:"simulation"`
+
+	// Distribution rows left out by Options.DistributionRows
+	OmittedRows int `json:"omitted_rows,omitempty"`
+}
+
+// Build analyzes, checks and simulates each of modes, in order. It stops
+// between simulation trials once ctx is cancelled and returns ctx.Err().
+// progress, if not nil, is called as trials of all modes complete.
+func Build(ctx context.Context, loader *lut.Loader, modes []string, opts Options, progress func(trialsDone, trials int)) (*Report, error) {
+	if len(modes) == 0 {
+		return nil, fmt.Errorf("no modes selected")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	profile, err := loader.ComplianceProfile(opts.Profile)
+	if err != nil {
+		return nil, err
+	}
+	checker := lut.NewComplianceCheckerWithProfile(profile)
+
+	report := &Report{
+		Title:       opts.Title,
+		Library:     loader.LibraryDir(),
+		GeneratedAt: time.Now().UTC(),
+		Options:     opts,
+		WinTiers:    loader.WinTiers(),
+	}
+
+	tables := make(map[string]*stakergs.LookupTable, len(modes))
+	for i, mode := range modes {
+		table, err := loader.GetMode(mode)
+		if err != nil {
+			return nil, err
+		}
+		tables[table.Mode] = table
+
+		stats := loader.Analyzer().Analyze(table)
+		omitted := 0
+		if opts.DistributionRows > 0 && len(stats.Distribution) > opts.DistributionRows {
+			omitted = len(stats.Distribution) - opts.DistributionRows
+			stats.Distribution = stats.Distribution[:opts.DistributionRows]
+		}
+
+		// Use mode cost as bet, same as the API
+		bet := table.Cost
+		if bet <= 0 {
+			bet = 1.0
+		}
+		simulation, err := loader.Simulator().RunSimulationContext(ctx, table, lut.SimulationConfig{
+			Spins:     opts.Spins,
+			Trials:    opts.Trials,
+			Bet:       bet,
+			TargetRTP: opts.TargetRTP,
+			TestSpins: opts.TestSpins,
+			Seed:      opts.Seed,
+		}, modeProgress(progress, i, len(modes)))
+		if err != nil {
+			return nil, err
+		}
+
+		report.Modes = append(report.Modes, ModeReport{
+			Mode:        table.Mode,
+			Statistics:  stats,
+			Simulation:  simulation,
+			OmittedRows: omitted,
+		})
+	}
+
+	compliance := checker.CheckAllModes(tables)
+	report.Profile = compliance.Profile
+	report.AllPassed = compliance.AllPassed
+	report.GlobalChecks = compliance.GlobalChecks
+	for i := range report.Modes {
+		report.Modes[i].Compliance = compliance.ModeResults[report.Modes[i].Mode]
+	}
+	return report, nil
+}
+
+// percent formats a rate as a percentage with the given decimal places.
+func percent(v float64, places int) string {
+	return fmt.Sprintf("%.*f%%", places, v*100)
+}
+
+// interval formats a confidence interval of rates as percentages.
+func interval(ci lut.ConfidenceInterval, places int) string {
+	return percent(ci.Low, places) + " - " + percent(ci.High, places)
+}
+
+// multiple formats a payout in base bets.
+func multiple(v float64) string {
+	return fmt.Sprintf("%.2fx", v)
+}
+
+// probability is weight's share of total.
+func probability(weight, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(weight) / float64(total)
+}
+
+// passFail labels a check result.
+func passFail(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+// modeProgress reports the trials of the i-th of n modes as progress of the
+// whole report, or returns nil if progress is nil.
+func modeProgress(progress func(trialsDone, trials int), i, n int) func(trialsDone, trials int) {
+	if progress == nil {
+		return nil
+	}
+	return func(trialsDone, trials int) {
+		progress(i*trials+trialsDone, n*trials)
+	}
+}
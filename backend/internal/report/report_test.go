@@ -0,0 +1,191 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"lutexplorer/internal/lut"
+	"lutexplorer/internal/testutil"
+)
+
+// baseCSV is a 96% RTP table.
+const baseCSV = `0,348250,0
+1,300000,50
+2,150000,100
+3,151750,200
+4,40000,500
+5,8000,1000
+6,1500,2500
+7,400,5000
+8,90,10000
+9,10,100000
+`
+
+// bonusCSV is a bonus table bought for 100 base bets.
+const bonusCSV = `0,50,2000
+1,40,10000
+2,10,30000
+`
+
+// loadLibrary writes and loads a library with a base and a bonus mode.
+func loadLibrary(t *testing.T) *lut.Loader {
+	t.Helper()
+
+	loader := lut.NewLoaderFromLibrary(testutil.WriteLibrary(t,
+		testutil.Mode{Name: "base", CSV: baseCSV},
+		testutil.Mode{Name: "bonus", Cost: 100, CSV: bonusCSV},
+	))
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return loader
+}
+
+func buildReport(t *testing.T, opts Options) *Report {
+	t.Helper()
+	loader := loadLibrary(t)
+	report, err := Build(context.Background(), loader, loader.ListModes(), opts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestBuild(t *testing.T) {
+	report := buildReport(t, Options{Spins: 200, Trials: 10, Seed: 42, DistributionRows: 5})
+
+	if len(report.Modes) != 2 || report.Modes[0].Mode != "base" || report.Modes[1].Mode != "bonus" {
+		t.Fatalf("unexpected modes %+v", report.Modes)
+	}
+	for _, m := range report.Modes {
+		if m.Statistics == nil || m.Compliance == nil || m.Simulation == nil {
+			t.Fatalf("mode %s is missing a section", m.Mode)
+		}
+		if m.Simulation.Config.Seed != 42 || m.Simulation.Config.Bet != m.Statistics.Cost {
+			t.Errorf("mode %s simulated with %+v", m.Mode, m.Simulation.Config)
+		}
+	}
+
+	base := report.Modes[0]
+	if len(base.Statistics.Distribution) != 5 || base.OmittedRows != 5 {
+		t.Errorf("kept %d distribution rows and omitted %d, want 5 and 5", len(base.Statistics.Distribution), base.OmittedRows)
+	}
+	if report.Title != DefaultTitle || report.Profile == "" {
+		t.Errorf("unexpected title %q or profile %q", report.Title, report.Profile)
+	}
+
+	loader := loadLibrary(t)
+	if _, err := Build(context.Background(), loader, []string{"missing"}, Options{}, nil); err == nil {
+		t.Error("expected an error for a missing mode")
+	}
+	if _, err := Build(context.Background(), loader, []string{"base"}, Options{Profile: "missing"}, nil); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+	if _, err := Build(context.Background(), loader, []string{"base"}, Options{Trials: -1}, nil); err == nil {
+		t.Error("expected an error for negative trials")
+	}
+}
+
+func TestBuild_Progress(t *testing.T) {
+	loader := loadLibrary(t)
+	var last, total int
+	_, err := Build(context.Background(), loader, loader.ListModes(), Options{Spins: 100, Trials: 10, Seed: 1}, func(done, trials int) {
+		if done < last {
+			t.Errorf("progress went back from %d to %d", last, done)
+		}
+		last, total = done, trials
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != 20 || total != 20 {
+		t.Errorf("final progress %d/%d, want 20/20 across both modes", last, total)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	report := buildReport(t, Options{Title: "Q&A <Game>", Spins: 200, Trials: 10, Seed: 1, DistributionRows: 5})
+
+	var buf bytes.Buffer
+	if err := report.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		"<title>Q&amp;A &lt;Game&gt;</title>",
+		"Mode: base", "Mode: bonus",
+		"Payout distribution", "Top payouts", "Compliance:", "95% CI", "Expected 99% range",
+		"5 more payouts not shown.",
+		report.Modes[1].Statistics.ExactRTPDecimal,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report is missing %q", want)
+		}
+	}
+
+	// Self-contained: no scripts, stylesheets or images to fetch
+	for _, external := range []string{"<script", "<link", "<img", "src="} {
+		if strings.Contains(html, external) {
+			t.Errorf("HTML report references an external resource: %s", external)
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	report := buildReport(t, Options{Spins: 200, Trials: 10, Seed: 1})
+
+	var buf bytes.Buffer
+	if err := report.WritePDF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+
+	// startxref points at the xref table, whose entries point at their objects
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	for i, e := range entries {
+		offset, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q", i+1, pdf[offset:offset+10])
+		}
+	}
+
+	// The title page plus one page per mode at least
+	if pages := bytes.Count(pdf, []byte("/Type /Page /Parent")); pages < 3 {
+		t.Errorf("expected at least 3 pages, got %d", pages)
+	}
+	if !bytes.Contains(pdf, []byte("(Mode: bonus) Tj")) {
+		t.Error("PDF is missing the bonus mode")
+	}
+}
+
+func TestPDFEscape(t *testing.T) {
+	if got := pdfEscape(`RTP (95%) ≥ 1\2 ± é`); got != `RTP \(95%\) >= 1\\2 +/- ?` {
+		t.Errorf("pdfEscape = %q", got)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	lines := wrap("one two three four", 10)
+	want := []string{"one two", "    three", "    four"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("wrap = %q, want %q", lines, want)
+	}
+}
@@ -0,0 +1,121 @@
+// Package sampling provides O(1) weighted outcome sampling shared by the
+// simulator, CrowdSim and the LGS.
+package sampling
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// Alias is a Walker alias table built with Vose's method. A draw picks a
+// column uniformly and then either the column itself or its alias, so
+// sampling costs the same for ten rows as for ten million.
+//
+// The table is built and sampled in integer arithmetic: every index is drawn
+// with probability exactly weight/total, with no float rounding and no modulo
+// bias, for any total that fits in a uint64.
+type Alias struct {
+	total uint64   // Sum of the weights; each column holds this much
+	prob  []uint64 // Share of column i kept by i itself, in [0, total]
+	alias []uint32
+}
+
+// NewAlias builds an alias table over integer weights. A table whose weights
+// sum to zero has nothing to sample and panics on Sample. Weights summing past
+// a uint64 panic here; the loader rejects such tables on load.
+func NewAlias(weights []uint64) *Alias {
+	var total, carry uint64
+	for _, w := range weights {
+		total, carry = bits.Add64(total, w, 0)
+		if carry != 0 {
+			panic("sampling: total weight overflows uint64")
+		}
+	}
+	if total == 0 {
+		return &Alias{}
+	}
+
+	n := len(weights)
+	a := &Alias{
+		total: total,
+		prob:  make([]uint64, n),
+		alias: make([]uint32, n),
+	}
+
+	// Scale weights by n so each column holds exactly total, then pair each
+	// under-full column with an over-full one that tops it up. Scaled weights
+	// can exceed 64 bits, so the high halves are kept separately.
+	hi := make([]uint64, n)
+	small := make([]uint32, 0, n)
+	large := make([]uint32, 0, n)
+	for i, w := range weights {
+		hi[i], a.prob[i] = bits.Mul64(w, uint64(n))
+		if hi[i] == 0 && a.prob[i] < total {
+			small = append(small, uint32(i))
+		} else {
+			large = append(large, uint32(i))
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		l := large[len(large)-1]
+
+		// scaled[l] -= total - scaled[s]; scaled[s] < total so this never underflows
+		a.alias[s] = l
+		var borrow uint64
+		a.prob[l], borrow = bits.Sub64(a.prob[l], total-a.prob[s], 0)
+		hi[l] -= borrow
+		if hi[l] == 0 && a.prob[l] < total {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+
+	// Integer arithmetic is exact, so whatever is left holds exactly total
+	for _, i := range large {
+		a.prob[i] = total
+	}
+	for _, i := range small {
+		a.prob[i] = total
+	}
+
+	return a
+}
+
+// Len returns the number of columns, or 0 for a table with no weight.
+func (a *Alias) Len() int {
+	return len(a.prob)
+}
+
+// Total returns the sum of the weights the table was built from.
+func (a *Alias) Total() uint64 {
+	return a.total
+}
+
+// Sample returns a random index with probability exactly weight/total.
+func (a *Alias) Sample(rng *rand.Rand) int {
+	if len(a.prob) == 0 {
+		panic("sampling: table has no weight to sample")
+	}
+	i := Uint64n(rng, uint64(len(a.prob)))
+	if Uint64n(rng, a.total) < a.prob[i] {
+		return int(i)
+	}
+	return int(a.alias[i])
+}
+
+// Uint64n returns a uniform integer in [0, n) for n > 0. It uses Lemire's
+// multiply-and-reject method, so unlike rng.Uint64() % n it has no modulo bias.
+func Uint64n(rng *rand.Rand, n uint64) uint64 {
+	hi, lo := bits.Mul64(rng.Uint64(), n)
+	if lo < n {
+		// Reject the few products that would make low results more likely
+		threshold := -n % n
+		for lo < threshold {
+			hi, lo = bits.Mul64(rng.Uint64(), n)
+		}
+	}
+	return hi
+}
@@ -0,0 +1,240 @@
+package sampling
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"stakergs"
+)
+
+// aliasMass reconstructs the mass of each index from the table, scaled by n:
+// an exact table gives every index weight*n.
+func aliasMass(a *Alias) []*big.Int {
+	mass := make([]*big.Int, a.Len())
+	for i := range mass {
+		mass[i] = new(big.Int)
+	}
+	for i, p := range a.prob {
+		mass[i].Add(mass[i], new(big.Int).SetUint64(p))
+		mass[a.alias[i]].Add(mass[a.alias[i]], new(big.Int).SetUint64(a.total-p))
+	}
+	return mass
+}
+
+func TestNewAlias_PreservesProbabilities(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	small := []uint64{0, 1, 1e12, 3, 0, 7, 1e6}
+	for i := 0; i < 200; i++ {
+		small = append(small, uint64(rng.Intn(1000)))
+	}
+	// Scaled by n these no longer fit in 64 bits
+	huge := []uint64{1 << 62, 1<<62 - 1, 3, 1 << 61, 12345}
+
+	for _, weights := range [][]uint64{small, huge} {
+		n := uint64(len(weights))
+		for i, got := range aliasMass(NewAlias(weights)) {
+			want := new(big.Int).Mul(new(big.Int).SetUint64(weights[i]), new(big.Int).SetUint64(n))
+			if got.Cmp(want) != 0 {
+				t.Errorf("index %d: mass %s, want %s", i, got, want)
+			}
+		}
+	}
+}
+
+func TestUint64n_IsUniform(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	// A bound just above 2^63 rejects almost half the raw draws, so a
+	// modulo would put nearly all results in the lower half
+	const bound = 1<<63 + 1<<62
+	var low int
+	const draws = 100_000
+	for i := 0; i < draws; i++ {
+		v := Uint64n(rng, bound)
+		if v >= bound {
+			t.Fatalf("Uint64n returned %d, want < %d", v, uint64(bound))
+		}
+		if v < bound/2 {
+			low++
+		}
+	}
+	// Half the draws, within five standard deviations
+	if diff := math.Abs(float64(low) - draws/2); diff > 5*math.Sqrt(draws/4) {
+		t.Errorf("%d of %d draws in the lower half, want about %d", low, draws, draws/2)
+	}
+}
+
+func TestQuantize_KeepsEveryOutcomeReachable(t *testing.T) {
+	weights, ok := quantize([]float64{1e300, 1e-300, 0, 1e300})
+	if !ok {
+		t.Fatal("expected finite weights to quantize")
+	}
+	if weights[1] == 0 {
+		t.Error("expected a tiny positive weight to stay reachable")
+	}
+	if weights[2] != 0 {
+		t.Errorf("zero weight quantized to %d", weights[2])
+	}
+	if weights[0] != weights[3] || weights[0] != quantizedTotal/2 {
+		t.Errorf("equal weights quantized to %d and %d, want %d", weights[0], weights[3], uint64(quantizedTotal/2))
+	}
+}
+
+func TestNewBiased_FallsBackOnUnusableWeights(t *testing.T) {
+	table := &stakergs.LookupTable{Mode: "base", Outcomes: []stakergs.Outcome{
+		{Weight: 1}, {SimID: 1, Weight: 1, Payout: 100}, {SimID: 2, Weight: 1, Payout: 1e9},
+	}}
+	for _, weights := range [][]float64{{0, 0}, {1, math.Inf(1)}, {math.NaN(), 1}} {
+		if _, ok := quantize(weights); ok {
+			t.Errorf("quantize(%v) succeeded", weights)
+		}
+	}
+
+	// A huge bias overflows the biased weights of the 10,000,000x payout
+	rng := rand.New(rand.NewSource(1))
+	for _, bias := range []float64{1000, math.NaN()} {
+		sampler := NewBiased(table, bias)
+		for i := 0; i < 100; i++ {
+			sampler.Sample(rng)
+		}
+	}
+}
+
+func TestSampler_MatchesWeights(t *testing.T) {
+	table := &stakergs.LookupTable{Outcomes: []stakergs.Outcome{
+		{SimID: 0, Weight: 600, Payout: 0},
+		{SimID: 1, Weight: 250, Payout: 50},
+		{SimID: 2, Weight: 100, Payout: 200},
+		{SimID: 3, Weight: 45, Payout: 1000},
+		{SimID: 4, Weight: 5, Payout: 10000},
+	}}
+	sampler := New(table)
+	rng := rand.New(rand.NewSource(7))
+
+	const draws = 1_000_000
+	counts := make([]int, len(table.Outcomes))
+	for i := 0; i < draws; i++ {
+		counts[sampler.Sample(rng).SimID]++
+	}
+
+	for i, o := range table.Outcomes {
+		p := float64(o.Weight) / 1000
+		// Five standard deviations of the binomial count
+		tolerance := 5 * math.Sqrt(draws*p*(1-p))
+		if diff := math.Abs(float64(counts[i]) - draws*p); diff > tolerance {
+			t.Errorf("simID %d: drawn %d times, want %.0f ± %.0f", o.SimID, counts[i], draws*p, tolerance)
+		}
+	}
+}
+
+func TestCache_InvalidatesOnChange(t *testing.T) {
+	table := &stakergs.LookupTable{Mode: "Base", Outcomes: []stakergs.Outcome{{Weight: 1}, {SimID: 1, Weight: 1}}}
+	cache := NewCache()
+
+	first := cache.Get(table, 0)
+	if cache.Get(table, 0) != first {
+		t.Error("expected the cached sampler on the second call")
+	}
+	if cache.Get(table, 1.5) == first {
+		t.Error("expected a separate sampler per bias")
+	}
+
+	cache.Invalidate("base")
+	if cache.Get(table, 0) == first {
+		t.Error("expected a new sampler after Invalidate")
+	}
+
+	reloaded := &stakergs.LookupTable{Mode: "base", Outcomes: table.Outcomes}
+	if cache.Get(reloaded, 0) == cache.Get(table, 0) {
+		t.Error("expected a new sampler for a replaced table")
+	}
+}
+
+func TestCache_BoundsBiasedSamplers(t *testing.T) {
+	base := &stakergs.LookupTable{Mode: "base", Outcomes: []stakergs.Outcome{{Weight: 1}, {SimID: 1, Weight: 1, Payout: 200}}}
+	bonus := &stakergs.LookupTable{Mode: "bonus", Outcomes: base.Outcomes}
+	cache := NewCache()
+
+	unbiased := cache.Get(base, 0)
+	cache.Get(bonus, 0)
+	kept := cache.Get(base, 0.5)
+	for i := 1; i <= 100; i++ {
+		cache.Get(base, float64(i)/50)
+		cache.Get(base, 0.5) // Recently used, so never evicted
+	}
+
+	if got, want := cache.Len(), 2+MaxBiasedPerMode; got != want {
+		t.Errorf("cache holds %d samplers after 100 biases, want %d", got, want)
+	}
+	if cache.Get(base, 0) != unbiased {
+		t.Error("unbiased sampler was evicted")
+	}
+	if cache.Get(base, 0.5) != kept {
+		t.Error("recently used biased sampler was evicted")
+	}
+}
+
+// benchWeights returns n weights shaped like a real table: mostly small
+// weights with a long tail of rare outcomes.
+func benchWeights(n int) []uint64 {
+	rng := rand.New(rand.NewSource(1))
+	weights := make([]uint64, n)
+	for i := range weights {
+		weights[i] = uint64(1e9 * math.Pow(rng.Float64(), 6))
+	}
+	return weights
+}
+
+// cumulativeSample is the binary search the alias table replaces, for comparison.
+func cumulativeSample(cumWeights []uint64, rng *rand.Rand) int {
+	r := Uint64n(rng, cumWeights[len(cumWeights)-1])
+	return sort.Search(len(cumWeights), func(i int) bool { return cumWeights[i] > r })
+}
+
+var benchSizes = []int{1_000, 100_000, 1_000_000, 5_000_000}
+
+func BenchmarkAlias_Sample(b *testing.B) {
+	for _, n := range benchSizes {
+		a := NewAlias(benchWeights(n))
+		b.Run(fmt.Sprintf("rows=%d", n), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				a.Sample(rng)
+			}
+		})
+	}
+}
+
+func BenchmarkCumulative_Sample(b *testing.B) {
+	for _, n := range benchSizes {
+		weights := benchWeights(n)
+		var cumulative uint64
+		for i, w := range weights {
+			cumulative += w
+			weights[i] = cumulative
+		}
+		b.Run(fmt.Sprintf("rows=%d", n), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cumulativeSample(weights, rng)
+			}
+		})
+	}
+}
+
+func BenchmarkNewAlias(b *testing.B) {
+	for _, n := range benchSizes {
+		weights := benchWeights(n)
+		b.Run(fmt.Sprintf("rows=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				NewAlias(weights)
+			}
+		})
+	}
+}
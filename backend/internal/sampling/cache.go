@@ -0,0 +1,123 @@
+package sampling
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"stakergs"
+)
+
+// MaxBiasedPerMode bounds the biased samplers cached per mode. Each holds an
+// alias table as large as the mode's table, and any bias can be requested,
+// so only the most recently used ones are kept.
+const MaxBiasedPerMode = 4
+
+// Cache keeps one sampler per mode and bias so alias tables are built once
+// rather than on every spin. The unbiased sampler of a mode is always kept;
+// biased ones are evicted least recently used first. Entries must be
+// invalidated when a mode's weights change; a table replaced by a reload is
+// also detected by identity.
+type Cache struct {
+	mu      sync.RWMutex
+	entries map[cacheKey]*cacheEntry
+	clock   atomic.Uint64 // Orders uses for eviction
+}
+
+type cacheKey struct {
+	mode string
+	bias float64
+}
+
+type cacheEntry struct {
+	table   *stakergs.LookupTable
+	sampler *Sampler
+	used    atomic.Uint64 // clock value of the last use
+}
+
+// NewCache creates an empty sampler cache.
+func NewCache() *Cache {
+	return &Cache{entries: make(map[cacheKey]*cacheEntry)}
+}
+
+// Get returns the sampler for a table with the given payout bias (0 for none),
+// building it on first use.
+func (c *Cache) Get(table *stakergs.LookupTable, bias float64) *Sampler {
+	key := cacheKey{mode: strings.ToLower(table.Mode), bias: bias}
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && entry.table == table {
+		entry.used.Store(c.clock.Add(1))
+		return entry.sampler
+	}
+
+	// Build outside the lock: large tables take a while and other modes
+	// should not wait. A concurrent build of the same key is harmless.
+	sampler := NewBiased(table, bias)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok && entry.table == table {
+		entry.used.Store(c.clock.Add(1))
+		return entry.sampler
+	}
+	if bias != 0 {
+		c.evictBiased(key)
+	}
+	entry = &cacheEntry{table: table, sampler: sampler}
+	entry.used.Store(c.clock.Add(1))
+	c.entries[key] = entry
+	return sampler
+}
+
+// evictBiased drops the least recently used biased samplers of key's mode
+// until there is room for key. The caller must hold the write lock.
+func (c *Cache) evictBiased(key cacheKey) {
+	for {
+		var oldest cacheKey
+		var oldestUse uint64
+		count := 0
+		for k, e := range c.entries {
+			if k.mode != key.mode || k.bias == 0 || k == key {
+				continue
+			}
+			count++
+			if use := e.used.Load(); count == 1 || use < oldestUse {
+				oldest, oldestUse = k, use
+			}
+		}
+		if count < MaxBiasedPerMode {
+			return
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// Len returns the number of cached samplers.
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Invalidate drops all samplers of a mode.
+func (c *Cache) Invalidate(mode string) {
+	mode = strings.ToLower(mode)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.mode == mode {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateAll clears the cache.
+func (c *Cache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]*cacheEntry)
+}
@@ -0,0 +1,31 @@
+package sampling
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mrand "math/rand"
+)
+
+// cryptoSource is a math/rand source backed by crypto/rand.
+type cryptoSource struct{}
+
+func (cryptoSource) Int63() int64 {
+	return int64(cryptoSource{}.Uint64() >> 1)
+}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("sampling: crypto/rand failed: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// Seed is a no-op: a crypto source cannot be seeded.
+func (cryptoSource) Seed(int64) {}
+
+// NewCryptoRand returns a generator that draws from crypto/rand, for runs that
+// need unpredictable rather than reproducible randomness.
+func NewCryptoRand() *mrand.Rand {
+	return mrand.New(cryptoSource{})
+}
@@ -0,0 +1,110 @@
+package sampling
+
+import (
+	"math"
+	"math/rand"
+
+	"stakergs"
+)
+
+// Sampler draws outcomes of a lookup table.
+type Sampler struct {
+	outcomes []stakergs.Outcome
+	alias    *Alias
+}
+
+// New creates a sampler that draws outcomes in proportion to their weights.
+func New(lut *stakergs.LookupTable) *Sampler {
+	weights := make([]uint64, len(lut.Outcomes))
+	for i, o := range lut.Outcomes {
+		weights[i] = o.Weight
+	}
+	return &Sampler{outcomes: lut.Outcomes, alias: NewAlias(weights)}
+}
+
+// NewBiased creates a sampler with payout-based bias.
+// bias = 0: normal sampling
+// bias > 0: favors higher payouts AND reduces zero payouts (losses)
+// bias < 0: favors lower payouts AND increases zero payouts (losses)
+//
+// Formula:
+//   - For payout = 0: weight * 0.5^bias (positive bias reduces losses)
+//   - For payout > 0: weight * (1 + payout/100)^bias
+//
+// Examples with bias = +5:
+//   - 0x payout: 0.5^5 = 0.03 (97% fewer losses!)
+//   - 1x payout: 2^5 = 32x more likely
+//   - 10x payout: 11^5 = 161051x more likely
+//
+// If the biased weights cannot be scaled, because they overflow or vanish,
+// the sampler falls back to the unbiased weights.
+func NewBiased(lut *stakergs.LookupTable, bias float64) *Sampler {
+	if bias == 0 {
+		return New(lut)
+	}
+	weights, ok := quantize(BiasedWeights(lut.Outcomes, bias))
+	if !ok {
+		return New(lut)
+	}
+	return &Sampler{outcomes: lut.Outcomes, alias: NewAlias(weights)}
+}
+
+// quantizedTotal is the integer total biased weights are scaled to. It leaves
+// headroom below 2^64 for the rounding of each weight.
+const quantizedTotal = 1 << 62
+
+// quantize scales float weights to integers summing to about quantizedTotal,
+// so each weight keeps the 53-bit precision of its float however large the
+// float total. Any positive weight stays at least 1, so no outcome becomes
+// unreachable. It returns false if the total is zero, infinite or NaN, as
+// the weights then have no usable proportions.
+func quantize(weights []float64) ([]uint64, bool) {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 || math.IsInf(total, 0) || math.IsNaN(total) {
+		return nil, false
+	}
+
+	result := make([]uint64, len(weights))
+	for i, w := range weights {
+		if w > 0 {
+			result[i] = max(uint64(math.Round(w/total*quantizedTotal)), 1)
+		}
+	}
+	return result, true
+}
+
+// BiasedWeights returns the outcome weights with the payout bias of NewBiased applied.
+func BiasedWeights(outcomes []stakergs.Outcome, bias float64) []float64 {
+	weights := make([]float64, len(outcomes))
+	for i, o := range outcomes {
+		if o.Payout == 0 {
+			// For zero payouts (losses): use 0.5 as base
+			// Positive bias reduces losses, negative bias increases them
+			weights[i] = float64(o.Weight) * math.Pow(0.5, bias)
+		} else {
+			// For winning payouts: use (1 + payout/100) as base
+			payoutMultiplier := 1.0 + float64(o.Payout)/100.0
+			weights[i] = float64(o.Weight) * math.Pow(payoutMultiplier, bias)
+		}
+	}
+	return weights
+}
+
+// Sample returns a random outcome. The sampler holds no mutable state, so it
+// can be shared between goroutines that each use their own rng.
+func (s *Sampler) Sample(rng *rand.Rand) stakergs.Outcome {
+	return s.outcomes[s.alias.Sample(rng)]
+}
+
+// SampleIndex returns the index in the table's outcomes of a random outcome.
+func (s *Sampler) SampleIndex(rng *rand.Rand) int {
+	return s.alias.Sample(rng)
+}
+
+// Len returns the number of outcomes the sampler draws from.
+func (s *Sampler) Len() int {
+	return len(s.outcomes)
+}
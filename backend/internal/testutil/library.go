@@ -0,0 +1,53 @@
+// Package testutil provides fixtures shared by the tests of several packages.
+package testutil
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stakergs"
+)
+
+// Mode is one mode of a test library.
+type Mode struct {
+	Name string
+	Cost float64 // 0 means 1
+	CSV  string  // Lookup table rows: sim_id,weight,payout
+}
+
+// WriteLibrary writes a library folder with the given modes to a temp dir
+// and returns its path. Each mode's table is written to
+// publish_files/lookUpTable_<name>_0.csv and listed in index.json.
+func WriteLibrary(t testing.TB, modes ...Mode) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	publish := filepath.Join(dir, "publish_files")
+	if err := os.MkdirAll(publish, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var index stakergs.GameIndex
+	for _, m := range modes {
+		file := "lookUpTable_" + m.Name + "_0.csv"
+		if err := os.WriteFile(filepath.Join(publish, file), []byte(m.CSV), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cost := m.Cost
+		if cost == 0 {
+			cost = 1
+		}
+		index.Modes = append(index.Modes, stakergs.ModeConfig{Name: m.Name, Cost: cost, Weights: file})
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(publish, "index.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
@@ -37,6 +37,13 @@ const (
 	MsgOptimizerProgress MessageType = "optimizer_progress"
 	MsgOptimizerComplete MessageType = "optimizer_complete"
 	MsgOptimizerError    MessageType = "optimizer_error"
+
+	// Background job messages
+	MsgJobStarted   MessageType = "job_started"
+	MsgJobProgress  MessageType = "job_progress"
+	MsgJobComplete  MessageType = "job_complete"
+	MsgJobFailed    MessageType = "job_failed"
+	MsgJobCancelled MessageType = "job_cancelled"
 )
 
 // Message represents a WebSocket message sent to clients.
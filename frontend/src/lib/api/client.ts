@@ -5,12 +5,19 @@ import type {
 	IndexInfo,
 	ModeSummary,
 	Statistics,
+	WinTiers,
+	ReportOptions,
+	ExportFormat,
+	ExportDataset,
 	DistributionItem,
 	Outcome,
 	CompareResponse,
 	EventLoadResult,
 	EventInfo,
 	LGSAuthResponse,
+	LGSConfig,
+	LGSJurisdictionPreset,
+	LGSCurrency,
 	LGSPlayResponse,
 	LGSSessionsResponse,
 	LGSStatsResponse,
@@ -25,6 +32,10 @@ import type {
 	CrowdSimResult,
 	CrowdSimCompareResult,
 	CrowdSimPresetInfo,
+	CrowdSimAnalyticResponse,
+	CrowdSimRuinConfig,
+	CrowdSimRuinResult,
+	CrowdSimRuinCompareResult,
 	OptimizerConfig,
 	OptimizerResult,
 	BucketDistributionResponse,
@@ -33,12 +44,26 @@ import type {
 	ConvexHealthResponse,
 	ConvexModeInfoResponse,
 	ModeAnalysis,
-	GenerateConfigsAnalysis
+	GenerateConfigsAnalysis,
+	JobInfo,
+	JobResult
 } from './types';
 
 const DEFAULT_BASE_URL = 'http://localhost:7754';
 const DEFAULT_LGS_URL = 'http://localhost:7754';
 
+// reportQuery encodes report options as the query string of the report endpoints
+function reportQuery(options: ReportOptions): string {
+	const params = new URLSearchParams();
+	for (const mode of options.modes ?? []) params.append('mode', mode);
+	for (const key of ['format', 'profile', 'spins', 'trials', 'seed', 'rows', 'title'] as const) {
+		const value = options[key];
+		if (value !== undefined && value !== '') params.set(key, String(value));
+	}
+	const query = params.toString();
+	return query ? `?${query}` : '';
+}
+
 class LutApiClient {
 	private baseUrl: string;
 
@@ -77,6 +102,32 @@ class LutApiClient {
 		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/stats`);
 	}
 
+	async getWinTiers(): Promise<WinTiers> {
+		return this.fetch('/api/win-tiers');
+	}
+
+	// The report is a document, not JSON, so callers link or download it.
+	// Reports simulating more than 10M spins per mode must run as a job.
+	getReportUrl(options: ReportOptions = {}): string {
+		return `${this.baseUrl}/api/report${reportQuery(options)}`;
+	}
+
+	/**
+	 * Build a report as a background job; once it completes, getReportJobUrl renders it
+	 */
+	async reportJob(options: ReportOptions = {}): Promise<JobInfo> {
+		return this.postJson(`/api/report/jobs${reportQuery(options)}`, {});
+	}
+
+	getReportJobUrl(id: string, format: ReportOptions['format'] = 'html'): string {
+		return `${this.baseUrl}/api/report/jobs/${encodeURIComponent(id)}?format=${format}`;
+	}
+
+	// Exports are files with every sim_id, so callers link or download them
+	getExportUrl(mode: string, dataset: ExportDataset, format: ExportFormat = 'csv'): string {
+		return `${this.baseUrl}/api/mode/${encodeURIComponent(mode)}/export/${dataset}?format=${format}`;
+	}
+
 	async getModeDistribution(mode: string): Promise<DistributionItem[]> {
 		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/distribution`);
 	}
@@ -180,8 +231,8 @@ class LutApiClient {
 	}
 
 	// Wallet endpoints (RGS-compatible)
-	async lgsAuthenticate(sessionID: string, language: string = 'en'): Promise<LGSAuthResponse> {
-		return this.lgsPost('/wallet/authenticate', { sessionID, language });
+	async lgsAuthenticate(sessionID: string, language: string = 'en', currency?: string): Promise<LGSAuthResponse> {
+		return this.lgsPost('/wallet/authenticate', { sessionID, language, currency });
 	}
 
 	async lgsPlay(options: {
@@ -194,7 +245,7 @@ class LutApiClient {
 			sessionID: options.sessionID,
 			mode: options.mode,
 			amount: options.amount,
-			currency: options.currency || 'USD'
+			currency: options.currency
 		});
 	}
 
@@ -203,6 +254,26 @@ class LutApiClient {
 	}
 
 	// LGS utility endpoints
+	async lgsConfig(): Promise<LGSConfig> {
+		return this.lgsGet('/lgs/config');
+	}
+
+	async lgsSetConfig(config: Partial<LGSConfig>): Promise<LGSConfig> {
+		return this.lgsPost('/lgs/config', config);
+	}
+
+	async lgsJurisdictions(): Promise<LGSJurisdictionPreset[]> {
+		return this.lgsGet('/lgs/jurisdictions');
+	}
+
+	async lgsSetJurisdiction(sessionID: string, jurisdiction: string): Promise<{ success: boolean; name: string }> {
+		return this.lgsPost('/lgs/jurisdiction', { sessionID, jurisdiction });
+	}
+
+	async lgsCurrencies(): Promise<LGSCurrency[]> {
+		return this.lgsGet('/lgs/currencies');
+	}
+
 	async lgsSessions(): Promise<LGSSessionsResponse> {
 		return this.lgsGet('/lgs/sessions');
 	}
@@ -243,7 +314,7 @@ class LutApiClient {
 			mode: options.mode,
 			amount: options.amount,
 			spins: options.spins,
-			currency: options.currency || 'USD'
+			currency: options.currency
 		});
 	}
 
@@ -279,6 +350,15 @@ class LutApiClient {
 		return this.lgsPost('/lgs/rtp-bias', { sessionID, bias });
 	}
 
+	async lgsSetSeed(sessionID: string, seed: number, draw: number = 0): Promise<{
+		success: boolean;
+		sessionID: string;
+		seed: number;
+		draw: number;
+	}> {
+		return this.lgsPost('/lgs/seed', { sessionID, seed, draw });
+	}
+
 	async lgsGetRTPBias(sessionID: string): Promise<{
 		sessionID: string;
 		bias: number;
@@ -346,6 +426,28 @@ class LutApiClient {
 		return this.postJson('/api/crowdsim/compare', { modes, config: config || {} });
 	}
 
+	async crowdsimAnalytic(
+		mode: string,
+		config?: Partial<CrowdSimConfig>,
+		options?: { max_bins?: number; compare?: boolean }
+	): Promise<CrowdSimAnalyticResponse> {
+		return this.postJson(`/api/crowdsim/${encodeURIComponent(mode)}/analytic`, {
+			config: config || {},
+			...options
+		});
+	}
+
+	async crowdsimRuin(mode: string, config?: Partial<CrowdSimRuinConfig>): Promise<CrowdSimRuinResult> {
+		return this.postJson(`/api/crowdsim/${encodeURIComponent(mode)}/ruin`, config || {});
+	}
+
+	async crowdsimRuinCompare(
+		modes: string[],
+		config?: Partial<CrowdSimRuinConfig>
+	): Promise<CrowdSimRuinCompareResult> {
+		return this.postJson('/api/crowdsim/ruin/compare', { modes, config: config || {} });
+	}
+
 	async crowdsimPresets(): Promise<CrowdSimPresetInfo[]> {
 		return this.fetch('/api/crowdsim/presets');
 	}
@@ -576,6 +678,81 @@ class LutApiClient {
 	}> {
 		return this.postJson('/api/convexopt/validate', request);
 	}
+
+	// ============ Background Job Methods ============
+
+	/**
+	 * Start a full simulation as a background job; progress arrives as job_progress messages
+	 */
+	async simulateJob(mode: string, config?: {
+		spins?: number;
+		trials?: number;
+		target_rtp?: number;
+		test_spins?: number[];
+		test_weights?: number[];
+		seed?: number;
+		rtp_precision?: number;
+	}): Promise<JobInfo> {
+		return this.postJson(`/api/mode/${encodeURIComponent(mode)}/simulate/jobs`, config || {});
+	}
+
+	// Runs a simulation and downloads every trial's summary as a file
+	async exportSimulation(mode: string, format: ExportFormat = 'csv', config?: {
+		spins?: number;
+		trials?: number;
+		target_rtp?: number;
+		seed?: number;
+	}): Promise<Blob> {
+		const response = await fetch(
+			`${this.baseUrl}/api/mode/${encodeURIComponent(mode)}/simulate/export?format=${format}`,
+			{
+				method: 'POST',
+				headers: {
+					'Content-Type': 'application/json'
+				},
+				body: JSON.stringify(config || {})
+			}
+		);
+		if (!response.ok) {
+			const data: ApiResponse<unknown> = await response.json();
+			throw new Error(data.error || 'Unknown error');
+		}
+		return response.blob();
+	}
+
+	async crowdsimJob(mode: string, config?: Partial<CrowdSimConfig>): Promise<JobInfo> {
+		return this.postJson(`/api/crowdsim/${encodeURIComponent(mode)}/jobs`, config || {});
+	}
+
+	/**
+	 * Start a bucket optimization as a background job; takes the bucket-optimize request
+	 */
+	async bucketOptimizeJob(mode: string, config: Record<string, unknown>): Promise<JobInfo> {
+		return this.postJson(`/api/optimizer/${encodeURIComponent(mode)}/optimize-job`, config);
+	}
+
+	async listJobs(): Promise<JobInfo[]> {
+		return this.fetch('/api/jobs');
+	}
+
+	async getJob(id: string): Promise<JobInfo> {
+		return this.fetch(`/api/jobs/${encodeURIComponent(id)}`);
+	}
+
+	async getJobResult<T = unknown>(id: string): Promise<JobResult<T>> {
+		return this.fetch(`/api/jobs/${encodeURIComponent(id)}/result`);
+	}
+
+	async cancelJob(id: string): Promise<JobInfo> {
+		const response = await fetch(`${this.baseUrl}/api/jobs/${encodeURIComponent(id)}`, {
+			method: 'DELETE'
+		});
+		const data: ApiResponse<JobInfo> = await response.json();
+		if (!data.success) {
+			throw new Error(data.error || 'Unknown error');
+		}
+		return data.data as JobInfo;
+	}
 }
 
 export const api = new LutApiClient();
@@ -65,6 +65,48 @@ export interface Statistics {
 	// Cost-adjusted metrics (for bonus modes)
 	breakeven_rate: number; // P(payout >= cost)
 	cost_adj_volatility: number; // StdDev / Cost
+	// Exact RTP from integer arithmetic
+	exact_rtp: string; // Reduced fraction "num/den"
+	exact_rtp_decimal: string; // Decimal with 12 places
+	// Frequency and RTP contribution per win tier
+	win_tiers: WinTierStats[];
+}
+
+export interface WinTier {
+	name: string;
+	min?: number; // Smallest win in base bets
+	max_win?: boolean; // Wins paying the mode's maximum
+}
+
+export interface WinTiers {
+	tiers: WinTier[]; // Lowest first; the first is big wins, the second mega wins
+}
+
+// Query options for GET /api/report; unset fields use the server defaults
+export interface ReportOptions {
+	format?: 'html' | 'pdf';
+	modes?: string[]; // Default: all modes
+	profile?: string;
+	spins?: number;
+	trials?: number;
+	seed?: number;
+	rows?: number; // Distribution rows per mode (0 = all)
+	title?: string;
+}
+
+// Formats and datasets of the export endpoints
+export type ExportFormat = 'csv' | 'xlsx' | 'parquet';
+export type ExportDataset = 'distribution' | 'buckets' | 'outcomes';
+
+export interface WinTierStats {
+	name: string;
+	min: number; // The mode's max payout for the max-win tier
+	max_win?: boolean;
+	outcomes: number;
+	weight: number;
+	probability: number;
+	odds: string;
+	rtp_contribution: number;
 }
 
 export interface Outcome {
@@ -114,13 +156,47 @@ export interface LGSBalance {
 	currency: string;
 }
 
+export interface LGSBetMode {
+	name: string;
+	id: string;
+	cost: number;
+	min_bet: number;
+	max_bet: number;
+	target_rtp: number;
+}
+
 export interface LGSConfig {
 	gameID: string;
+	currency: string;
 	minBet: number;
 	maxBet: number;
 	stepBet: number;
 	defaultBetLevel: number;
 	betLevels: number[];
+	betModes: Record<string, LGSBetMode>;
+	buyFeatureModes: string[];
+	jurisdiction: Record<string, boolean | number>;
+	currencies?: Record<string, LGSCurrency>;
+}
+
+export interface LGSCurrency {
+	code: string;
+	name: string;
+	symbol: string;
+	decimals: number;
+	social?: boolean;
+	defaultBalance?: number;
+	minBet?: number;
+	maxBet?: number;
+	stepBet?: number;
+	defaultBetLevel?: number;
+	betLevels?: number[];
+}
+
+export interface LGSJurisdictionPreset {
+	name: string;
+	description: string;
+	jurisdiction: Record<string, boolean | number>;
 }
 
 export interface LGSModeInfo {
@@ -141,6 +217,8 @@ export interface LGSRound {
 	active: boolean;
 	mode: string;
 	event?: unknown;
+	seed: number;
+	draw: number;
 }
 
 export interface LGSAuthResponse {
@@ -159,6 +237,7 @@ export interface LGSSessionSummary {
 	sessionID: string;
 	balance: number;
 	currency: string;
+	balances: Record<string, number>;
 	totalBets: number;
 	totalWins: number;
 	totalWagered: number;
@@ -171,6 +250,9 @@ export interface LGSSessionSummary {
 	lastActivity: string;
 	forcedOutcomes: Record<string, number>;
 	rtpBias: number;
+	jurisdiction?: string;
+	seed: number;
+	draws: number;
 }
 
 export interface LGSAggregateStats {
@@ -221,6 +303,7 @@ export interface LGSBatchPlayResponse {
 	maxWin: number;
 	bigWins: number;
 	megaWins: number;
+	winTiers: Record<string, number>; // Wins per tier, in the highest tier reached
 	balance: LGSBalance;
 	rounds?: LGSBatchPlayRound[];
 	durationMs: number;
@@ -268,7 +351,12 @@ export type WSMessageType =
 	| 'lgs_session_update'
 	| 'lgs_sessions_update'
 	| 'crowdsim_progress'
-	| 'optimizer_progress';
+	| 'optimizer_progress'
+	| 'job_started'
+	| 'job_progress'
+	| 'job_complete'
+	| 'job_failed'
+	| 'job_cancelled';
 
 export interface WSMessage {
 	type: WSMessageType;
@@ -355,6 +443,8 @@ export interface ComplianceSummary {
 
 export interface ComplianceResult {
 	mode: string;
+	profile: string;
+	disabled_checks?: string[];
 	passed: boolean;
 	passed_count: number;
 	failed_count: number;
@@ -365,6 +455,8 @@ export interface ComplianceResult {
 
 export interface AllModesComplianceResult {
 	all_passed: boolean;
+	profile: string;
+	disabled_checks?: string[];
 	mode_results: Record<string, ComplianceResult>;
 	global_checks: ComplianceCheck[];
 }
@@ -386,11 +478,101 @@ export interface CrowdSimConfig {
 	spins_per_session: number;
 	initial_balance: number;
 	bet_amount: number;
-	big_win_threshold: number;
+	big_win_threshold: number; // Stake multiple; 0 = the game's big-win tier
 	danger_threshold: number;
 	use_crypto_rng: boolean;
 	streaming_mode: boolean;
 	parallel_workers: number;
+	seed: number; // 0 = random; the result echoes the seed used
+	strategies?: CrowdSimStrategyConfig[]; // Player behaviour mix; empty = flat bets for every spin
+	population?: CrowdSimPopulationSegment[]; // Player segments; empty = every player uses the config's balance and bet
+	journey?: CrowdSimBonusBuy[]; // Chance per spin of playing another mode, when the balance covers it
+}
+
+export interface CrowdSimBonusBuy {
+	mode: string;
+	rate: number; // 0-1
+}
+
+export interface CrowdSimModeStats {
+	mode: string;
+	cost: number; // Relative to the simulated mode
+	players: number;
+	spins: number;
+	spin_share: number; // Percent
+	wagered: number;
+	wager_share: number; // Percent
+	won: number;
+	theoretical_rtp: number;
+	actual_rtp: number;
+	rtp_contribution: number; // Adds up to the result's actual_rtp
+}
+
+export type CrowdSimDistributionType = 'fixed' | 'uniform' | 'lognormal' | 'pareto';
+
+export interface CrowdSimDistribution {
+	type: CrowdSimDistributionType;
+	value?: number; // fixed
+	min?: number; // uniform lower bound, pareto scale; a floor for every type
+	max?: number; // uniform upper bound; a cap for every type
+	median?: number; // lognormal
+	sigma?: number; // lognormal
+	alpha?: number; // pareto
+}
+
+export interface CrowdSimPopulationSegment {
+	name: string;
+	share?: number;
+	initial_balance?: CrowdSimDistribution;
+	bet_amount?: CrowdSimDistribution;
+	spins_per_session?: CrowdSimDistribution; // Capped at the config's spins_per_session
+}
+
+export interface CrowdSimSegmentStats {
+	name: string;
+	players: number;
+	avg_initial_balance: number;
+	avg_bet_amount: number;
+	avg_spins: number;
+	actual_rtp: number;
+	final_pop: number;
+	balance_stats: CrowdSimBalanceStats;
+	peak_stats: CrowdSimPeakStats;
+	drawdown_stats: CrowdSimDrawdownStats;
+	volatility_profile: CrowdSimVolatilityProfile;
+}
+
+export type CrowdSimStopReason = 'session_end' | 'stop_win' | 'stop_loss' | 'bust' | 'time_limit';
+
+export type CrowdSimProgression = 'flat' | 'martingale' | 'paroli';
+
+export interface CrowdSimStrategyConfig {
+	name: string;
+	share?: number;
+	stop_win?: number;
+	stop_loss?: number;
+	stop_on_bust?: boolean;
+	progression?: CrowdSimProgression;
+	progression_factor?: number;
+	max_bet_multiple?: number;
+	bonus_buys?: CrowdSimBonusBuy[];
+	session_minutes?: number;
+	spins_per_minute?: number;
+}
+
+export interface CrowdSimStrategyStats {
+	name: string;
+	players: number;
+	final_pop: number;
+	avg_final_balance: number;
+	median_final_balance: number;
+	avg_spins: number;
+	avg_wagered: number;
+	actual_rtp: number;
+	avg_max_bet: number;
+	avg_bonus_buys: number;
+	avg_max_drawdown: number;
+	stop_reasons: Partial<Record<CrowdSimStopReason, number>>; // Percent of players
 }
 
 export interface CrowdSimBalanceBucket {
@@ -461,6 +643,12 @@ export interface CrowdSimPlayerSummary {
 	is_profitable: boolean;
 	hit_big_win: boolean;
 	actual_rtp: number;
+	strategy?: string;
+	segment?: string;
+	stop_reason: CrowdSimStopReason;
+	spins: number;
+	initial_balance: number;
+	bet_amount: number;
 }
 
 export type CrowdSimVolatilityProfile = 'low' | 'medium' | 'high';
@@ -501,6 +689,16 @@ export interface CrowdSimResult {
 	volatility_profile: CrowdSimVolatilityProfile;
 	composite_score: number;
 
+	// Per-strategy breakdown (when the config has strategies)
+	strategy_stats?: CrowdSimStrategyStats[];
+
+	// Per-segment breakdown (when the config has a population)
+	segment_stats?: CrowdSimSegmentStats[];
+
+	// Per-mode breakdown (when players buy bonuses or follow a journey)
+	mode_stats?: CrowdSimModeStats[];
+	blended_rtp?: number; // RTP expected from the stakes in each mode
+
 	// Detailed Data
 	player_summaries?: CrowdSimPlayerSummary[];
 }
@@ -522,6 +720,68 @@ export interface CrowdSimPresetInfo {
 	config: CrowdSimConfig;
 }
 
+export interface CrowdSimReturnBucket {
+	range_start: number;
+	range_end: number;
+	probability: number;
+}
+
+export interface CrowdSimAnalyticResult {
+	mode: string;
+	spins_per_session: number;
+	initial_balance: number;
+	theoretical_rtp: number;
+	duration_ms: number;
+	grid_step: number;
+	bins: number;
+	exact: boolean;
+	final_pop: number;
+	pop_curve: number[];
+	balance_curve: CrowdSimBalanceCurvePoint[];
+	balance_stats: CrowdSimBalanceStats;
+	return_distribution: CrowdSimReturnBucket[];
+}
+
+export interface CrowdSimPoPComparison {
+	final_deviation: number;
+	max_deviation: number;
+	max_deviation_spin: number;
+	mean_abs_deviation: number;
+}
+
+export interface CrowdSimAnalyticResponse {
+	analytic: CrowdSimAnalyticResult;
+	simulation?: CrowdSimResult;
+	comparison?: CrowdSimPoPComparison;
+}
+
+export interface CrowdSimRuinConfig {
+	initial_balance: number;
+	bet_amount: number;
+	max_spins: number;
+	max_states: number;
+}
+
+export interface CrowdSimRuinResult {
+	mode: string;
+	config: CrowdSimRuinConfig;
+	duration_ms: number;
+	resolution: number;
+	states: number;
+	exact: boolean;
+	ruin_probability: number;
+	survival_probability: number;
+	expected_spins: number;
+	converged: boolean;
+	median_spins_to_ruin: number;
+	survival_curve: { spin: number; survival: number }[];
+}
+
+export interface CrowdSimRuinCompareResult {
+	results: CrowdSimRuinResult[];
+	ranking: CrowdSimRankedResult[];
+}
+
 export interface CrowdSimProgress {
 	mode: string;
 	players_complete: number;
@@ -979,3 +1239,32 @@ export interface GenerateConfigsAnalysis {
 	is_bonus_mode: boolean;
 	suggested_void_buckets?: VoidSuggestion[]; // Suggestions for voiding when RTP unreachable
 }
+
+// Background jobs (simulation, CrowdSim and optimizer runs)
+export type JobKind = 'simulation' | 'crowdsim' | 'optimizer' | 'report';
+export type JobStatus = 'running' | 'completed' | 'failed' | 'cancelled';
+
+export interface JobProgress {
+	done: number;
+	total: number;
+	percent: number;
+	detail?: unknown; // Kind-specific, e.g. the optimizer's current RTP
+}
+
+// Payload of job_* WebSocket messages
+export interface JobInfo {
+	id: string;
+	kind: JobKind;
+	mode: string;
+	status: JobStatus;
+	progress: JobProgress;
+	error?: string;
+	created_at: string;
+	finished_at?: string;
+	elapsed_ms: number;
+}
+
+export interface JobResult<T> {
+	job: JobInfo;
+	result: T;
+}
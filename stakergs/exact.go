@@ -0,0 +1,166 @@
+package stakergs
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+	"strconv"
+)
+
+// ErrWeightOverflow is returned when the sum of outcome weights does not fit in a uint64.
+var ErrWeightOverflow = errors.New("total weight overflows uint64")
+
+// ErrZeroTotalWeight is returned for a table no outcome of which can be drawn.
+var ErrZeroTotalWeight = errors.New("total weight is zero")
+
+// CheckedTotalWeight returns the sum of all outcome weights,
+// or ErrWeightOverflow if the sum does not fit in a uint64.
+func (lut *LookupTable) CheckedTotalWeight() (uint64, error) {
+	var total, carry uint64
+	for _, o := range lut.Outcomes {
+		total, carry = bits.Add64(total, o.Weight, 0)
+		if carry != 0 {
+			return 0, ErrWeightOverflow
+		}
+	}
+	return total, nil
+}
+
+// ExactStats holds exact integer aggregates of a lookup table.
+// Payouts are in cents (multiplier * 100), as stored in the LUT.
+type ExactStats struct {
+	TotalWeight *big.Int // Sum of weights
+	WinWeight   *big.Int // Sum of weights with payout > 0
+	PayoutSum   *big.Int // Sum of weight * payout
+	PayoutSqSum *big.Int // Sum of weight * payout^2
+	Cost        *big.Rat // Mode cost as an exact decimal (1 if cost <= 0)
+}
+
+// ExactStats computes exact aggregates in a single pass using 192-bit accumulators.
+// Unlike TotalWeight and float64 accumulation, the result never wraps or rounds.
+func (lut *LookupTable) ExactStats() *ExactStats {
+	var total, win, sum, sumSq uint192
+	for _, o := range lut.Outcomes {
+		p := uint64(o.Payout)
+		total.add(o.Weight)
+		if p > 0 {
+			win.add(o.Weight)
+			sum.addMul(o.Weight, p)
+			sumSq.addMul3(o.Weight, p, p)
+		}
+	}
+
+	return &ExactStats{
+		TotalWeight: total.bigInt(),
+		WinWeight:   win.bigInt(),
+		PayoutSum:   sum.bigInt(),
+		PayoutSqSum: sumSq.bigInt(),
+		Cost:        ExactCost(lut.Cost),
+	}
+}
+
+// ExactCost converts a mode cost to an exact rational using its shortest
+// decimal representation, so 1.5 becomes 3/2 rather than the nearest binary fraction.
+// Costs <= 0 are treated as 1, matching RTP.
+func ExactCost(cost float64) *big.Rat {
+	if cost <= 0 {
+		return big.NewRat(1, 1)
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(cost, 'g', -1, 64))
+	if !ok {
+		return new(big.Rat).SetFloat64(cost)
+	}
+	return r
+}
+
+// RTP returns the exact cost-adjusted RTP: PayoutSum / (TotalWeight * 100 * Cost).
+func (e *ExactStats) RTP() *big.Rat {
+	if e.TotalWeight.Sign() == 0 {
+		return new(big.Rat)
+	}
+	denom := new(big.Int).Mul(e.TotalWeight, big.NewInt(100))
+	rtp := new(big.Rat).SetFrac(e.PayoutSum, denom)
+	return rtp.Quo(rtp, e.Cost)
+}
+
+// HitRate returns the exact probability of a non-zero payout.
+func (e *ExactStats) HitRate() *big.Rat {
+	if e.TotalWeight.Sign() == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(e.WinWeight, e.TotalWeight)
+}
+
+// MeanPayout returns the exact expected payout as a multiplier (not cost-adjusted).
+func (e *ExactStats) MeanPayout() *big.Rat {
+	if e.TotalWeight.Sign() == 0 {
+		return new(big.Rat)
+	}
+	denom := new(big.Int).Mul(e.TotalWeight, big.NewInt(100))
+	return new(big.Rat).SetFrac(e.PayoutSum, denom)
+}
+
+// Variance returns the exact payout variance in multiplier units:
+// (W * sum(w*p^2) - sum(w*p)^2) / (W^2 * 100^2).
+func (e *ExactStats) Variance() *big.Rat {
+	if e.TotalWeight.Sign() == 0 {
+		return new(big.Rat)
+	}
+	num := new(big.Int).Mul(e.TotalWeight, e.PayoutSqSum)
+	num.Sub(num, new(big.Int).Mul(e.PayoutSum, e.PayoutSum))
+
+	denom := new(big.Int).Mul(e.TotalWeight, e.TotalWeight)
+	denom.Mul(denom, big.NewInt(10000))
+
+	return new(big.Rat).SetFrac(num, denom)
+}
+
+// uint192 is an unsigned 192-bit accumulator stored as little-endian words.
+// Weight * payout^2 fits in 128 bits, so millions of rows cannot overflow it.
+type uint192 [3]uint64
+
+// add128 adds the 128-bit value hi:lo.
+func (u *uint192) add128(hi, lo uint64) {
+	var carry uint64
+	u[0], carry = bits.Add64(u[0], lo, 0)
+	u[1], carry = bits.Add64(u[1], hi, carry)
+	u[2] += carry
+}
+
+// add adds x.
+func (u *uint192) add(x uint64) {
+	u.add128(0, x)
+}
+
+// addMul adds x * y.
+func (u *uint192) addMul(x, y uint64) {
+	hi, lo := bits.Mul64(x, y)
+	u.add128(hi, lo)
+}
+
+// addMul3 adds x * y * z, where x * y * z < 2^192.
+func (u *uint192) addMul3(x, y, z uint64) {
+	hi, lo := bits.Mul64(x, y)
+	h0, w0 := bits.Mul64(lo, z)
+	h1, l1 := bits.Mul64(hi, z)
+	w1, carry := bits.Add64(h0, l1, 0)
+	w2 := h1 + carry
+
+	var c uint64
+	u[0], c = bits.Add64(u[0], w0, 0)
+	u[1], c = bits.Add64(u[1], w1, c)
+	u[2] += w2 + c
+}
+
+// bigInt converts the accumulator to a big.Int.
+func (u *uint192) bigInt() *big.Int {
+	words := make([]big.Word, 0, 3*(64/bits.UintSize))
+	for _, w := range u {
+		if bits.UintSize == 64 {
+			words = append(words, big.Word(w))
+		} else {
+			words = append(words, big.Word(uint32(w)), big.Word(w>>32))
+		}
+	}
+	return new(big.Int).SetBits(words)
+}
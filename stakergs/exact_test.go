@@ -0,0 +1,67 @@
+package stakergs
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestCheckedTotalWeight_Overflow(t *testing.T) {
+	lut := &LookupTable{Outcomes: []Outcome{
+		{SimID: 0, Weight: math.MaxUint64 - 1, Payout: 0},
+		{SimID: 1, Weight: 2, Payout: 100},
+	}}
+
+	if _, err := lut.CheckedTotalWeight(); !errors.Is(err, ErrWeightOverflow) {
+		t.Errorf("expected ErrWeightOverflow, got %v", err)
+	}
+
+	lut.Outcomes[1].Weight = 1
+	total, err := lut.CheckedTotalWeight()
+	if err != nil || total != math.MaxUint64 {
+		t.Errorf("expected %d, got %d (err %v)", uint64(math.MaxUint64), total, err)
+	}
+}
+
+func TestExactStats_LargeWeights(t *testing.T) {
+	// Weights near 2e12 where float64 products lose the last digits
+	lut := &LookupTable{Cost: 1.5, Outcomes: []Outcome{
+		{SimID: 0, Weight: 1996456169598, Payout: 0},
+		{SimID: 1, Weight: 1996456169597, Payout: 150},
+		{SimID: 2, Weight: 3, Payout: 4294967295},
+	}}
+
+	exact := lut.ExactStats()
+
+	total := big.NewInt(0)
+	sum := big.NewInt(0)
+	sumSq := big.NewInt(0)
+	for _, o := range lut.Outcomes {
+		w := new(big.Int).SetUint64(o.Weight)
+		p := new(big.Int).SetUint64(uint64(o.Payout))
+		total.Add(total, w)
+		wp := new(big.Int).Mul(w, p)
+		sum.Add(sum, wp)
+		sumSq.Add(sumSq, wp.Mul(wp, p))
+	}
+
+	if exact.TotalWeight.Cmp(total) != 0 {
+		t.Errorf("total weight: got %s, want %s", exact.TotalWeight, total)
+	}
+	if exact.PayoutSum.Cmp(sum) != 0 {
+		t.Errorf("payout sum: got %s, want %s", exact.PayoutSum, sum)
+	}
+	if exact.PayoutSqSum.Cmp(sumSq) != 0 {
+		t.Errorf("payout square sum: got %s, want %s", exact.PayoutSqSum, sumSq)
+	}
+
+	// RTP = sum / (total * 100 * 3/2)
+	want := new(big.Rat).SetFrac(new(big.Int).Mul(sum, big.NewInt(2)), new(big.Int).Mul(total, big.NewInt(300)))
+	if exact.RTP().Cmp(want) != 0 {
+		t.Errorf("RTP: got %s, want %s", exact.RTP(), want)
+	}
+	if exact.Variance().Sign() <= 0 {
+		t.Errorf("expected positive variance, got %s", exact.Variance())
+	}
+}
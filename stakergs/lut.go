@@ -34,6 +34,7 @@ type Outcome struct {
 }
 
 // TotalWeight returns the sum of all outcome weights.
+// The sum wraps silently on overflow; use CheckedTotalWeight for untrusted tables.
 func (lut *LookupTable) TotalWeight() uint64 {
 	var total uint64
 	for _, o := range lut.Outcomes {
@@ -61,45 +62,16 @@ func (lut *LookupTable) SelectOutcome(rnd uint64) *Outcome {
 // RTP calculates the theoretical Return To Player as a decimal (e.g., 0.97 = 97%).
 // For modes with cost > 1, RTP is adjusted: RTP = rawRTP / cost
 // Example: bonus with cost=350x and avg payout 340.55x -> RTP = 340.55/350 = 0.9730 (97.30%)
+// The sum is computed exactly (see ExactStats) and rounded once to float64.
 func (lut *LookupTable) RTP() float64 {
-	totalWeight := lut.TotalWeight()
-	if totalWeight == 0 {
-		return 0
-	}
-
-	// Use float64 accumulation to handle large weights without overflow
-	var weightedPayoutSum float64
-	for _, o := range lut.Outcomes {
-		weightedPayoutSum += float64(o.Weight) * float64(o.Payout)
-	}
-
-	// Payout is multiplier * 100, so divide by 100 to get raw RTP
-	rawRTP := weightedPayoutSum / float64(totalWeight) / 100.0
-
-	// Adjust for cost: RTP = rawRTP / cost
-	cost := lut.Cost
-	if cost <= 0 {
-		cost = 1.0
-	}
-
-	return rawRTP / cost
+	rtp, _ := lut.ExactStats().RTP().Float64()
+	return rtp
 }
 
 // HitRate returns the probability of a winning outcome (payout > 0).
 func (lut *LookupTable) HitRate() float64 {
-	totalWeight := lut.TotalWeight()
-	if totalWeight == 0 {
-		return 0
-	}
-
-	var winWeight uint64
-	for _, o := range lut.Outcomes {
-		if o.Payout > 0 {
-			winWeight += o.Weight
-		}
-	}
-
-	return float64(winWeight) / float64(totalWeight)
+	hitRate, _ := lut.ExactStats().HitRate().Float64()
+	return hitRate
 }
 
 // MaxPayout returns the maximum payout multiplier * 100.